@@ -3,13 +3,16 @@ package handler
 import (
 	"backend/boundary/middleware"
 	"backend/boundary/presenter"
+	"backend/domain"
 	"backend/models"
 	"backend/usecase/agenda"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/nferruzzi/gormGIS"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -23,15 +26,18 @@ type AgendaController struct {
 // @Accept  json
 // @Produce  json
 // @Security BearerToken
+// @Param Idempotency-Key header string false "Replays the first response when a retry sends the same key"
 // @Param event body models.Event true "Event object to be created"
 // @Success 201 {object} presenter.IdResponse
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /events [post]
 func (a *AgendaController) createEvent(c *gin.Context) {
 	var event models.Event
-	if err := c.Bind(&event); err != nil {
+	if err := c.ShouldBindJSON(&event); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
@@ -46,24 +52,44 @@ func (a *AgendaController) createEvent(c *gin.Context) {
 
 func parseTime(c *gin.Context, key string, result *time.Time) error {
 	if out, err := time.Parse(time.RFC3339, c.Query(key)); err != nil {
-		return gin.Error{Err: err, Type: gin.ErrorTypeBind}
+		return domain.ErrBadRequest.WithMessage("invalid " + key).Wrap(err)
 	} else {
-		result = &out
+		*result = out
 		return nil
 	}
 }
 func parseFloat(c *gin.Context, key string, result *float64) error {
 	if out, err := strconv.ParseFloat(c.Query(key), 64); err != nil {
-		return gin.Error{
-			Err:  err,
-			Type: gin.ErrorTypeBind,
-		}
+		return domain.ErrBadRequest.WithMessage("invalid " + key).Wrap(err)
 	} else {
-		result = &out
+		*result = out
 		return nil
 	}
 }
 
+// parseMinimumPay reads the optional min_pay (minor currency units) and currency query parameters.
+func parseMinimumPay(c *gin.Context) (*models.MinimumPay, error) {
+	raw, ok := c.GetQuery("min_pay")
+	if !ok {
+		return nil, nil
+	}
+	amount, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, domain.ErrBadRequest.WithMessage("invalid min_pay").Wrap(err)
+	}
+	currency := strings.ToUpper(c.DefaultQuery("currency", "USD"))
+	return &models.MinimumPay{Amount: amount, Currency: currency}, nil
+}
+
+// parseApplicationStatus reads the optional status query parameter. An absent status matches every application.
+func parseApplicationStatus(c *gin.Context) (models.ApplicationStatus, error) {
+	var status models.ApplicationStatus
+	if err := status.UnmarshalJSON([]byte(strconv.Quote(c.Query("status")))); err != nil {
+		return "", domain.ErrInvalid.WithFields(domain.FieldError{Field: "status", Message: err.Error()})
+	}
+	return status, nil
+}
+
 // GetAllEvents returns all events within a specified time range and distance from a center point.
 // @Summary Get all events
 // @Description Returns all events within a specified time range and distance from a center point.
@@ -75,9 +101,11 @@ func parseFloat(c *gin.Context, key string, result *float64) error {
 // @Param lat query number true "latitude of search point"
 // @Param lon query number true "longitude of search point"
 // @Param distance_km query number true "Distance from the center point in kilometers"
+// @Param min_pay query integer false "Minimum guaranteed pay in minor currency units (e.g. cents)"
+// @Param currency query string false "ISO 4217 currency for min_pay" default(USD)
 // @Success 200 {array} models.Event
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /events [get]
 func (a *AgendaController) getEventsByFilter(c *gin.Context) {
 	var startTime, endTime time.Time
@@ -104,7 +132,12 @@ func (a *AgendaController) getEventsByFilter(c *gin.Context) {
 	if err := parseFloat(c, "distance_km", &distance); err != nil {
 		return
 	}
-	events, err := a.agendaService.GetAllEvents(c, startTime, endTime, centerPoint, distance)
+	minPay, err := parseMinimumPay(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	events, err := a.agendaService.GetAllEvents(c, startTime, endTime, centerPoint, distance, minPay)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
@@ -120,9 +153,10 @@ func (a *AgendaController) getEventsByFilter(c *gin.Context) {
 // @Security BearerToken
 // @Param id path string true "Event ID"
 // @Success 200 {object} models.Event
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Header 200 {string} ETag "Current version, for If-Match"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /events/{id} [get]
 func (a *AgendaController) getEvent(c *gin.Context) {
 	id, err := GetId(c)
@@ -138,6 +172,7 @@ func (a *AgendaController) getEvent(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	} else {
+		SetETag(c, profile.Version)
 		c.JSON(http.StatusOK, profile)
 	}
 }
@@ -145,31 +180,47 @@ func (a *AgendaController) getEvent(c *gin.Context) {
 // Update an event by ID
 // PATCH /events/:id
 // @Summary Update an event by ID
-// @Description Update an event by ID
+// @Description RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps, Producer
+// @Description and Applications cannot be changed.
 // @Tags Events
-// @Accept json
+// @Accept json,application/merge-patch+json
 // @Produce json
 // @Security BearerToken
 // @Param id path string true "Event ID"
-// @Param event body models.Event true "Event object"
+// @Param If-Match header string false "ETag of the version being patched"
+// @Param event body models.Event true "Merge patch of the event"
 // @Success 200 {object} models.Event
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
+// @Failure 415 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
 // @Router /events/{id} [patch]
 func (a *AgendaController) updateEvent(c *gin.Context) {
-	var event models.Event
-	if err := c.Bind(&event); err != nil {
+	id, err := GetId(c)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if profile, err := a.agendaService.UpdateEvent(c, event); err != nil {
+	version, err := IfMatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	patch, err := MergePatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := a.agendaService.PatchEvent(c, id, patch, version)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
-	} else {
-		c.JSON(http.StatusOK, profile)
 	}
+	SetETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
 }
 
 // Delete an event by ID
@@ -178,11 +229,13 @@ func (a *AgendaController) updateEvent(c *gin.Context) {
 // @Description Delete an event by ID
 // @Tags Events
 // @Param id path string true "Event ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Security BearerToken
 // @Success 204 "No Content"
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
 // @Router /events/{id} [delete]
 func (a *AgendaController) deleteEvent(c *gin.Context) {
 	id, err := GetId(c)
@@ -190,28 +243,167 @@ func (a *AgendaController) deleteEvent(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if err := a.agendaService.DeleteEvent(c, id); err != nil {
+	version, err := IfMatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := a.agendaService.DeleteEvent(c, id, version); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
 
+// @Summary Set an event's application form
+// @Description Replaces the questions applicants answer: text, choice, url, file (a link to the file) or number, each
+// @Description optionally required. Only a draft event's form can be edited. Once an application has answered the
+// @Description current version, an edit starts a new version and earlier applications keep the one they answered.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param form body models.ApplicationForm true "Questions"
+// @Success 200 {object} models.ApplicationForm
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /events/{id}/form [put]
+func (a *AgendaController) setForm(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var form models.ApplicationForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := a.agendaService.SetForm(c, id, form.Questions)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Get an event's application form
+// @Description The current version of the form, or the one an application answered when version is given.
+// @Tags Events
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param version query int false "Form version"
+// @Success 200 {object} models.ApplicationForm
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /events/{id}/form [get]
+func (a *AgendaController) getForm(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var version int
+	if raw := c.Query("version"); raw != "" {
+		if version, err = strconv.Atoi(raw); err != nil || version < 1 {
+			presenter.HandleErr(c, domain.ErrBadRequest.WithMessage("invalid version"))
+			return
+		}
+	}
+	form, err := a.agendaService.GetForm(c, id, version)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, form)
+}
+
+// @Summary Get an event's waitlist
+// @Description The waitlisted applications, first in line first. When an offer is declined, the first of them is
+// @Description offered the place automatically, with the event's offer_response_hours to answer.
+// @Tags Applications
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Success 200 {array} models.Application
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /events/{id}/waitlist [get]
+func (a *AgendaController) getWaitlist(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	applications, err := a.agendaService.GetWaitlist(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, applications)
+}
+
+// @Summary Set an event's waitlist
+// @Description Waitlists the listed applications in that order, in one transaction. Pending, unknown, rejected and
+// @Description waitlisted applications can be listed; waitlisted applications left out go back to pending.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param order body models.WaitlistOrder true "Application IDs, first in line first"
+// @Success 200 {array} models.Application
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /events/{id}/waitlist [put]
+func (a *AgendaController) setWaitlist(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var order models.WaitlistOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	applications, err := a.agendaService.SetWaitlist(c, id, order)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, applications)
+}
+
 // @Summary Create a new application
-// @Description Create a new application
+// @Description Create a new application. If the event has an application form, answers are keyed by question ID and
+// @Description checked against its current version, which the application records as form_version.
 // @Tags Applications
 // @Accept  json
 // @Produce  json
 // @Security BearerToken
+// @Param Idempotency-Key header string false "Replays the first response when a retry sends the same key"
 // @Param application body models.Application true "Application object to be created"
 // @Success 201 {object} presenter.IdResponse
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /applications [post]
 func (a *AgendaController) createApplication(c *gin.Context) {
 	var application models.Application
-	if err := c.Bind(&application); err != nil {
+	if err := c.ShouldBindJSON(&application); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
@@ -232,9 +424,10 @@ func (a *AgendaController) createApplication(c *gin.Context) {
 // @Security BearerToken
 // @Param id path string true "Application ID"
 // @Success 200 {object} models.Application
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Header 200 {string} ETag "Current version, for If-Match"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /applications/{id} [get]
 func (a *AgendaController) getApplication(c *gin.Context) {
 	id, err := GetId(c)
@@ -246,38 +439,65 @@ func (a *AgendaController) getApplication(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	} else {
+		SetETag(c, application.Version)
 		c.JSON(http.StatusOK, application)
 	}
 }
 
 // Update an application by ID
 // PATCH /applications/:id
-// @Summary Update an event by ID
-// @Description Update an event by ID
+// @Summary Update an application by ID
+// @Description RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps, EventRef,
+// @Description Performer, the form answers, the waitlist position and the offer deadline cannot be changed. The
+// @Description performer may only accept or decline an offer; every other status change is the producer's and fails
+// @Description with status_producer_only. Declining an offer offers the place to the first waitlisted application.
+// @Description Offering or accepting fails with booking_conflict when the performer, a member of it or an ensemble it
+// @Description belongs to is already offered or accepted for an event starting within four hours.
 // @Tags Applications
-// @Accept json
+// @Accept json,application/merge-patch+json
 // @Produce json
 // @Security BearerToken
 // @Param id path string true "Application ID"
-// @Param application body models.Application true "Application object"
+// @Param If-Match header string false "ETag of the version being patched"
+// @Param application body models.Application true "Merge patch of the application"
 // @Success 200 {object} models.Application
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
+// @Failure 415 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
 // @Router /applications/{id} [patch]
 func (a *AgendaController) updateApplication(c *gin.Context) {
-	var application models.Application
-	if err := c.Bind(&application); err != nil {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	version, err := IfMatch(c)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if profile, err := a.agendaService.UpdateApplication(c, application); err != nil {
+	patch, err := MergePatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	// Only the performer's members get past ApplicationModifier; the super user acts for the producer.
+	party := agenda.PartyProducer
+	if _, ok := FirebaseID(c); ok {
+		party = agenda.PartyPerformer
+	}
+	out, err := a.agendaService.PatchApplication(c, party, id, patch, version)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
-	} else {
-		c.JSON(http.StatusOK, profile)
 	}
+	SetETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
 }
 
 // Delete an application by ID
@@ -286,11 +506,13 @@ func (a *AgendaController) updateApplication(c *gin.Context) {
 // @Description Delete an application by ID
 // @Tags Applications
 // @Param id path string true "Application ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Security BearerToken
 // @Success 204 "No Content"
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
 // @Router /applications/{id} [delete]
 func (a *AgendaController) deleteApplication(c *gin.Context) {
 	id, err := GetId(c)
@@ -298,7 +520,12 @@ func (a *AgendaController) deleteApplication(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if err := a.agendaService.DeleteApplication(c, id); err != nil {
+	version, err := IfMatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := a.agendaService.DeleteApplication(c, id, version); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
@@ -313,9 +540,9 @@ func (a *AgendaController) deleteApplication(c *gin.Context) {
 // @Security BearerToken
 // @Param id path string true "Producer ID"
 // @Success 200 {object} []models.Event
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /producer/{id}/events [get]
 func (a *AgendaController) getEventsByProducer(c *gin.Context) {
 	id, err := GetId(c)
@@ -340,10 +567,13 @@ func (a *AgendaController) getEventsByProducer(c *gin.Context) {
 // @Produce json
 // @Security BearerToken
 // @Param id path string true "Event ID"
+// @Param status query string false "Only applications with this status" Enums(accepted, rejected, pending, offered, unknown, waitlisted, declined, expired)
+// @Param sort query string false "score ranks by the reviewers' average rubric score and includes it" Enums(score)
 // @Success 200 {object} []models.Application
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /event/{id}/applications [get]
 func (a *AgendaController) getApplicationsByEvent(c *gin.Context) {
 	id, err := GetId(c)
@@ -351,25 +581,149 @@ func (a *AgendaController) getApplicationsByEvent(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	}
-	applications, err := a.agendaService.GetApplicationsByEvent(c, id)
+	status, err := parseApplicationStatus(c)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
+	var applications []models.Application
+	switch sort := c.Query("sort"); sort {
+	case "":
+		applications, err = a.agendaService.GetApplicationsByEvent(c, id)
+	case "score":
+		applications, err = a.agendaService.RankApplicationsByEvent(c, id)
+	default:
+		err = domain.ErrInvalid.WithFields(domain.FieldError{Field: "sort", Message: fmt.Sprintf("invalid value %q. Allowed: score", sort)})
+	}
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if status != "" {
+		filtered := make([]models.Application, 0, len(applications))
+		for _, application := range applications {
+			if application.Status == status {
+				filtered = append(filtered, application)
+			}
+		}
+		applications = filtered
+	}
 	c.JSON(http.StatusOK, applications)
 }
 
+// @Summary Change the status of many applications
+// @Description Moves the listed applications of the event to one status in a single transaction. Every item is checked
+// @Description like a single update and fails on its own; the results say which were updated, already had the status
+// @Description or failed and why. message is an optional Go template rendered with .Event, .Performer, .Application
+// @Description and .PreviousStatus and sent to each performer whose status changed. Notifications go out in the
+// @Description background once the change has been committed. offer_expires_at sets the response deadline of offers,
+// @Description including ones already offered; unanswered offers expire at their deadline and cannot change after.
+// @Description Items whose act is already booked around the event's time fail with booking_conflict.
+// @ID change-application-statuses
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param Idempotency-Key header string false "Replays the first response when a retry sends the same key"
+// @Param id path string true "Event ID"
+// @Param change body models.BulkStatusChange true "Status, applications and optional message template"
+// @Success 200 {object} models.BulkStatusReport
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /events/{id}/applications/status [post]
+func (a *AgendaController) changeApplicationStatuses(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var change models.BulkStatusChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	report, err := a.agendaService.ChangeApplicationStatuses(c, id, change)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Export Applications by Event ID
+// @Description Streams the applications submitted to an event, with their performers' profiles, as CSV, JSON or XLSX
+// @ID export-applications-by-event-id
+// @Tags Applications
+// @Produce text/csv
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param format query string false "File format, csv by default" Enums(csv, json, xlsx)
+// @Param status query string false "Only applications with this status" Enums(accepted, rejected, pending, offered, unknown, waitlisted, declined, expired)
+// @Success 200 {array} presenter.ApplicationExport
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /events/{id}/applications/export [get]
+func (a *AgendaController) exportApplicationsByEvent(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	status, err := parseApplicationStatus(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	format, ok := presenter.ParseExportFormat(c.Query("format"))
+	if !ok {
+		presenter.HandleErr(c, domain.ErrInvalid.WithFields(
+			domain.FieldError{Field: "format", Message: "must be one of csv, json, xlsx"},
+		))
+		return
+	}
+	// Look the event up first: once the file has started there is no way left to answer with a 404.
+	if _, err := a.agendaService.GetEvent(c, id); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="applications-%s.%s"`, id, format))
+	c.Status(http.StatusOK)
+	exporter, err := presenter.NewApplicationExporter(format, c.Writer)
+	if err == nil {
+		err = a.agendaService.EachApplicationByEvent(c, id, status, func(application models.Application) error {
+			return exporter.Write(presenter.NewApplicationExport(application))
+		})
+	}
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err != nil {
+		// The status line is already out, so all that is left is to cut the file short and say why in the log.
+		log.Printf("export of applications to event %s: %v", id, err)
+		c.Abort()
+	}
+}
+
 // @Summary Get Applications by Performer ID
-// @Description Returns the applications submitted to an event
+// @Description Returns the applications submitted to an event. Offers carry the offer_expires_at deadline to answer by.
 // @ID get-applications-by-performer-id
 // @Tags Applications
 // @Produce json
 // @Security BearerToken
 // @Param id path string true "Performer ID"
 // @Success 200 {object} []models.Application
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /performer/{id}/applications [get]
 func (a *AgendaController) getApplicationsByPerformer(c *gin.Context) {
 	id, err := GetId(c)
@@ -391,9 +745,9 @@ func (a *AgendaController) getApplicationsByPerformer(c *gin.Context) {
 // @Produce  json
 // @Security BasicAuth
 // @Success 201 {object} presenter.IdResponse
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /tag/{name} [post]
 func (a *AgendaController) createTag(c *gin.Context) {
 	name := c.Param("name")
@@ -413,11 +767,12 @@ func (a *AgendaController) createTag(c *gin.Context) {
 // @Tags Tags
 // @Produce  json
 // @Security BasicAuth
+// @Param Idempotency-Key header string false "Replays the first response when a retry sends the same key"
 // @Param event body models.Event true "Event object to be created"
 // @Success 201 {object} presenter.IdResponse
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /tag/{name} [delete]
 func (a *AgendaController) deleteTag(c *gin.Context) {
 	name := c.Param("name")
@@ -437,18 +792,25 @@ func RegisterAgendaHanlder(
 	router *gin.RouterGroup,
 	firebaseMiddleware middleware.FirebaseMiddleware,
 	permissionsMiddleware middleware.PermissionsMiddleware,
+	idempotencyMiddleware middleware.IdempotencyMiddleware,
 ) {
 	handler := AgendaController{service}
-	router.POST("/events", firebaseMiddleware.AuthMiddleware, handler.createEvent)
+	router.POST("/events", firebaseMiddleware.AuthMiddleware, idempotencyMiddleware.Idempotent, handler.createEvent)
 	router.GET("/events/:id", firebaseMiddleware.AuthMiddleware, handler.getEvent)
 	router.GET("/events", firebaseMiddleware.AuthMiddleware, handler.getEventsByFilter)
 	router.GET("/producer/:id/events", firebaseMiddleware.AuthMiddleware, handler.getEventsByProducer)
 	router.PATCH("/events/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.updateEvent)
 	router.DELETE("/events/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.deleteEvent)
-	router.POST("/applications", firebaseMiddleware.AuthMiddleware, handler.createApplication)
+	router.PUT("/events/:id/form", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.setForm)
+	router.GET("/events/:id/form", firebaseMiddleware.AuthMiddleware, handler.getForm)
+	router.POST("/applications", firebaseMiddleware.AuthMiddleware, idempotencyMiddleware.Idempotent, handler.createApplication)
 	router.GET("/applications/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationViewer, handler.getApplication)
-	router.GET("/events/:id/applications", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.getApplicationsByEvent)
-	router.GET("/performer/:id/applications", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationModifier, handler.getApplicationsByPerformer)
+	router.GET("/events/:id/applications", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventApplicationsViewer, handler.getApplicationsByEvent)
+	router.POST("/events/:id/applications/status", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, idempotencyMiddleware.Idempotent, handler.changeApplicationStatuses)
+	router.GET("/events/:id/waitlist", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventApplicationsViewer, handler.getWaitlist)
+	router.PUT("/events/:id/waitlist", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.setWaitlist)
+	router.GET("/events/:id/applications/export", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventApplicationsViewer, handler.exportApplicationsByEvent)
+	router.GET("/performer/:id/applications", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.getApplicationsByPerformer)
 	router.PATCH("/applications/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationModifier, handler.updateApplication)
 	router.DELETE("/applications/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationModifier, handler.deleteApplication)
 	router.POST("/tag/:name", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.Admin, handler.createTag)
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"backend/domain"
+	"backend/models"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseApplicationStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query   string
+		want    models.ApplicationStatus
+		wantErr error
+	}{
+		{query: "", want: ""},
+		{query: "accepted", want: models.StatusAccepted},
+		{query: "waitlisted", want: models.StatusWaitlisted},
+		{query: "Accepted", wantErr: domain.ErrInvalid},
+		{query: "booked", wantErr: domain.ErrInvalid},
+	}
+	for _, test := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?status="+url.QueryEscape(test.query), nil)
+		got, err := parseApplicationStatus(c)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("parseApplicationStatus(%q) error = %v, want %v", test.query, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseApplicationStatus(%q) = %q, want %q", test.query, got, test.want)
+		}
+		if e, ok := domain.As(err); ok && (len(e.Fields) != 1 || e.Fields[0].Field != "status") {
+			t.Errorf("parseApplicationStatus(%q) fields = %v, want one on status", test.query, e.Fields)
+		}
+	}
+}
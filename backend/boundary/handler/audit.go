@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/audit"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditController struct {
+	auditService audit.Service
+}
+
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		ActorID:    c.Query("actor_id"),
+		ResourceID: c.Query("resource_id"),
+		Action:     c.Query("action"),
+	}
+	for key, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if raw, ok := c.GetQuery(key); ok {
+			at, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return filter, domain.ErrBadRequest.WithMessage("invalid " + key).Wrap(err)
+			}
+			*target = &at
+		}
+	}
+	for key, target := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if raw, ok := c.GetQuery(key); ok {
+			n, err := strconv.Atoi(raw)
+			if err == nil && n < 0 {
+				err = fmt.Errorf("%s must not be negative", key)
+			}
+			if err != nil {
+				return filter, domain.ErrBadRequest.WithMessage("invalid " + key).Wrap(err)
+			}
+			*target = n
+		}
+	}
+	if raw, ok := c.GetQuery("profile_id"); ok {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, domain.ErrBadRequest.WithMessage("invalid profile_id").Wrap(err)
+		}
+		filter.ProfileID = &id
+	}
+	return filter, nil
+}
+
+// @Summary Get the audit trail of a profile
+// @Description Every recorded mutation touching the profile, its events or their applications, newest first.
+// @Description Restricted to the profile's admins.
+// @Tags Audit
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Profile ID"
+// @Param actor_id query string false "Firebase UID of the actor"
+// @Param resource_id query string false "ID of the changed resource"
+// @Param action query string false "Action, e.g. application.update"
+// @Param since query string false "Earliest entry (RFC3339)"
+// @Param until query string false "Latest entry, exclusive (RFC3339)"
+// @Param limit query integer false "Page size" default(100)
+// @Param offset query integer false "Entries to skip"
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Router /profiles/{id}/audit [get]
+func (h *AuditController) getByProfile(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	filter.ProfileID = &id
+	entries, err := h.auditService.GetEntries(c, filter)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary Get the full audit trail
+// @Description Every recorded mutation across all profiles, newest first. Super user only.
+// @Tags Audit
+// @Produce json
+// @Security BasicAuth
+// @Param profile_id query string false "Restrict to one profile"
+// @Param actor_id query string false "Firebase UID of the actor"
+// @Param resource_id query string false "ID of the changed resource"
+// @Param action query string false "Action, e.g. event.delete"
+// @Param since query string false "Earliest entry (RFC3339)"
+// @Param until query string false "Latest entry, exclusive (RFC3339)"
+// @Param limit query integer false "Page size" default(100)
+// @Param offset query integer false "Entries to skip"
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Router /audit [get]
+func (h *AuditController) getAll(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	entries, err := h.auditService.GetEntries(c, filter)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func RegisterAuditController(
+	service audit.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := AuditController{auditService: service}
+	router.GET("/profiles/:id/audit", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileAdmin, handler.getByProfile)
+	router.GET("/audit", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.Admin, handler.getAll)
+}
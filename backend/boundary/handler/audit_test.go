@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"backend/models"
+	"backend/usecase/audit"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// filterRepo records the filter the audit service queried with.
+type filterRepo struct {
+	audit.Repository
+	filter models.AuditFilter
+}
+
+func (r *filterRepo) GetEntries(_ context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	r.filter = filter
+	return nil, nil
+}
+
+func TestGetAuditByProfileIsScopedToThePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &filterRepo{}
+	handler := AuditController{auditService: audit.NewService(repo)}
+	profileID, otherID := uuid.New(), uuid.New()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: profileID.String()}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/profiles/"+profileID.String()+"/audit?profile_id="+otherID.String()+"&limit=5000&action=event.update", nil)
+	handler.getByProfile(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if repo.filter.ProfileID == nil || *repo.filter.ProfileID != profileID {
+		t.Errorf("queried profile = %v, want the one in the path %s", repo.filter.ProfileID, profileID)
+	}
+	if repo.filter.Action != "event.update" || repo.filter.Limit != audit.MaxLimit {
+		t.Errorf("filter = %+v, want the action kept and the limit capped at %d", repo.filter, audit.MaxLimit)
+	}
+}
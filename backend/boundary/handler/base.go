@@ -1,15 +1,83 @@
 package handler
 
 import (
+	"backend/domain"
+	"backend/models"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
+	"mime"
+	"strconv"
+	"strings"
 )
 
+const MergePatchContentType = "application/merge-patch+json"
+
+var errPatchContentType = domain.New(domain.KindUnsupportedMedia, "unsupported_media_type",
+	"PATCH bodies must be "+MergePatchContentType+" or application/json")
+
 func GetId(c *gin.Context) (uuid.UUID, error) {
 	if id, err := uuid.Parse(c.Param("id")); err != nil {
-		return uuid.Nil, gin.Error{Err: errors.Wrap(err, "unable to parse id"), Type: gin.ErrorTypeBind}
+		return uuid.Nil, domain.ErrBadRequest.WithMessage("unable to parse id").Wrap(err)
 	} else {
 		return id, nil
 	}
 }
+
+// GetIds parses the :id path parameter and a second one naming another resource.
+func GetIds(c *gin.Context, param string) (uuid.UUID, uuid.UUID, error) {
+	id, err := GetId(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	other, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrBadRequest.WithMessage("unable to parse " + param).Wrap(err)
+	}
+	return id, other, nil
+}
+
+// FirebaseID returns the caller's firebase UID. Requests authenticated as the super user have none.
+func FirebaseID(c *gin.Context) (string, bool) {
+	if firebaseID, exists := c.Get(models.FirebaseContextKey); exists {
+		return firebaseID.(string), true
+	}
+	return "", false
+}
+
+// MergePatch returns the raw RFC 7396 merge patch sent as the request body.
+func MergePatch(c *gin.Context) ([]byte, error) {
+	if contentType := c.GetHeader("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != MergePatchContentType && mediaType != gin.MIMEJSON) {
+			return nil, errPatchContentType
+		}
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		return nil, domain.ErrBadRequest.WithMessage("unable to read body").Wrap(err)
+	}
+	return patch, nil
+}
+
+// SetETag sends the resource version as a strong entity tag.
+func SetETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.FormatInt(version, 10)))
+}
+
+// IfMatch returns the version the If-Match header requires, or 0 when it is absent or "*". A tag that can never match
+// a version, such as a weak one, fails the precondition outright.
+func IfMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	if strings.Contains(header, ",") {
+		return 0, domain.BadRequest("invalid_if_match", "If-Match must be * or a single entity tag")
+	}
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || !strings.HasPrefix(header, `"`) || version <= 0 {
+		return 0, models.ErrVersionConflict
+	}
+	return version, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"backend/models"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		header  string
+		want    int64
+		wantErr error
+	}{
+		{name: "absent", header: "", want: 0},
+		{name: "any", header: "*", want: 0},
+		{name: "strong tag", header: `"3"`, want: 3},
+		{name: "padded", header: ` "3" `, want: 3},
+		{name: "weak tag never matches", header: `W/"3"`, wantErr: models.ErrVersionConflict},
+		{name: "unquoted", header: "3", wantErr: models.ErrVersionConflict},
+		{name: "not a version", header: `"abc"`, wantErr: models.ErrVersionConflict},
+		{name: "zero", header: `"0"`, wantErr: models.ErrVersionConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+			if test.header != "" {
+				c.Request.Header.Set("If-Match", test.header)
+			}
+			got, err := IfMatch(c)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("IfMatch() error = %v, want %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("IfMatch() = %d, want %d", got, test.want)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+		c.Request.Header.Set("If-Match", `"1", "2"`)
+		if _, err := IfMatch(c); err == nil || errors.Is(err, models.ErrVersionConflict) {
+			t.Errorf("IfMatch() error = %v, want invalid_if_match", err)
+		}
+	})
+}
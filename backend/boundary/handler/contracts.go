@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/contracts"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type ContractController struct {
+	contractService contracts.Service
+}
+
+// authorize lets the super user and either party of the contract through.
+func (h *ContractController) authorize(c *gin.Context, contract models.Contract) bool {
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		return true
+	}
+	if ok, err := h.contractService.IsParty(c, contract, firebaseID); err != nil {
+		presenter.HandleErr(c, err)
+		return false
+	} else if !ok {
+		presenter.HandleErr(c, contracts.ErrNotParty)
+		return false
+	}
+	return true
+}
+
+// @Summary Create a contract template
+// @Description Create a contract template for a producer. Templates use Go text/template syntax and are rendered
+// @Description with .Event, .Producer, .Performer, .Application, .Pay, .Slot and .Date. The newest template is used.
+// @Description A template that does not parse, or names a field the data lacks, is refused.
+// @Tags Contracts
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Producer ID"
+// @Param template body models.ContractTemplate true "Contract template"
+// @Success 201 {object} presenter.IdResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /producer/{id}/contract-templates [post]
+func (h *ContractController) createTemplate(c *gin.Context) {
+	producerID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var template models.ContractTemplate
+	if err := c.ShouldBind(&template); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	template.ProducerID = producerID
+	id, err := h.contractService.CreateTemplate(c, template)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, presenter.IdResponse{Id: id.String()})
+}
+
+// @Summary List contract templates
+// @Description Returns the producer's contract templates, newest first
+// @Tags Contracts
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Producer ID"
+// @Success 200 {array} models.ContractTemplate
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /producer/{id}/contract-templates [get]
+func (h *ContractController) getTemplates(c *gin.Context) {
+	producerID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	templates, err := h.contractService.GetTemplatesByProducer(c, producerID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Generate the contract for an application
+// @Description Renders the agreement for an accepted application. Unsigned contracts are regenerated in place; a void or
+// @Description terminated one is kept and a new one drafted.
+// @Tags Contracts
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Success 201 {object} models.Contract
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Router /applications/{id}/contract [post]
+func (h *ContractController) generate(c *gin.Context) {
+	applicationID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if firebaseID, ok := FirebaseID(c); ok {
+		if member, err := h.contractService.IsProducerMember(c, applicationID, firebaseID); err != nil {
+			presenter.HandleErr(c, err)
+			return
+		} else if !member {
+			presenter.HandleErr(c, contracts.ErrNotParty)
+			return
+		}
+	}
+	contract, err := h.contractService.GenerateForApplication(c, applicationID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, contract)
+}
+
+// @Summary Get the contract for an application
+// @Description When the application stops being accepted, a contract not yet signed by both parties is voided, and a
+// @Description signed one keeps its status with terminated_at set.
+// @Tags Contracts
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.Contract
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /applications/{id}/contract [get]
+func (h *ContractController) getByApplication(c *gin.Context) {
+	applicationID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	contract, err := h.contractService.GetContractByApplication(c, applicationID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if contract.ID == uuid.Nil {
+		presenter.HandleErr(c, domain.NotFound("no_contract", "no contract for this application"))
+		return
+	}
+	if !h.authorize(c, contract) {
+		return
+	}
+	c.JSON(http.StatusOK, contract)
+}
+
+// @Summary Get a contract by ID
+// @Tags Contracts
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Contract ID"
+// @Success 200 {object} models.Contract
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /contracts/{id} [get]
+func (h *ContractController) getContract(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	contract, err := h.contractService.GetContract(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if !h.authorize(c, contract) {
+		return
+	}
+	c.JSON(http.StatusOK, contract)
+}
+
+// @Summary Sign a contract
+// @Description Records the caller's firebase UID and the current time as the signature of the party named, which the
+// @Description caller has to be a member of. The contract becomes immutable once signed and is marked signed when both
+// @Description parties have signed.
+// @Tags Contracts
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Contract ID"
+// @Param party query string true "Side the caller signs for" Enums(producer, performer)
+// @Success 200 {object} models.Contract
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /contracts/{id}/sign [post]
+func (h *ContractController) sign(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("contracts must be signed by a firebase user"))
+		return
+	}
+	party, err := models.ParseContractParty(c.Query("party"))
+	if err != nil {
+		presenter.HandleErr(c, domain.ErrInvalid.WithFields(domain.FieldError{Field: "party", Message: err.Error()}))
+		return
+	}
+	contract, err := h.contractService.Sign(c, id, party, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, contract)
+}
+
+// @Summary Export a contract as PDF
+// @Tags Contracts
+// @Produce application/pdf
+// @Security BearerToken
+// @Param id path string true "Contract ID"
+// @Success 200 {file} file
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /contracts/{id}/pdf [get]
+func (h *ContractController) exportPDF(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	contract, err := h.contractService.GetContract(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if !h.authorize(c, contract) {
+		return
+	}
+	text := contract.Body + "\n\n" + signatureBlock("Producer", contract.ProducerSignerUID, contract.ProducerSignedAt) +
+		"\n" + signatureBlock("Performer", contract.PerformerSignerUID, contract.PerformerSignedAt) +
+		"\n\nDocument hash (SHA-256): " + contract.BodyHash
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=contract-%s.pdf", contract.ID))
+	c.Header("Content-Type", "application/pdf")
+	c.Status(http.StatusOK)
+	if err := presenter.WritePDF(c.Writer, "Contract "+contract.ID.String(), text); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func signatureBlock(party string, signer *string, at *time.Time) string {
+	if signer == nil || at == nil {
+		return party + ": not signed"
+	}
+	return fmt.Sprintf("%s: signed by %s at %s", party, *signer, at.Format(time.RFC3339))
+}
+
+func RegisterContractController(
+	service contracts.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := ContractController{contractService: service}
+	router.POST("/producer/:id/contract-templates", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.createTemplate)
+	router.GET("/producer/:id/contract-templates", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.getTemplates)
+	router.POST("/applications/:id/contract", firebaseMiddleware.AuthMiddleware, handler.generate)
+	router.GET("/applications/:id/contract", firebaseMiddleware.AuthMiddleware, handler.getByApplication)
+	router.GET("/contracts/:id", firebaseMiddleware.AuthMiddleware, handler.getContract)
+	router.POST("/contracts/:id/sign", firebaseMiddleware.AuthMiddleware, handler.sign)
+	router.GET("/contracts/:id/pdf", firebaseMiddleware.AuthMiddleware, handler.exportPDF)
+}
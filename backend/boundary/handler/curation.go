@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/curation"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CurationController struct {
+	curationService curation.Service
+}
+
+func reviewerID(c *gin.Context) (string, bool) {
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("reviewers must be firebase users"))
+	}
+	return firebaseID, ok
+}
+
+// @Summary Set an event's rubric
+// @Description Defines the criteria reviewers score (0 to 10, weighted), the producer members assigned to review by
+// @Description user ID, and whether reviewers see who the performers are. Criteria are fixed once anything is scored.
+// @Tags Curation
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param rubric body models.Rubric true "Criteria, reviewer_ids and blind"
+// @Success 200 {object} models.Rubric
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /events/{id}/rubric [put]
+func (h *CurationController) setRubric(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var rubric models.Rubric
+	if err := c.ShouldBindJSON(&rubric); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.curationService.SetRubric(c, id, rubric)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Get an event's rubric
+// @Tags Curation
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Success 200 {object} models.Rubric
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /events/{id}/rubric [get]
+func (h *CurationController) getRubric(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	rubric, err := h.curationService.GetRubric(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rubric)
+}
+
+// @Summary Get the caller's review queue for an event
+// @Description Every application of the event with the caller's own score, if any. Only assigned reviewers may ask.
+// @Description Under a blind rubric the performer, the application name and the form answers are left out, and the
+// @Description reviewers may not see the event's applications anywhere else.
+// @Tags Curation
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Success 200 {array} models.ReviewItem
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /events/{id}/review-queue [get]
+func (h *CurationController) reviewQueue(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, ok := reviewerID(c)
+	if !ok {
+		return
+	}
+	items, err := h.curationService.ReviewQueue(c, id, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Score an application
+// @Description Records or replaces the caller's score of the application against the event's rubric. Every criterion
+// @Description needs a score from 0 to 10. Only the event's assigned reviewers may score.
+// @Tags Curation
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Param score body models.ApplicationScore true "Scores by criterion name and an optional comment"
+// @Success 200 {object} models.ApplicationScore
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /applications/{id}/score [put]
+func (h *CurationController) submitScore(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, ok := reviewerID(c)
+	if !ok {
+		return
+	}
+	var score models.ApplicationScore
+	if err := c.ShouldBindJSON(&score); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.curationService.SubmitScore(c, id, firebaseID, score)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func RegisterCurationController(
+	service curation.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := CurationController{curationService: service}
+	router.PUT("/events/:id/rubric", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.setRubric)
+	router.GET("/events/:id/rubric", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.getRubric)
+	router.GET("/events/:id/review-queue", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.reviewQueue)
+	router.PUT("/applications/:id/score", firebaseMiddleware.AuthMiddleware, handler.submitScore)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"backend/boundary/presenter"
+	"backend/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary List an ensemble's members
+// @Description Active members, pending invitations and declined ones, with the member profiles.
+// @Tags Profiles
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Ensemble profile ID"
+// @Success 200 {array} models.EnsembleMember
+// @Failure 400 {object} presenter.Problem
+// @Router /profiles/{id}/ensemble/members [get]
+func (u *UserController) getMembers(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	members, err := u.userService.GetMembers(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Invite a performer to an ensemble
+// @Description The performer becomes a member once their own profile accepts. Inviting an active member again only
+// @Description changes their role. Only an ensemble profile can invite, and only a performer that is not one can be
+// @Description invited.
+// @Tags Profiles
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Ensemble profile ID"
+// @Param invitation body models.EnsembleInvitation true "Performer to invite"
+// @Success 200 {object} models.EnsembleMember
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /profiles/{id}/ensemble/members [post]
+func (u *UserController) invite(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var invitation models.EnsembleInvitation
+	if err := c.ShouldBindJSON(&invitation); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	membership, err := u.userService.Invite(c, id, invitation)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// @Summary Remove a member from an ensemble
+// @Description Also withdraws a pending invitation.
+// @Tags Profiles
+// @Security BearerToken
+// @Param id path string true "Ensemble profile ID"
+// @Param member_id path string true "Member profile ID"
+// @Success 204
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /profiles/{id}/ensemble/members/{member_id} [delete]
+func (u *UserController) removeMember(c *gin.Context) {
+	id, memberID, err := GetIds(c, "member_id")
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := u.userService.RemoveMember(c, id, memberID); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List a performer's ensembles
+// @Description The ensembles the performer belongs to or is invited to, with the ensemble profiles.
+// @Tags Profiles
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Performer profile ID"
+// @Success 200 {array} models.EnsembleMember
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Router /profiles/{id}/ensembles [get]
+func (u *UserController) getEnsembles(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	ensembles, err := u.userService.GetEnsembles(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ensembles)
+}
+
+// @Summary Answer an ensemble's invitation
+// @Description Accepting makes the performer part of the ensemble's applications and bookings; declining keeps them
+// @Description out until they are invited again. Only a pending invitation can be answered, except that accepting
+// @Description again as an active member changes nothing.
+// @Tags Profiles
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Performer profile ID"
+// @Param ensemble_id path string true "Ensemble profile ID"
+// @Param response body models.InvitationResponse true "Answer"
+// @Success 200 {object} models.EnsembleMember
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /profiles/{id}/ensembles/{ensemble_id} [put]
+func (u *UserController) respondToInvitation(c *gin.Context) {
+	id, ensembleID, err := GetIds(c, "ensemble_id")
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var response models.InvitationResponse
+	if err := c.ShouldBindJSON(&response); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	membership, err := u.userService.RespondToInvitation(c, id, ensembleID, response)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// @Summary Leave an ensemble
+// @Tags Profiles
+// @Security BearerToken
+// @Param id path string true "Performer profile ID"
+// @Param ensemble_id path string true "Ensemble profile ID"
+// @Success 204
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /profiles/{id}/ensembles/{ensemble_id} [delete]
+func (u *UserController) leaveEnsemble(c *gin.Context) {
+	id, ensembleID, err := GetIds(c, "ensemble_id")
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := u.userService.RemoveMember(c, ensembleID, id); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
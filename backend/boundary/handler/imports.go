@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/usecase/imports"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// maxImportBytes caps an uploaded CSV file; imports.MaxRows caps it again once parsed.
+const maxImportBytes = 10 << 20
+
+type ImportController struct {
+	importService imports.Service
+}
+
+type importFunc func(ctx context.Context, producerID uuid.UUID, r io.Reader, dryRun bool) (imports.Report, error)
+
+// runImport answers 200 with the report of a dry run and 201 with the report of a committed import. An import that
+// was rejected answers with the problem instead, listing every row error.
+func runImport(c *gin.Context, run importFunc) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	dryRun := false
+	if raw, ok := c.GetQuery("dry_run"); ok {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			presenter.HandleErr(c, domain.ErrBadRequest.WithMessage("invalid dry_run").Wrap(err))
+			return
+		}
+	}
+	report, err := run(c, id, http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes), dryRun)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if report.DryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// @Summary Import events from CSV
+// @Description Creates a season of events for the producer from a CSV file with a header row. Columns: name, time
+// @Description (both required), description, apply_by_time, location ("lat,lng" or an address), venue (a venue's
+// @Description name), tags (names separated by semicolons), status and pay (free text such as "$150" or "70% door").
+// @Description At most 20 distinct addresses are geocoded per import. Either every row is created or none is; a dry
+// @Description run reports row by row what would fail.
+// @Tags Imports
+// @Accept text/csv
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Producer ID"
+// @Param dry_run query bool false "Validate and report without creating anything"
+// @Param file body string true "CSV file"
+// @Success 200 {object} imports.Report
+// @Success 201 {object} imports.Report
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /profiles/{id}/imports/events [post]
+func (h *ImportController) importEvents(c *gin.Context) {
+	runImport(c, h.importService.ImportEvents)
+}
+
+// @Summary Import performers from CSV
+// @Description Creates the producer's roster of past performers from a CSV file with a header row. Columns: name
+// @Description (required), type (performer by default), location ("lat,lng" or an address) and portfolio_links
+// @Description (separated by spaces or semicolons). The producer's admins, and those of its organization, become admins
+// @Description of the imported profiles until an operator hands them over with "ocall-admin members add" and
+// @Description "permissions revoke"; a producer without admins cannot import. At most 20 distinct addresses are geocoded
+// @Description per import. Either every row is created or none is.
+// @Tags Imports
+// @Accept text/csv
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Producer ID"
+// @Param dry_run query bool false "Validate and report without creating anything"
+// @Param file body string true "CSV file"
+// @Success 200 {object} imports.Report
+// @Success 201 {object} imports.Report
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /profiles/{id}/imports/performers [post]
+func (h *ImportController) importPerformers(c *gin.Context) {
+	runImport(c, h.importService.ImportProfiles)
+}
+
+func RegisterImportController(
+	service imports.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := ImportController{importService: service}
+	router.POST("/profiles/:id/imports/events", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileAdmin, handler.importEvents)
+	router.POST("/profiles/:id/imports/performers", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileAdmin, handler.importPerformers)
+}
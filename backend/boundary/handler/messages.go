@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/models"
+	"backend/usecase/messages"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type MessageController struct {
+	messageService messages.Service
+}
+
+// @Summary Send a message on an application
+// @Description Either side of the application may write; profile_id says which one the message is sent for. Both
+// @Description sides are told through the stream as message.created.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Param message body models.MessageRequest true "Message"
+// @Success 201 {object} models.Message
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /applications/{id}/messages [post]
+func (h *MessageController) send(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var request models.MessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, _ := FirebaseID(c)
+	out, err := h.messageService.Send(c, id, request, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// @Summary List the messages on an application
+// @Description Oldest first.
+// @Tags Messages
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.Message
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /applications/{id}/messages [get]
+func (h *MessageController) getMessages(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.messageService.GetMessages(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func RegisterMessageController(
+	service messages.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := MessageController{messageService: service}
+	router.POST("/applications/:id/messages", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationViewer, handler.send)
+	router.GET("/applications/:id/messages", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ApplicationViewer, handler.getMessages)
+}
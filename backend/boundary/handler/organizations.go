@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/organizations"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type OrganizationController struct {
+	organizationService organizations.Service
+}
+
+// @Summary Create an organization
+// @Description The caller becomes its first admin. Profiles are brought in afterwards by someone who administers both.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param organization body models.Organization true "Organization to create"
+// @Success 201 {object} models.Organization
+// @Failure 400 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /organizations [post]
+func (h *OrganizationController) create(c *gin.Context) {
+	var organization models.Organization
+	if err := c.ShouldBindJSON(&organization); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, _ := FirebaseID(c)
+	out, err := h.organizationService.CreateOrganization(c, organization, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// @Summary List the caller's organizations
+// @Tags Organizations
+// @Produce json
+// @Security BearerToken
+// @Success 200 {array} models.Organization
+// @Failure 401 {object} presenter.Problem
+// @Router /organizations [get]
+func (h *OrganizationController) getMine(c *gin.Context) {
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("only firebase users administer organizations"))
+		return
+	}
+	out, err := h.organizationService.GetOrganizations(c, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Get an organization
+// @Description Includes the profiles it owns.
+// @Tags Organizations
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /organizations/{id} [get]
+func (h *OrganizationController) get(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.organizationService.GetOrganization(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary List an organization's admins
+// @Tags Organizations
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.OrganizationAdmin
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Router /organizations/{id}/admins [get]
+func (h *OrganizationController) getAdmins(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.organizationService.GetAdmins(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Add an organization admin
+// @Description The user becomes an admin of every profile the organization owns.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Param admin body models.OrganizationAdminRequest true "Firebase user to add"
+// @Success 200 {object} models.OrganizationAdmin
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /organizations/{id}/admins [post]
+func (h *OrganizationController) addAdmin(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var request models.OrganizationAdminRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.organizationService.AddAdmin(c, id, request.FirebaseId)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Remove an organization admin
+// @Description They keep any membership they hold on its profiles directly. The last admin cannot be removed.
+// @Tags Organizations
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Param firebase_id path string true "Firebase UID of the admin"
+// @Success 204
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Router /organizations/{id}/admins/{firebase_id} [delete]
+func (h *OrganizationController) removeAdmin(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := h.organizationService.RemoveAdmin(c, id, c.Param("firebase_id")); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Bring a profile into an organization
+// @Description Only producer and venue profiles can belong to one. The caller has to be an admin of the profile as well
+// @Description as of the organization; a profile owned by another organization moves.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Param profile body models.OrganizationProfileRequest true "Profile to bring in"
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /organizations/{id}/profiles [post]
+func (h *OrganizationController) addProfile(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var request models.OrganizationProfileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, _ := FirebaseID(c)
+	out, err := h.organizationService.AddProfile(c, id, request.ProfileID, firebaseID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Hand a profile back from an organization
+// @Description The profile has to have an admin of its own, so it is not left without anyone to run it.
+// @Tags Organizations
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Param profile_id path string true "Profile ID"
+// @Success 204
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Router /organizations/{id}/profiles/{profile_id} [delete]
+func (h *OrganizationController) removeProfile(c *gin.Context) {
+	id, profileID, err := GetIds(c, "profile_id")
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := h.organizationService.RemoveProfile(c, id, profileID); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List an organization's events
+// @Description Events produced by or held at any of its profiles, soonest first, with their producer and venue.
+// @Tags Organizations
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.Event
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Router /organizations/{id}/events [get]
+func (h *OrganizationController) getEvents(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	events, err := h.organizationService.GetEvents(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary List the applications to an organization's events
+// @Tags Organizations
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Organization ID"
+// @Param status query string false "Only applications with this status" Enums(accepted, rejected, pending, offered, unknown, waitlisted, declined, expired)
+// @Success 200 {array} models.Application
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Router /organizations/{id}/applications [get]
+func (h *OrganizationController) getApplications(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	status, err := parseApplicationStatus(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	applications, err := h.organizationService.GetApplications(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if status != "" {
+		filtered := make([]models.Application, 0, len(applications))
+		for _, application := range applications {
+			if application.Status == status {
+				filtered = append(filtered, application)
+			}
+		}
+		applications = filtered
+	}
+	c.JSON(http.StatusOK, applications)
+}
+
+func RegisterOrganizationController(
+	service organizations.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := OrganizationController{organizationService: service}
+	router.POST("/organizations", firebaseMiddleware.AuthMiddleware, handler.create)
+	router.GET("/organizations", firebaseMiddleware.AuthMiddleware, handler.getMine)
+	router.GET("/organizations/:id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.get)
+	router.GET("/organizations/:id/admins", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.getAdmins)
+	router.POST("/organizations/:id/admins", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.addAdmin)
+	router.DELETE("/organizations/:id/admins/:firebase_id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.removeAdmin)
+	router.POST("/organizations/:id/profiles", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.addProfile)
+	router.DELETE("/organizations/:id/profiles/:profile_id", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.removeProfile)
+	router.GET("/organizations/:id/events", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.getEvents)
+	router.GET("/organizations/:id/applications", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.OrganizationAdmin, handler.getApplications)
+}
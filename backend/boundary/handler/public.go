@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/agenda"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PublicMaxAge is how long browsers and shared caches may reuse a public response without asking again.
+const PublicMaxAge = time.Minute
+
+type PublicController struct {
+	agendaService agenda.Service
+	// publicURL is the scheme and host clients reach the API at, which feeds link to. Empty takes them from the request.
+	publicURL string
+	// eventURL is where feeds link each event, with {id} standing for its ID. Empty links the public API instead.
+	eventURL string
+	basePath string
+	// trustedProxies are the only peers whose X-Forwarded-Proto is believed.
+	trustedProxies []*net.IPNet
+}
+
+// cacheable sends data that any cache may keep for PublicMaxAge, tagged with a hash of its content. A request whose
+// If-None-Match already holds that tag gets 304 and no body.
+func cacheable(c *gin.Context, contentType string, data []byte) {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(PublicMaxAge.Seconds())))
+	c.Header("ETag", etag)
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/"); tag == etag || tag == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+func cacheableJSON(c *gin.Context, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	cacheable(c, gin.MIMEJSON+"; charset=utf-8", data)
+}
+
+func parseOptionalID(c *gin.Context, key string) (*uuid.UUID, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, domain.ErrBadRequest.WithMessage("invalid " + key).Wrap(err)
+	}
+	return &id, nil
+}
+
+// parsePublicEventFilter reads the optional from, to, producer_id, venue_id, tag and limit query parameters. from
+// defaults to now, so the listing is of upcoming events.
+func parsePublicEventFilter(c *gin.Context) (models.PublicEventFilter, error) {
+	filter := models.PublicEventFilter{From: time.Now().UTC(), Tag: c.Query("tag")}
+	if _, ok := c.GetQuery("from"); ok {
+		if err := parseTime(c, "from", &filter.From); err != nil {
+			return filter, err
+		}
+	}
+	if _, ok := c.GetQuery("to"); ok {
+		var to time.Time
+		if err := parseTime(c, "to", &to); err != nil {
+			return filter, err
+		}
+		filter.To = &to
+	}
+	var err error
+	if filter.ProducerID, err = parseOptionalID(c, "producer_id"); err != nil {
+		return filter, err
+	}
+	if filter.VenueID, err = parseOptionalID(c, "venue_id"); err != nil {
+		return filter, err
+	}
+	if raw, ok := c.GetQuery("limit"); ok {
+		if filter.Limit, err = strconv.Atoi(raw); err != nil || filter.Limit < 1 {
+			return filter, domain.ErrInvalid.WithFields(domain.FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+	}
+	return filter, nil
+}
+
+// @Summary List published events
+// @Description Open and closed events, soonest first, without applications, Google form IDs or offer settings, and
+// @Description without pay where the producer hides it. No authentication; rate limited per client IP. Responses carry
+// @Description an ETag and may be cached for a minute.
+// @Tags Public
+// @Produce json
+// @Param from query string false "Earliest start time (RFC3339); defaults to now"
+// @Param to query string false "Start time to list up to, exclusive (RFC3339)"
+// @Param producer_id query string false "Only this producer's events"
+// @Param venue_id query string false "Only events at this venue"
+// @Param tag query string false "Only events with this tag"
+// @Param limit query integer false "At most this many events, up to 100" default(50)
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {array} models.PublicEvent
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Cache-Control "public, max-age=60"
+// @Failure 400 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 429 {object} presenter.Problem
+// @Router /public/events [get]
+func (h *PublicController) getEvents(c *gin.Context) {
+	filter, err := parsePublicEventFilter(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	events, err := h.agendaService.GetPublicEvents(c, filter)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	cacheableJSON(c, events)
+}
+
+// @Summary Get a published event
+// @Description Drafts and cancelled events are not found. No authentication; rate limited per client IP.
+// @Tags Public
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {object} models.PublicEvent
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Cache-Control "public, max-age=60"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 429 {object} presenter.Problem
+// @Router /public/events/{id} [get]
+func (h *PublicController) getEvent(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	event, err := h.agendaService.GetPublicEvent(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	cacheableJSON(c, event)
+}
+
+// origin is the scheme and host clients reach the API at: the public URL when one is configured, or else the request's
+// host with the scheme a trusted proxy forwarded.
+func (h *PublicController) origin(c *gin.Context) string {
+	if h.publicURL != "" {
+		return h.publicURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || (h.fromTrustedProxy(c) && c.GetHeader("X-Forwarded-Proto") == "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func (h *PublicController) fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range h.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxies reads the IPs and CIDRs gin was given as trusted proxies, which has already refused any invalid one.
+func parseProxies(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			nets = append(nets, network)
+		}
+	}
+	return nets
+}
+
+func (h *PublicController) links(c *gin.Context) presenter.FeedLinks {
+	base := h.origin(c)
+	return presenter.FeedLinks{
+		Self: base + c.Request.URL.RequestURI(),
+		Event: func(id uuid.UUID) string {
+			if h.eventURL == "" {
+				return base + h.basePath + "/events/" + id.String()
+			}
+			return strings.ReplaceAll(h.eventURL, "{id}", id.String())
+		},
+	}
+}
+
+// writeFeed renders the feed in the format the query asks for.
+func (h *PublicController) writeFeed(c *gin.Context, feed func() (models.Feed, error)) {
+	format, ok := presenter.ParseFeedFormat(c.Query("format"))
+	if !ok {
+		presenter.HandleErr(c, domain.ErrInvalid.WithFields(domain.FieldError{
+			Field: "format", Message: fmt.Sprintf("invalid value %q. Allowed: rss, atom, json, html", c.Query("format")),
+		}))
+		return
+	}
+	out, err := feed()
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	data, err := presenter.WriteFeed(format, out, h.links(c))
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	cacheable(c, format.ContentType(), data)
+}
+
+// @Summary Feed of a producer's calls for performers
+// @Description The producer's upcoming open events whose apply-by deadline has not passed, soonest first, as RSS 2.0,
+// @Description Atom, JSON Feed or an HTML list to embed in another site. Items carry the start time, the venue and
+// @Description the apply-by deadline. No authentication; rate limited per client IP and cacheable like the rest.
+// @Tags Public
+// @Produce application/rss+xml,application/atom+xml,application/feed+json,text/html
+// @Param id path string true "Producer profile ID"
+// @Param format query string false "Feed format" Enums(rss, atom, json, html) default(rss)
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {string} string "The feed"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 429 {object} presenter.Problem
+// @Router /public/producers/{id}/feed [get]
+func (h *PublicController) getProducerFeed(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	h.writeFeed(c, func() (models.Feed, error) { return h.agendaService.GetProducerFeed(c, id) })
+}
+
+// @Summary Feed of the calls for performers at a venue
+// @Description Like the producer feed, for the upcoming open events held at the venue.
+// @Tags Public
+// @Produce application/rss+xml,application/atom+xml,application/feed+json,text/html
+// @Param id path string true "Venue profile ID"
+// @Param format query string false "Feed format" Enums(rss, atom, json, html) default(rss)
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {string} string "The feed"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 429 {object} presenter.Problem
+// @Router /public/venues/{id}/feed [get]
+func (h *PublicController) getVenueFeed(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	h.writeFeed(c, func() (models.Feed, error) { return h.agendaService.GetVenueFeed(c, id) })
+}
+
+// @Summary Feed of the calls for performers with a tag
+// @Description Like the producer feed, for the upcoming open events of any producer that have the tag.
+// @Tags Public
+// @Produce application/rss+xml,application/atom+xml,application/feed+json,text/html
+// @Param name path string true "Tag name"
+// @Param format query string false "Feed format" Enums(rss, atom, json, html) default(rss)
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Success 200 {string} string "The feed"
+// @Success 304 "Not Modified"
+// @Failure 404 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 429 {object} presenter.Problem
+// @Router /public/tags/{name}/feed [get]
+func (h *PublicController) getTagFeed(c *gin.Context) {
+	h.writeFeed(c, func() (models.Feed, error) { return h.agendaService.GetTagFeed(c, c.Param("name")) })
+}
+
+// allowAnyOrigin lets websites on any origin read the public API from the browser; nothing in it needs credentials.
+func allowAnyOrigin(c *gin.Context) {
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Header("Access-Control-Expose-Headers", "ETag, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining")
+	c.Next()
+}
+
+// RegisterPublicController mounts the read-only API that needs no authentication under /public. Feeds link to the API
+// at publicURL, a scheme and host, and link events to eventURL, in which {id} stands for the event ID, or to the public
+// API when it is empty. Without a publicURL the links follow the request's Host header, so deployments behind shared
+// caches should set one.
+func RegisterPublicController(
+	service agenda.Service,
+	router *gin.RouterGroup,
+	rateLimitMiddleware middleware.RateLimitMiddleware,
+	publicURL string,
+	eventURL string,
+	trustedProxies []string,
+) {
+	public := router.Group("/public", allowAnyOrigin, rateLimitMiddleware.Limit)
+	handler := PublicController{
+		agendaService:  service,
+		publicURL:      strings.TrimRight(publicURL, "/"),
+		eventURL:       eventURL,
+		basePath:       public.BasePath(),
+		trustedProxies: parseProxies(trustedProxies),
+	}
+	public.GET("/events", handler.getEvents)
+	public.GET("/events/:id", handler.getEvent)
+	public.GET("/producers/:id/feed", handler.getProducerFeed)
+	public.GET("/venues/:id/feed", handler.getVenueFeed)
+	public.GET("/tags/:name/feed", handler.getTagFeed)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"crypto/tls"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublicOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	proxies := parseProxies([]string{"10.0.0.0/8", "192.168.1.1", "::1"})
+	tests := []struct {
+		name      string
+		publicURL string
+		remote    string
+		proto     string
+		tls       bool
+		want      string
+	}{
+		{name: "plain request", remote: "203.0.113.5:4000", want: "http://api.example"},
+		{name: "TLS request", remote: "203.0.113.5:4000", tls: true, want: "https://api.example"},
+		{name: "trusted proxy in a range", remote: "10.1.2.3:4000", proto: "https", want: "https://api.example"},
+		{name: "trusted proxy by IP", remote: "192.168.1.1:4000", proto: "https", want: "https://api.example"},
+		{name: "trusted IPv6 proxy", remote: "[::1]:4000", proto: "https", want: "https://api.example"},
+		{name: "untrusted client claims https", remote: "192.168.1.2:4000", proto: "https", want: "http://api.example"},
+		{
+			name: "configured public URL wins", publicURL: "https://ocall.example", remote: "203.0.113.5:4000",
+			want: "https://ocall.example",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "http://api.example/public/events", nil)
+			c.Request.RemoteAddr = test.remote
+			if test.proto != "" {
+				c.Request.Header.Set("X-Forwarded-Proto", test.proto)
+			}
+			if test.tls {
+				c.Request.TLS = &tls.ConnectionState{}
+			}
+			h := PublicController{publicURL: test.publicURL, trustedProxies: proxies}
+			if got := h.origin(c); got != test.want {
+				t.Errorf("origin() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/reviews"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ReviewController struct {
+	reviewService reviews.Service
+}
+
+// @Summary Review the other side of a booking
+// @Description Producers review the performer and performers review the producer of an accepted application once the
+// @Description event has taken place. Reviews stay hidden until both sides have submitted or the review window closes.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Application ID"
+// @Param review body models.Review true "Rating (1-5) and body"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Router /applications/{id}/reviews [post]
+func (h *ReviewController) submit(c *gin.Context) {
+	applicationID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("reviews must be written by a firebase user"))
+		return
+	}
+	var review models.Review
+	if err := c.ShouldBind(&review); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.reviewService.Submit(c, applicationID, firebaseID, review)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// @Summary Get the published reviews of a profile
+// @Tags Reviews
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Profile ID"
+// @Success 200 {object} presenter.ReviewsResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /profiles/{id}/reviews [get]
+func (h *ReviewController) getByProfile(c *gin.Context) {
+	profileID, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	reputation, err := h.reviewService.GetReputation(c, profileID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.reviewService.GetReviewsOfProfile(c, profileID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, presenter.ReviewsResponse{Reputation: reputation, Reviews: out})
+}
+
+// @Summary Flag a review for moderation
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Review ID"
+// @Param reason body presenter.ModerationRequest false "Reason in note"
+// @Success 200 {object} models.Review
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /reviews/{id}/flag [post]
+func (h *ReviewController) flag(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseID, ok := FirebaseID(c)
+	if !ok {
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("reviews must be flagged by a firebase user"))
+		return
+	}
+	var request presenter.ModerationRequest
+	_ = c.ShouldBindJSON(&request)
+	out, err := h.reviewService.Flag(c, id, firebaseID, request.Note)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary List flagged reviews
+// @Tags Reviews
+// @Produce json
+// @Security BasicAuth
+// @Success 200 {array} models.Review
+// @Failure 401 {object} presenter.Problem
+// @Router /reviews/flagged [get]
+func (h *ReviewController) getFlagged(c *gin.Context) {
+	out, err := h.reviewService.GetFlaggedReviews(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Moderate a review
+// @Description Set a review to visible or removed. Removed reviews are hidden and excluded from reputation scores.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Review ID"
+// @Param moderation body presenter.ModerationRequest true "Moderation decision"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Router /reviews/{id}/moderation [patch]
+func (h *ReviewController) moderate(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var request presenter.ModerationRequest
+	if err := c.ShouldBind(&request); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	status, err := models.ParseModerationStatus(request.Status.String())
+	if err != nil {
+		presenter.HandleErr(c, domain.ErrInvalid.WithFields(domain.FieldError{Field: "status", Message: err.Error()}))
+		return
+	}
+	out, err := h.reviewService.Moderate(c, id, status, request.Note)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func RegisterReviewController(
+	service reviews.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := ReviewController{reviewService: service}
+	router.POST("/applications/:id/reviews", firebaseMiddleware.AuthMiddleware, handler.submit)
+	router.GET("/profiles/:id/reviews", firebaseMiddleware.AuthMiddleware, handler.getByProfile)
+	router.POST("/reviews/:id/flag", firebaseMiddleware.AuthMiddleware, handler.flag)
+	router.GET("/reviews/flagged", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.Admin, handler.getFlagged)
+	router.PATCH("/reviews/:id/moderation", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.Admin, handler.moderate)
+}
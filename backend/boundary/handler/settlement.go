@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/models"
+	"backend/usecase/settlement"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SettlementController struct {
+	settlementService settlement.Service
+}
+
+// @Summary Get the settlement ledger of an event
+// @Description Expected, paid and outstanding amounts for every accepted application of the event
+// @Tags Settlement
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Success 200 {object} models.EventSettlement
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /events/{id}/settlement [get]
+func (h *SettlementController) getEventSettlement(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.settlementService.GetEventSettlement(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Record the actual revenue of an event
+// @Description Door revenue (minor currency units) and tickets sold, used to settle door splits and ticket tiers
+// @Tags Settlement
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param revenue body models.EventRevenue true "Revenue"
+// @Success 200 {object} models.EventRevenue
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /events/{id}/revenue [put]
+func (h *SettlementController) putRevenue(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var revenue models.EventRevenue
+	if err := c.ShouldBind(&revenue); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	revenue.EventRef = id
+	out, err := h.settlementService.RecordRevenue(c, revenue)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Record a payment to a performer
+// @Description Appends a payment to the event's ledger. Corrections are recorded as negative amounts.
+// @Tags Settlement
+// @Accept json
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Event ID"
+// @Param payment body models.Payment true "Payment"
+// @Success 201 {object} presenter.IdResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Router /events/{id}/payments [post]
+func (h *SettlementController) createPayment(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	var payment models.Payment
+	if err := c.ShouldBind(&payment); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	payment.RecordedBy, _ = FirebaseID(c)
+	paymentID, err := h.settlementService.RecordPayment(c, id, payment)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, presenter.IdResponse{Id: paymentID.String()})
+}
+
+// @Summary Get a performer's outstanding balance
+// @Description Expected, paid and outstanding amounts across every event the performer was accepted to
+// @Tags Settlement
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Performer ID"
+// @Success 200 {object} models.PerformerBalance
+// @Failure 403 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /performer/{id}/balance [get]
+func (h *SettlementController) getPerformerBalance(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := h.settlementService.GetPerformerBalance(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func RegisterSettlementController(
+	service settlement.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := SettlementController{settlementService: service}
+	router.GET("/events/:id/settlement", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventApplicationsViewer, handler.getEventSettlement)
+	router.PUT("/events/:id/revenue", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.putRevenue)
+	router.POST("/events/:id/payments", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.EventModifier, handler.createPayment)
+	router.GET("/performer/:id/balance", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.getPerformerBalance)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/models"
+	"backend/usecase/stream"
+	"github.com/gin-gonic/gin"
+	"io"
+	"time"
+)
+
+const streamHeartbeat = 30 * time.Second
+
+type StreamController struct {
+	streamService stream.Service
+}
+
+// @Summary Subscribe to real-time updates
+// @Description Server-Sent Events stream of application created/status-changed, message and event status-changed
+// @Description notifications for every profile the caller is a member of. Memberships are re-checked every 30 seconds,
+// @Description and the stream ends if that fails. Super users receive every event.
+// @Tags Stream
+// @Produce text/event-stream
+// @Security BearerToken
+// @Success 200 {object} models.StreamEvent
+// @Failure 401 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
+// @Router /stream [get]
+func (s *StreamController) subscribe(c *gin.Context) {
+	ctx := c.Request.Context()
+	var events <-chan models.StreamEvent
+	var err error
+	if firebaseID, exists := c.Get(models.FirebaseContextKey); exists {
+		events, err = s.streamService.Subscribe(ctx, firebaseID.(string))
+	} else {
+		events, err = s.streamService.SubscribeAll(ctx)
+	}
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			// the audience is routing information, not something subscribers need to see
+			event.Audience = nil
+			c.SSEvent(event.Type.String(), event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().UTC())
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+func RegisterStreamController(
+	service stream.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+) {
+	handler := StreamController{streamService: service}
+	router.GET("/stream", firebaseMiddleware.AuthMiddleware, handler.subscribe)
+}
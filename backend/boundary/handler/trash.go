@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"backend/boundary/middleware"
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/trash"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type TrashController struct {
+	trashService trash.Service
+}
+
+func getTrashItem(c *gin.Context) (uuid.UUID, models.TrashKind, uuid.UUID, error) {
+	profileID, err := GetId(c)
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, err
+	}
+	kind, err := models.ParseTrashKind(c.Param("kind"))
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, domain.ErrBadRequest.Wrap(err)
+	}
+	id, err := uuid.Parse(c.Param("item"))
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, domain.ErrBadRequest.WithMessage("unable to parse item id").Wrap(err)
+	}
+	return profileID, kind, id, nil
+}
+
+// @Summary List a profile's trash
+// @Description Soft-deleted events, applications and the profile itself, newest first. Items can be restored until
+// @Description expires_at, after which they are purged.
+// @Tags Trash
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Profile ID"
+// @Success 200 {array} models.TrashItem
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Router /profiles/{id}/trash [get]
+func (h *TrashController) getTrash(c *gin.Context) {
+	id, err := GetId(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	items, err := h.trashService.GetTrash(c, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Restore an item from the trash
+// @Description Applications can only be restored once their event and performer are, and events once their producer is.
+// @Tags Trash
+// @Produce json
+// @Security BearerToken
+// @Param id path string true "Profile ID"
+// @Param kind path string true "event, application or profile"
+// @Param item path string true "ID of the deleted item"
+// @Success 200 {object} models.TrashItem
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 410 {object} presenter.Problem
+// @Router /profiles/{id}/trash/{kind}/{item}/restore [post]
+func (h *TrashController) restore(c *gin.Context) {
+	profileID, kind, id, err := getTrashItem(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	item, err := h.trashService.Restore(c, profileID, kind, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Permanently delete an item from the trash
+// @Description Purging an application also purges its messages and scores. Purging an event also purges its
+// @Description applications, rubric and forms; purging a profile purges its events, applications and ensemble memberships.
+// @Tags Trash
+// @Security BearerToken
+// @Param id path string true "Profile ID"
+// @Param kind path string true "event, application or profile"
+// @Param item path string true "ID of the deleted item"
+// @Success 204
+// @Failure 400 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Router /profiles/{id}/trash/{kind}/{item} [delete]
+func (h *TrashController) purge(c *gin.Context) {
+	profileID, kind, id, err := getTrashItem(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := h.trashService.Purge(c, profileID, kind, id); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func RegisterTrashController(
+	service trash.Service,
+	router *gin.RouterGroup,
+	firebaseMiddleware middleware.FirebaseMiddleware,
+	permissionsMiddleware middleware.PermissionsMiddleware,
+) {
+	handler := TrashController{trashService: service}
+	router.GET("/profiles/:id/trash", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.TrashAdmin, handler.getTrash)
+	router.POST("/profiles/:id/trash/:kind/:item/restore", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.TrashAdmin, handler.restore)
+	router.DELETE("/profiles/:id/trash/:kind/:item", firebaseMiddleware.AuthMiddleware, permissionsMiddleware.TrashAdmin, handler.purge)
+}
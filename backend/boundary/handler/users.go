@@ -20,14 +20,17 @@ type UserController struct {
 // @Accept  json
 // @Produce  json
 // @Security BearerToken
+// @Param Idempotency-Key header string false "Replays the first response when a retry sends the same key"
 // @Param profile body models.Profile true "Profile object to be created"
 // @Success 201 {object} presenter.IdResponse
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 409 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /profiles [post]
 func (u *UserController) createProfile(c *gin.Context) {
 	var profile models.Profile
-	if err := c.Bind(&profile); err != nil {
+	if err := c.ShouldBindJSON(&profile); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
@@ -51,9 +54,10 @@ func (u *UserController) createProfile(c *gin.Context) {
 // @Security BearerToken
 // @Param id path string true "Profile ID"
 // @Success 200 {object} models.Profile
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
-// @Failure 500 {object} presenter.ErrorResponse
+// @Header 200 {string} ETag "Current version, for If-Match"
+// @Failure 400 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 500 {object} presenter.Problem
 // @Router /profiles/{id} [get]
 func (u *UserController) getProfile(c *gin.Context) {
 	id, err := GetId(c)
@@ -69,6 +73,7 @@ func (u *UserController) getProfile(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	} else {
+		SetETag(c, profile.Version)
 		c.JSON(http.StatusOK, profile)
 	}
 }
@@ -76,31 +81,47 @@ func (u *UserController) getProfile(c *gin.Context) {
 // Update a profile by ID
 // PATCH /profiles/:id
 // @Summary Update a profile by ID
-// @Description Update a profile by ID
+// @Description RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps and
+// @Description reputation cannot be changed.
 // @Tags Profiles
-// @Accept json
+// @Accept json,application/merge-patch+json
 // @Produce json
 // @Security BearerToken
 // @Param id path string true "Profile ID"
-// @Param application body models.Profile true "Profile object"
+// @Param If-Match header string false "ETag of the version being patched"
+// @Param profile body models.Profile true "Merge patch of the profile"
 // @Success 200 {object} models.Profile
-// @Failure 400 {object} presenter.ErrorResponse
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 400 {object} presenter.Problem
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
+// @Failure 415 {object} presenter.Problem
+// @Failure 422 {object} presenter.Problem
 // @Router /profiles/{id} [patch]
 func (u *UserController) updateProfile(c *gin.Context) {
-	var profile models.Profile
-	if err := c.Bind(&profile); err != nil {
+	id, err := GetId(c)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if profile, err := u.userService.UpdateProfile(c, profile); err != nil {
+	version, err := IfMatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	patch, err := MergePatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	out, err := u.userService.PatchProfile(c, id, patch, version)
+	if err != nil {
 		presenter.HandleErr(c, err)
 		return
-	} else {
-		c.JSON(http.StatusOK, profile)
 	}
+	SetETag(c, out.Version)
+	c.JSON(http.StatusOK, out)
 }
 
 // Delete a profile by ID
@@ -109,11 +130,13 @@ func (u *UserController) updateProfile(c *gin.Context) {
 // @Description Delete a profile by ID
 // @Tags Profiles
 // @Param id path string true "Profile ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Security BearerToken
 // @Success 204 "No Content"
-// @Failure 401 {object} presenter.ErrorResponse
-// @Failure 403 {object} presenter.ErrorResponse
-// @Failure 404 {object} presenter.ErrorResponse
+// @Failure 401 {object} presenter.Problem
+// @Failure 403 {object} presenter.Problem
+// @Failure 404 {object} presenter.Problem
+// @Failure 412 {object} presenter.Problem
 // @Router /profiles/{id} [delete]
 func (u *UserController) deleteProfile(c *gin.Context) {
 	id, err := GetId(c)
@@ -121,7 +144,12 @@ func (u *UserController) deleteProfile(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	}
-	if err := u.userService.DeleteProfile(c, id); err != nil {
+	version, err := IfMatch(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	if err := u.userService.DeleteProfile(c, id, version); err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
@@ -133,11 +161,21 @@ func RegisterUserController(
 	router *gin.RouterGroup,
 	firebase middleware.FirebaseMiddleware,
 	permissionsMiddleware middleware.PermissionsMiddleware,
+	idempotencyMiddleware middleware.IdempotencyMiddleware,
 ) {
 	handler := UserController{userService: service}
-	router.POST("/profiles", firebase.AuthMiddleware, handler.createProfile)
+	router.POST("/profiles", firebase.AuthMiddleware, idempotencyMiddleware.Idempotent, handler.createProfile)
 	router.GET("/profile/:id", firebase.AuthMiddleware, handler.getProfile)
 	router.PATCH("/profiles/:id", firebase.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.updateProfile)
 	router.DELETE("/profiles/:id", firebase.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.deleteProfile)
+	router.GET("/profiles/:id/ensemble/members", firebase.AuthMiddleware, handler.getMembers)
+	router.POST("/profiles/:id/ensemble/members", firebase.AuthMiddleware, permissionsMiddleware.ProfileAdmin, handler.invite)
+	router.DELETE("/profiles/:id/ensemble/members/:member_id", firebase.AuthMiddleware, permissionsMiddleware.ProfileAdmin,
+		handler.removeMember)
+	router.GET("/profiles/:id/ensembles", firebase.AuthMiddleware, permissionsMiddleware.ProfileModifier, handler.getEnsembles)
+	router.PUT("/profiles/:id/ensembles/:ensemble_id", firebase.AuthMiddleware, permissionsMiddleware.ProfileModifier,
+		handler.respondToInvitation)
+	router.DELETE("/profiles/:id/ensembles/:ensemble_id", firebase.AuthMiddleware, permissionsMiddleware.ProfileModifier,
+		handler.leaveEnsemble)
 	//return handler
 }
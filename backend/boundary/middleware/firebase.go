@@ -1,11 +1,12 @@
 package middleware
 
 import (
+	"backend/boundary/presenter"
+	"backend/domain"
 	"backend/models"
 	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 	"log"
-	"net/http"
 	"strings"
 )
 
@@ -23,9 +24,10 @@ func (m *FirebaseMiddleware) AuthMiddleware(c *gin.Context) {
 
 	if strings.HasPrefix(authHeader, "Basic") {
 		if strings.Split(authHeader, " ")[1] != m.SuperUserEncoded {
-			c.AbortWithStatusJSON(http.StatusForbidden, "invalid basic auth")
+			presenter.HandleErr(c, domain.Forbidden("invalid_basic_auth", "invalid basic auth"))
 			return
 		} else {
+			c.Set(models.ActorContextKey, models.Actor{Type: models.ActorSuperUser})
 			c.Next()
 			return
 		}
@@ -33,20 +35,22 @@ func (m *FirebaseMiddleware) AuthMiddleware(c *gin.Context) {
 	} else if m.Client == nil {
 		log.Printf("no firebase token")
 		c.Set(models.FirebaseContextKey, "test")
+		c.Set(models.ActorContextKey, models.Actor{Type: models.ActorFirebase, ID: "test"})
 		c.Next()
 		return
 	} else if strings.HasPrefix(authHeader, "Bearer") {
 		token := strings.Split(authHeader, " ")[1]
 		if firebaseToken, err := m.Client.VerifyIDToken(c, token); err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, "unable to verify id token")
+			presenter.HandleErr(c, domain.Unauthorized("invalid_id_token", "unable to verify id token"))
 			return
 		} else {
 			c.Set(models.FirebaseContextKey, firebaseToken.UID)
+			c.Set(models.ActorContextKey, models.Actor{Type: models.ActorFirebase, ID: firebaseToken.UID})
 			c.Next()
 			return
 		}
 	} else {
-		c.AbortWithStatus(http.StatusUnauthorized)
+		presenter.HandleErr(c, domain.ErrUnauthorized)
 		return
 	}
 
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"backend/boundary/presenter"
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/idempotency"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type IdempotencyMiddleware struct {
+	service idempotency.Service
+}
+
+func NewIdempotencyMiddleware(service idempotency.Service) IdempotencyMiddleware {
+	return IdempotencyMiddleware{service: service}
+}
+
+// recorder keeps a copy of everything the handler writes so it can be replayed.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotent replays the stored response when a caller retries a request with the same Idempotency-Key. Requests
+// without the header pass straight through. It needs the caller, so it goes after AuthMiddleware.
+func (m *IdempotencyMiddleware) Idempotent(c *gin.Context) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		presenter.HandleErr(c, domain.ErrBadRequest.WithMessage("unable to read body").Wrap(err))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + " " + c.Request.URL.RawQuery + "\n"))
+	hash.Write(body)
+
+	actor := models.ActorFromContext(c)
+	caller := actor.Type.String() + ":" + actor.ID
+	record, replay, err := m.service.Begin(c, caller, key, hex.EncodeToString(hash.Sum(nil)))
+	switch {
+	case err != nil:
+		presenter.HandleErr(c, err)
+		return
+	case replay:
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(record.StatusCode, record.ContentType, record.Body)
+		c.Abort()
+		return
+	}
+
+	writer := &recorder{ResponseWriter: c.Writer}
+	c.Writer = writer
+	stored := false
+	// Unless the response is stored the key is released, even when the handler panics, so the retry runs again
+	// instead of waiting out the lease.
+	defer func() {
+		if stored {
+			return
+		}
+		if err := m.service.Release(c, record); err != nil {
+			log.Printf("unable to release idempotency key %s: %v", key, err)
+		}
+	}()
+	c.Next()
+
+	// server errors are not worth replaying
+	if writer.Status() >= http.StatusInternalServerError {
+		return
+	}
+	record.StatusCode = writer.Status()
+	record.ContentType = writer.Header().Get("Content-Type")
+	record.Body = writer.body.Bytes()
+	if err := m.service.Complete(c, record); err != nil {
+		log.Printf("unable to store response for idempotency key %s: %v", key, err)
+		return
+	}
+	stored = true
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"backend/models"
+	"backend/usecase/idempotency"
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryRepo keeps idempotency records in memory, one per caller and key.
+type memoryRepo struct {
+	idempotency.Repository
+	records map[string]models.IdempotencyRecord
+}
+
+func (r *memoryRepo) Reserve(_ context.Context, record models.IdempotencyRecord, _ time.Time) (models.IdempotencyRecord, bool, error) {
+	if stored, ok := r.records[record.Caller+" "+record.Key]; ok {
+		return stored, false, nil
+	}
+	r.records[record.Caller+" "+record.Key] = record
+	return record, true, nil
+}
+
+func (r *memoryRepo) Complete(_ context.Context, record models.IdempotencyRecord) error {
+	r.records[record.Caller+" "+record.Key] = record
+	return nil
+}
+
+func (r *memoryRepo) Release(_ context.Context, record models.IdempotencyRecord) error {
+	delete(r.records, record.Caller+" "+record.Key)
+	return nil
+}
+
+func TestIdempotentTellsPathParametersApart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := idempotency.NewService(&memoryRepo{records: map[string]models.IdempotencyRecord{}}, time.Hour, time.Minute)
+	m := NewIdempotencyMiddleware(service)
+	calls := 0
+	router := gin.New()
+	router.POST("/events/:id/applications/status", m.Idempotent, func(c *gin.Context) {
+		calls++
+		c.String(http.StatusOK, c.Param("id"))
+	})
+	send := func(eventID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/events/"+eventID+"/applications/status", strings.NewReader(`{"status":"accepted"}`))
+		r.Header.Set(IdempotencyKeyHeader, "retry-1")
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := send("a"); w.Code != http.StatusOK || w.Body.String() != "a" {
+		t.Fatalf("first request = %d %q", w.Code, w.Body.String())
+	}
+	if w := send("a"); w.Header().Get("Idempotent-Replayed") != "true" || w.Body.String() != "a" || calls != 1 {
+		t.Errorf("retry = %d %q after %d calls, want the replayed response", w.Code, w.Body.String(), calls)
+	}
+	if w := send("b"); w.Code != http.StatusUnprocessableEntity || calls != 1 {
+		t.Errorf("same key for another event = %d %q, want it refused as reused", w.Code, w.Body.String())
+	}
+}
@@ -2,31 +2,52 @@ package middleware
 
 import (
 	"backend/boundary/presenter"
+	"backend/domain"
 	"backend/models"
 	"backend/usecase/agenda"
+	"backend/usecase/curation"
+	"backend/usecase/organizations"
 	"backend/usecase/users"
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
-	"net/http"
 )
 
 const ParamIdContextKey string = "contextId"
 
 type PermissionsMiddleware struct {
-	uService users.Service
-	aService agenda.Service
+	uService  users.Service
+	aService  agenda.Service
+	oService  organizations.Service
+	cuService curation.Service
 }
 
-func NewPermissionsMiddleware(uService users.Service, aService agenda.Service) PermissionsMiddleware {
-	return PermissionsMiddleware{uService: uService, aService: aService}
+func NewPermissionsMiddleware(
+	uService users.Service, aService agenda.Service, oService organizations.Service, cuService curation.Service,
+) PermissionsMiddleware {
+	return PermissionsMiddleware{uService: uService, aService: aService, oService: oService, cuService: cuService}
+}
+
+// blindReviewer answers for the callers assigned to review the event under a blind rubric, who only get to see its
+// applications through their review queue, and reports whether it did.
+func (m *PermissionsMiddleware) blindReviewer(c *gin.Context, event models.Event, firebaseId interface{}) bool {
+	id, _ := firebaseId.(string)
+	blind, err := m.cuService.IsBlindReviewer(c, event, id)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return true
+	}
+	if blind {
+		presenter.HandleErr(c, curation.ErrBlindReviewer)
+		return true
+	}
+	return false
 }
 
 func (m *PermissionsMiddleware) setID(c *gin.Context) (uuid.UUID, error) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": errors.Wrap(err, "unable to parse id").Error()})
-		return uuid.Nil, errors.New("finished call")
+		return uuid.Nil, domain.ErrBadRequest.WithMessage("unable to parse id").Wrap(err)
 	}
 	c.Set(ParamIdContextKey, id)
 	return id, nil
@@ -35,6 +56,7 @@ func (m *PermissionsMiddleware) setID(c *gin.Context) (uuid.UUID, error) {
 func (m *PermissionsMiddleware) ProfileModifier(c *gin.Context) {
 	profileId, err := m.setID(c)
 	if err != nil {
+		presenter.HandleErr(c, err)
 		return
 	}
 	firebaseId, exists := c.Get(models.FirebaseContextKey)
@@ -43,7 +65,7 @@ func (m *PermissionsMiddleware) ProfileModifier(c *gin.Context) {
 		return
 	}
 
-	_users, err := m.uService.GetUsersByProfileId(c, profileId)
+	_users, err := m.uService.GetAuthorizedUsers(c, profileId)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
@@ -55,9 +77,45 @@ func (m *PermissionsMiddleware) ProfileModifier(c *gin.Context) {
 		}
 	}
 
-	c.Status(http.StatusForbidden)
-	return
+	presenter.HandleErr(c, domain.ErrForbidden)
+}
+
+// ProfileAdmin is ProfileModifier restricted to members holding the admin permission.
+func (m *PermissionsMiddleware) ProfileAdmin(c *gin.Context) {
+	m.profileAdmin(c, m.uService.GetAuthorizedUsers)
+}
+
+// TrashAdmin is ProfileAdmin for the trash, which has to stay reachable while the profile itself is in it.
+func (m *PermissionsMiddleware) TrashAdmin(c *gin.Context) {
+	m.profileAdmin(c, m.uService.GetAuthorizedUsersUnscoped)
+}
+
+func (m *PermissionsMiddleware) profileAdmin(
+	c *gin.Context, authorizedUsers func(ctx context.Context, id uuid.UUID) ([]models.UserID, error),
+) {
+	profileId, err := m.setID(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseId, exists := c.Get(models.FirebaseContextKey)
+	if !exists {
+		c.Next()
+		return
+	}
 
+	_users, err := authorizedUsers(c, profileId)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	for _, user := range _users {
+		if user.FirebaseId == firebaseId && user.Permissions == models.Admin {
+			c.Next()
+			return
+		}
+	}
+	presenter.HandleErr(c, domain.ErrForbidden)
 }
 
 func (m *PermissionsMiddleware) ApplicationViewer(c *gin.Context) {
@@ -78,29 +136,47 @@ func (m *PermissionsMiddleware) ApplicationViewer(c *gin.Context) {
 	}
 	event, err := m.aService.GetEvent(c, app.EventRef)
 	if err != nil {
-		c.Next()
+		presenter.HandleErr(c, err)
+		return
 	}
-	appUsers, err := m.uService.GetUsersByProfileId(c, app.PerformerID)
+	appUsers, err := m.uService.GetAuthorizedUsers(c, app.PerformerID)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
-	eventUsers, err := m.uService.GetUsersByProfileId(c, event.ProducerID)
+	eventUsers, err := m.uService.GetAuthorizedUsers(c, event.ProducerID)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
 	}
-	profileUsers := append(appUsers, eventUsers...)
-	for _, p := range profileUsers {
+	for _, p := range appUsers {
 		if p.FirebaseId == firebaseID {
 			c.Next()
 			return
 		}
 	}
-	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"how": "did we get here?"})
+	for _, p := range eventUsers {
+		if p.FirebaseId == firebaseID {
+			if !m.blindReviewer(c, event, firebaseID) {
+				c.Next()
+			}
+			return
+		}
+	}
+	presenter.HandleErr(c, domain.ErrForbidden)
 }
 
 func (m *PermissionsMiddleware) EventModifier(c *gin.Context) {
+	m.eventMember(c, false)
+}
+
+// EventApplicationsViewer is EventModifier for the routes that show who applied, which the event's reviewers may not
+// use while its rubric is blind.
+func (m *PermissionsMiddleware) EventApplicationsViewer(c *gin.Context) {
+	m.eventMember(c, true)
+}
+
+func (m *PermissionsMiddleware) eventMember(c *gin.Context, notBlindReviewer bool) {
 	firebaseId, ok := c.Get(models.FirebaseContextKey)
 	if !ok {
 		c.Next()
@@ -108,19 +184,28 @@ func (m *PermissionsMiddleware) EventModifier(c *gin.Context) {
 	}
 	eventId, err := m.setID(c)
 	if err != nil {
+		presenter.HandleErr(c, err)
 		return
 	}
 	event, err := m.aService.GetEvent(c, eventId)
-	profileUsers, err := m.uService.GetUsersByProfileId(c, event.ProducerID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	profileUsers, err := m.uService.GetAuthorizedUsers(c, event.ProducerID)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
 	for _, p := range profileUsers {
 		if p.FirebaseId == firebaseId {
-			c.Next()
+			if !notBlindReviewer || !m.blindReviewer(c, event, firebaseId) {
+				c.Next()
+			}
 			return
 		}
 	}
-	c.Status(http.StatusForbidden)
-	return
-
+	presenter.HandleErr(c, domain.ErrForbidden)
 }
 
 func (m *PermissionsMiddleware) ApplicationModifier(c *gin.Context) {
@@ -139,7 +224,7 @@ func (m *PermissionsMiddleware) ApplicationModifier(c *gin.Context) {
 		presenter.HandleErr(c, err)
 		return
 	}
-	profileUsers, err := m.uService.GetUsersByProfileId(c, app.Performer.ID)
+	profileUsers, err := m.uService.GetAuthorizedUsers(c, app.PerformerID)
 	if err != nil {
 		presenter.HandleErr(c, err)
 		return
@@ -150,12 +235,34 @@ func (m *PermissionsMiddleware) ApplicationModifier(c *gin.Context) {
 			return
 		}
 	}
-	c.Status(http.StatusForbidden)
+	presenter.HandleErr(c, domain.ErrForbidden)
+}
+
+// OrganizationAdmin lets the admins of the organization on :id through.
+func (m *PermissionsMiddleware) OrganizationAdmin(c *gin.Context) {
+	organizationID, err := m.setID(c)
+	if err != nil {
+		presenter.HandleErr(c, err)
+		return
+	}
+	firebaseId, exists := c.Get(models.FirebaseContextKey)
+	if !exists {
+		c.Next()
+		return
+	}
+	if ok, err := m.oService.IsAdmin(c, organizationID, firebaseId.(string)); err != nil {
+		presenter.HandleErr(c, err)
+		return
+	} else if !ok {
+		presenter.HandleErr(c, domain.ErrForbidden)
+		return
+	}
+	c.Next()
 }
 
 func (m *PermissionsMiddleware) Admin(c *gin.Context) {
 	if _, exists := c.Get(models.FirebaseContextKey); exists {
-		c.Status(http.StatusUnauthorized)
+		presenter.HandleErr(c, domain.ErrUnauthorized.WithMessage("only the super user may call this endpoint"))
 		return
 	}
 	c.Next()
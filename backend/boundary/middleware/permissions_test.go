@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"backend/models"
+	"backend/usecase/agenda"
+	"backend/usecase/curation"
+	"backend/usecase/organizations"
+	"backend/usecase/users"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// authorizedRepo answers GetAuthorizedUsers with the users stored for the profile, organization admins included.
+type authorizedRepo struct {
+	users.Repository
+	authorized map[uuid.UUID][]models.UserID
+}
+
+func (r *authorizedRepo) GetAuthorizedUsers(_ context.Context, id uuid.UUID) ([]models.UserID, error) {
+	return r.authorized[id], nil
+}
+
+type adminRepo struct {
+	organizations.Repository
+	admins map[uuid.UUID][]string
+}
+
+func (r *adminRepo) GetAdmin(_ context.Context, organizationID uuid.UUID, firebaseID string) (models.OrganizationAdmin, error) {
+	for _, admin := range r.admins[organizationID] {
+		if admin == firebaseID {
+			return models.OrganizationAdmin{Model: models.Model{ID: uuid.New()}, FirebaseId: admin}, nil
+		}
+	}
+	return models.OrganizationAdmin{}, nil
+}
+
+func TestProfileAndOrganizationPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	profileID, organizationID := uuid.New(), uuid.New()
+	uService := users.NewService(&authorizedRepo{authorized: map[uuid.UUID][]models.UserID{profileID: {
+		{FirebaseId: "booker", Permissions: models.Restricted, ProfileId: profileID},
+		{FirebaseId: "org-admin", Permissions: models.Admin, ProfileId: profileID},
+	}}}, nil)
+	oService := organizations.NewService(&adminRepo{admins: map[uuid.UUID][]string{organizationID: {"org-admin"}}}, nil, nil)
+	m := NewPermissionsMiddleware(uService, agenda.Service{}, oService, curation.Service{})
+
+	tests := []struct {
+		name       string
+		guard      gin.HandlerFunc
+		id         uuid.UUID
+		firebaseID string
+		wantStatus int
+	}{
+		{name: "organization admin modifies a profile", guard: m.ProfileModifier, id: profileID, firebaseID: "org-admin", wantStatus: http.StatusOK},
+		{name: "organization admin administers a profile", guard: m.ProfileAdmin, id: profileID, firebaseID: "org-admin", wantStatus: http.StatusOK},
+		{name: "restricted member modifies a profile", guard: m.ProfileModifier, id: profileID, firebaseID: "booker", wantStatus: http.StatusOK},
+		{name: "restricted member administers a profile", guard: m.ProfileAdmin, id: profileID, firebaseID: "booker", wantStatus: http.StatusForbidden},
+		{name: "outsider modifies a profile", guard: m.ProfileModifier, id: profileID, firebaseID: "someone", wantStatus: http.StatusForbidden},
+		{name: "organization admin", guard: m.OrganizationAdmin, id: organizationID, firebaseID: "org-admin", wantStatus: http.StatusOK},
+		{name: "profile member administers the organization", guard: m.OrganizationAdmin, id: organizationID, firebaseID: "booker", wantStatus: http.StatusForbidden},
+		{name: "super user", guard: m.OrganizationAdmin, id: organizationID, wantStatus: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/:id", func(c *gin.Context) {
+				if test.firebaseID != "" {
+					c.Set(models.FirebaseContextKey, test.firebaseID)
+				}
+			}, test.guard, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+test.id.String(), nil))
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"backend/boundary/presenter"
+	"backend/domain"
+	"github.com/gin-gonic/gin"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is how many requests a minute one client IP may make to a rate limited group.
+const DefaultRateLimit = 60
+
+var ErrRateLimited = domain.TooManyRequests("rate_limited", "too many requests from this address, retry later")
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type buckets struct {
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+// RateLimitMiddleware is a token bucket per client IP: each IP may burst up to its limit and regains the limit every
+// minute. Buckets live in memory, so every replica counts on its own.
+type RateLimitMiddleware struct {
+	perMinute int
+	buckets   *buckets
+}
+
+func NewRateLimitMiddleware(perMinute int) RateLimitMiddleware {
+	if perMinute <= 0 {
+		perMinute = DefaultRateLimit
+	}
+	return RateLimitMiddleware{perMinute: perMinute, buckets: &buckets{clients: map[string]*bucket{}}}
+}
+
+// take spends a token of the client's bucket. It returns the tokens left, or how long until one is available.
+func (m *RateLimitMiddleware) take(client string, now time.Time) (int, time.Duration) {
+	limit := float64(m.perMinute)
+	perSecond := limit / 60
+	m.buckets.mu.Lock()
+	defer m.buckets.mu.Unlock()
+	// A bucket idle for a minute is full again, the same as no bucket at all.
+	if now.Sub(m.buckets.lastSweep) > time.Minute {
+		for key, b := range m.buckets.clients {
+			if now.Sub(b.last) > time.Minute {
+				delete(m.buckets.clients, key)
+			}
+		}
+		m.buckets.lastSweep = now
+	}
+	b, ok := m.buckets.clients[client]
+	if !ok {
+		b = &bucket{tokens: limit, last: now}
+		m.buckets.clients[client] = b
+	}
+	b.tokens = math.Min(limit, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	b.tokens--
+	return int(b.tokens), 0
+}
+
+// Limit rejects requests over the client IP's limit with 429 and Retry-After, and tells every caller where they stand
+// in X-RateLimit-Limit and X-RateLimit-Remaining.
+func (m *RateLimitMiddleware) Limit(c *gin.Context) {
+	remaining, wait := m.take(c.ClientIP(), time.Now())
+	c.Header("X-RateLimit-Limit", strconv.Itoa(m.perMinute))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		presenter.HandleErr(c, ErrRateLimited)
+		return
+	}
+	c.Next()
+}
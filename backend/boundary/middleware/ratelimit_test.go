@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	type step struct {
+		client        string
+		at            time.Duration
+		wantRemaining int
+		wantWait      time.Duration
+	}
+	tests := []struct {
+		name      string
+		perMinute int
+		steps     []step
+	}{
+		{
+			name:      "burst up to the limit",
+			perMinute: 3,
+			steps: []step{
+				{client: "a", wantRemaining: 2},
+				{client: "a", wantRemaining: 1},
+				{client: "a", wantRemaining: 0},
+				{client: "a", wantWait: 20 * time.Second},
+				{client: "b", wantRemaining: 2},
+			},
+		},
+		{
+			name:      "tokens come back over the minute",
+			perMinute: 60,
+			steps: []step{
+				{client: "a", wantRemaining: 59},
+				{client: "a", at: 500 * time.Millisecond, wantRemaining: 58},
+				{client: "a", at: 10 * time.Second, wantRemaining: 59},
+			},
+		},
+		{
+			name:      "a refused request spends nothing",
+			perMinute: 1,
+			steps: []step{
+				{client: "a", wantRemaining: 0},
+				{client: "a", at: 15 * time.Second, wantWait: 45 * time.Second},
+				{client: "a", at: 30 * time.Second, wantWait: 30 * time.Second},
+				{client: "a", at: time.Minute, wantRemaining: 0},
+			},
+		},
+		{
+			name:      "an idle bucket is full again",
+			perMinute: 2,
+			steps: []step{
+				{client: "a", wantRemaining: 1},
+				{client: "a", wantRemaining: 0},
+				{client: "b", at: 2 * time.Minute, wantRemaining: 1},
+				{client: "a", at: 2 * time.Minute, wantRemaining: 1},
+			},
+		},
+		{name: "default limit", perMinute: 0, steps: []step{{client: "a", wantRemaining: DefaultRateLimit - 1}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewRateLimitMiddleware(test.perMinute)
+			for i, step := range test.steps {
+				remaining, wait := m.take(step.client, start.Add(step.at))
+				if remaining != step.wantRemaining || wait != step.wantWait {
+					t.Errorf("step %d: take(%q) = %d, %v, want %d, %v", i, step.client, remaining, wait, step.wantRemaining, step.wantWait)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewRateLimitMiddleware(1)
+	router := gin.New()
+	router.GET("/", m.Limit, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		wantStatus     int
+		wantRemaining  string
+		wantRetryAfter string
+	}{
+		{wantStatus: http.StatusNoContent, wantRemaining: "0"},
+		{wantStatus: http.StatusTooManyRequests, wantRemaining: "0", wantRetryAfter: "60"},
+	}
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != test.wantStatus {
+			t.Errorf("request %d: status = %d, want %d", i, w.Code, test.wantStatus)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "1" {
+			t.Errorf("request %d: X-RateLimit-Limit = %q, want %q", i, got, "1")
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != test.wantRemaining {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %q", i, got, test.wantRemaining)
+		}
+		if got := w.Header().Get("Retry-After"); got != test.wantRetryAfter {
+			t.Errorf("request %d: Retry-After = %q, want %q", i, got, test.wantRetryAfter)
+		}
+	}
+}
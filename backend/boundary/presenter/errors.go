@@ -1,23 +1,86 @@
 package presenter
 
 import (
+	"backend/domain"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
-	"github.com/jinzhu/gorm"
+	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"io"
+	"log"
 	"net/http"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+const ProblemContentType = "application/problem+json"
+
+// Problem is the RFC 7807 body of every error response. Code is stable and machine-readable; Errors lists the
+// offending fields of a rejected request.
+type Problem struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Code   string              `json:"code"`
+	Detail string              `json:"detail,omitempty"`
+	Errors []domain.FieldError `json:"errors,omitempty"`
 }
 
-func HandleErr(c *gin.Context, err error) {
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
-		return
+var kindStatus = map[domain.Kind]int{
+	domain.KindBadRequest:         http.StatusBadRequest,
+	domain.KindValidation:         http.StatusUnprocessableEntity,
+	domain.KindUnauthorized:       http.StatusUnauthorized,
+	domain.KindForbidden:          http.StatusForbidden,
+	domain.KindNotFound:           http.StatusNotFound,
+	domain.KindConflict:           http.StatusConflict,
+	domain.KindGone:               http.StatusGone,
+	domain.KindPreconditionFailed: http.StatusPreconditionFailed,
+	domain.KindUnsupportedMedia:   http.StatusUnsupportedMediaType,
+	domain.KindTooManyRequests:    http.StatusTooManyRequests,
+}
+
+// toDomain turns the errors that never pass through a service, such as malformed JSON, into domain errors.
+// Anything else is internal.
+func toDomain(err error) *domain.Error {
+	if domainErr, ok := domain.As(err); ok {
+		return domainErr
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return domain.ErrInvalid.WithFields(domain.FieldError{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()})
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return domain.BadRequest("invalid_json", "the request body is not valid JSON").Wrap(err)
 	}
-	if errors.Is(err, &gin.Error{}) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if errors.Is(err, io.EOF) {
+		return domain.BadRequest("empty_body", "the request body is empty")
+	}
+	var bindErrs validator.ValidationErrors
+	if errors.As(err, &bindErrs) {
+		fields := make([]domain.FieldError, 0, len(bindErrs))
+		for _, fieldErr := range bindErrs {
+			fields = append(fields, domain.FieldError{Field: fieldErr.Field(), Message: "failed the " + fieldErr.Tag() + " check"})
+		}
+		return domain.ErrInvalid.WithFields(fields...)
+	}
+	return domain.New(domain.KindInternal, "internal", "internal server error").Wrap(err)
+}
+
+// HandleErr aborts the request with the problem matching err. Only the domain message and fields are echoed; what
+// caused the error stays out of the response, and internal errors are logged.
+func HandleErr(c *gin.Context, err error) {
+	domainErr := toDomain(err)
+	status, ok := kindStatus[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
 	}
-	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   domainErr.Code,
+		Detail: domainErr.Message,
+		Errors: domainErr.Fields,
+	})
 }
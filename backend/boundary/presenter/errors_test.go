@@ -0,0 +1,80 @@
+package presenter
+
+import (
+	"backend/domain"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := errors.New(`pq: duplicate key value violates unique constraint "idx_secret"`)
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantFields int
+	}{
+		{
+			name:       "wrapped domain error",
+			err:        domain.NotFound("no_event", "event not found").Wrap(secret),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "no_event",
+			wantDetail: "event not found",
+		},
+		{
+			name:       "fields stay in errors",
+			err:        domain.ErrInvalid.WithFields(domain.FieldError{Field: "body", Message: "is required"}).Wrap(secret),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   domain.ErrInvalid.Code,
+			wantDetail: domain.ErrInvalid.Message,
+			wantFields: 1,
+		},
+		{
+			name:       "internal",
+			err:        secret,
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal",
+			wantDetail: "internal server error",
+		},
+		{
+			name:       "malformed JSON",
+			err:        &json.SyntaxError{Offset: 3},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_json",
+			wantDetail: "the request body is not valid JSON",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/events/1", nil)
+			HandleErr(c, test.err)
+
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+			if strings.Contains(w.Body.String(), "idx_secret") {
+				t.Errorf("the response leaks the cause: %s", w.Body.String())
+			}
+			var problem Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("unable to decode the problem: %v", err)
+			}
+			if problem.Code != test.wantCode || problem.Detail != test.wantDetail {
+				t.Errorf("problem = %q %q, want %q %q", problem.Code, problem.Detail, test.wantCode, test.wantDetail)
+			}
+			if len(problem.Errors) != test.wantFields {
+				t.Errorf("problem lists %d fields, want %d", len(problem.Errors), test.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package presenter
+
+import (
+	"backend/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ParseExportFormat defaults to CSV when s is empty.
+func ParseExportFormat(s string) (ExportFormat, bool) {
+	switch format := ExportFormat(strings.ToLower(s)); format {
+	case "":
+		return ExportCSV, true
+	case ExportCSV, ExportJSON, ExportXLSX:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportJSON:
+		return "application/json"
+	case ExportXLSX:
+		return XLSXContentType
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ApplicationExport is one row of an applications export: the application and the performer behind it.
+type ApplicationExport struct {
+	ApplicationID  uuid.UUID                `json:"application_id"`
+	Name           string                   `json:"name"`
+	Status         models.ApplicationStatus `json:"status"`
+	SubmittedAt    time.Time                `json:"submitted_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	PerformerID    uuid.UUID                `json:"performer_id"`
+	PerformerName  string                   `json:"performer_name"`
+	PerformerType  models.ProfileType       `json:"performer_type"`
+	Latitude       *float64                 `json:"latitude,omitempty"`
+	Longitude      *float64                 `json:"longitude,omitempty"`
+	RatingAverage  float64                  `json:"rating_average"`
+	RatingCount    int                      `json:"rating_count"`
+	PortfolioLinks []string                 `json:"portfolio_links"`
+	// Members are the names of an ensemble's active members.
+	Members []string `json:"members"`
+}
+
+var applicationExportHeader = []string{
+	"application_id", "name", "status", "submitted_at", "updated_at", "performer_id", "performer_name",
+	"performer_type", "latitude", "longitude", "rating_average", "rating_count", "portfolio_links", "members",
+}
+
+func NewApplicationExport(application models.Application) ApplicationExport {
+	performer := application.Performer
+	row := ApplicationExport{
+		ApplicationID:  application.ID,
+		Name:           application.Name,
+		Status:         application.Status,
+		SubmittedAt:    application.CreatedAt,
+		UpdatedAt:      application.UpdatedAt,
+		PerformerID:    application.PerformerID,
+		PerformerName:  performer.Name,
+		PerformerType:  performer.ProfileType,
+		PortfolioLinks: performer.PortfolioLinks,
+	}
+	if row.PortfolioLinks == nil {
+		row.PortfolioLinks = []string{}
+	}
+	row.Members = make([]string, len(application.Members))
+	for i, member := range application.Members {
+		row.Members[i] = member.Name
+	}
+	if performer.Location != nil {
+		row.Latitude, row.Longitude = &performer.Location.Lat, &performer.Location.Lng
+	}
+	if performer.Reputation != nil {
+		row.RatingAverage, row.RatingCount = performer.Reputation.Average, performer.Reputation.Count
+	}
+	return row
+}
+
+// cells flattens the row for CSV and XLSX. Portfolio links are separated by spaces, which URLs cannot contain, and
+// member names by semicolons.
+func (e ApplicationExport) cells() []string {
+	optional := func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*f, 'f', -1, 64)
+	}
+	return []string{
+		e.ApplicationID.String(), e.Name, string(e.Status), e.SubmittedAt.UTC().Format(time.RFC3339),
+		e.UpdatedAt.UTC().Format(time.RFC3339), e.PerformerID.String(), e.PerformerName, string(e.PerformerType),
+		optional(e.Latitude), optional(e.Longitude), strconv.FormatFloat(e.RatingAverage, 'f', 2, 64),
+		strconv.Itoa(e.RatingCount), strings.Join(e.PortfolioLinks, " "), strings.Join(e.Members, "; "),
+	}
+}
+
+// ApplicationExporter writes rows as they arrive, so an export never holds a whole event in memory. The output is
+// only complete once Close has returned.
+type ApplicationExporter interface {
+	Write(row ApplicationExport) error
+	Close() error
+}
+
+func NewApplicationExporter(format ExportFormat, w io.Writer) (ApplicationExporter, error) {
+	switch format {
+	case ExportCSV:
+		exporter := &csvExporter{w: csv.NewWriter(w)}
+		return exporter, exporter.w.Write(applicationExportHeader)
+	case ExportJSON:
+		_, err := io.WriteString(w, "[")
+		return &jsonExporter{w: w}, err
+	case ExportXLSX:
+		x, err := NewXLSXWriter(w, "Applications")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExporter{x: x}, x.WriteRow(applicationExportHeader)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) Write(row ApplicationExport) error {
+	cells := row.cells()
+	for i, cell := range cells {
+		cells[i] = spreadsheetSafe(cell)
+	}
+	return e.w.Write(cells)
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// spreadsheetSafe keeps a spreadsheet from evaluating applicant-supplied text that opens a CSV cell as a formula.
+func spreadsheetSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+type jsonExporter struct {
+	w    io.Writer
+	rows int
+}
+
+func (e *jsonExporter) Write(row ApplicationExport) error {
+	body, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if e.rows > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.rows++
+	_, err = e.w.Write(body)
+	return err
+}
+
+func (e *jsonExporter) Close() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+type xlsxExporter struct {
+	x *XLSXWriter
+}
+
+func (e *xlsxExporter) Write(row ApplicationExport) error { return e.x.WriteRow(row.cells()) }
+func (e *xlsxExporter) Close() error                      { return e.x.Close() }
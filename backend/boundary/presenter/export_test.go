@@ -0,0 +1,118 @@
+package presenter
+
+import (
+	"backend/models"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   ExportFormat
+		wantOK bool
+	}{
+		{in: "", want: ExportCSV, wantOK: true},
+		{in: "CSV", want: ExportCSV, wantOK: true},
+		{in: "json", want: ExportJSON, wantOK: true},
+		{in: "xlsx", want: ExportXLSX, wantOK: true},
+		{in: "pdf", wantOK: false},
+	}
+	for _, test := range tests {
+		if got, ok := ParseExportFormat(test.in); got != test.want || ok != test.wantOK {
+			t.Errorf("ParseExportFormat(%q) = %q, %v, want %q, %v", test.in, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func testApplications() []models.Application {
+	submitted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []models.Application{
+		{
+			Model: models.Model{ID: uuid.New(), CreatedAt: submitted, UpdatedAt: submitted}, Name: "=HYPERLINK(1)",
+			Status: models.StatusAccepted, PerformerID: uuid.New(),
+			Performer: models.Profile{
+				Name: "The Band", ProfileType: models.PerformerType, Location: &gormGIS.GeoPoint{Lat: 52.5, Lng: 13.4},
+				Reputation: &models.Reputation{Average: 4.5, Count: 2}, PortfolioLinks: models.PortfolioLinks{"https://a.example", "https://b.example"},
+			},
+			Members: []models.Profile{{Name: "Ann"}, {Name: "Bo"}},
+		},
+		{
+			Model: models.Model{ID: uuid.New(), CreatedAt: submitted, UpdatedAt: submitted}, Name: "Solo",
+			Status: models.StatusPending, PerformerID: uuid.New(),
+			Performer: models.Profile{Name: "Solo", ProfileType: models.PerformerType},
+		},
+	}
+}
+
+func TestApplicationExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	exporter, err := NewApplicationExporter(ExportCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewApplicationExporter() error = %v", err)
+	}
+	applications := testApplications()
+	for _, application := range applications {
+		if err := exporter.Write(NewApplicationExport(application)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("the export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], applicationExportHeader) {
+		t.Fatalf("export = %v, want the header and 2 rows", records)
+	}
+	want := []string{
+		applications[0].ID.String(), "'=HYPERLINK(1)", "accepted", "2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z",
+		applications[0].PerformerID.String(), "The Band", "performer", "52.5", "13.4", "4.50", "2",
+		"https://a.example https://b.example", "Ann; Bo",
+	}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("row = %q\nwant  %q", records[1], want)
+	}
+	if records[2][8] != "" || records[2][12] != "" || records[2][13] != "" {
+		t.Errorf("a performer without location, links or members exports %q", records[2])
+	}
+}
+
+func TestApplicationExportJSON(t *testing.T) {
+	for _, count := range []int{0, 2} {
+		var buf bytes.Buffer
+		exporter, err := NewApplicationExporter(ExportJSON, &buf)
+		if err != nil {
+			t.Fatalf("NewApplicationExporter() error = %v", err)
+		}
+		for _, application := range testApplications()[:count] {
+			if err := exporter.Write(NewApplicationExport(application)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+		}
+		if err := exporter.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		var rows []ApplicationExport
+		if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+			t.Fatalf("the export of %d rows is not valid JSON: %v\n%s", count, err, buf.String())
+		}
+		if len(rows) != count {
+			t.Errorf("exported %d rows, want %d", len(rows), count)
+		}
+		for _, row := range rows {
+			if row.PortfolioLinks == nil || row.Members == nil {
+				t.Errorf("row %s exports null lists", row.Name)
+			}
+		}
+	}
+}
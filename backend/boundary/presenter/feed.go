@@ -0,0 +1,287 @@
+package presenter
+
+import (
+	"backend/models"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/google/uuid"
+	"html/template"
+	"strings"
+	"time"
+)
+
+type FeedFormat string
+
+const (
+	FeedRSS  FeedFormat = "rss"
+	FeedAtom FeedFormat = "atom"
+	FeedJSON FeedFormat = "json"
+	// FeedHTML is a plain list of the events for embedding in another site, say in an iframe.
+	FeedHTML FeedFormat = "html"
+)
+
+// ParseFeedFormat defaults to RSS when s is empty.
+func ParseFeedFormat(s string) (FeedFormat, bool) {
+	switch format := FeedFormat(strings.ToLower(s)); format {
+	case "":
+		return FeedRSS, true
+	case FeedRSS, FeedAtom, FeedJSON, FeedHTML:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+func (f FeedFormat) ContentType() string {
+	switch f {
+	case FeedAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FeedJSON:
+		return "application/feed+json; charset=utf-8"
+	case FeedHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/rss+xml; charset=utf-8"
+	}
+}
+
+// FeedLinks are the absolute URLs a feed points at: the feed itself and the page of each event.
+type FeedLinks struct {
+	Self  string
+	Event func(id uuid.UUID) string
+}
+
+const feedTimeLayout = "Mon, 2 Jan 2006 15:04 MST"
+
+// summary is the plain text line under an event's name: when it starts, where and when to apply by.
+func summary(event models.PublicEvent) string {
+	parts := []string{"Starts " + event.Time.UTC().Format(feedTimeLayout)}
+	if event.Venue != nil {
+		parts = append(parts, "at "+event.Venue.Name)
+	}
+	text := strings.Join(parts, " ") + "."
+	if event.ApplyByTime != nil {
+		text += " Apply by " + event.ApplyByTime.UTC().Format(feedTimeLayout) + "."
+	}
+	if event.Description != "" {
+		text += "\n\n" + event.Description
+	}
+	return text
+}
+
+// WriteFeed renders the feed in the format.
+func WriteFeed(format FeedFormat, feed models.Feed, links FeedLinks) ([]byte, error) {
+	switch format {
+	case FeedAtom:
+		return atomFeed(feed, links)
+	case FeedJSON:
+		return jsonFeed(feed, links)
+	case FeedHTML:
+		return htmlFeed(feed, links)
+	default:
+		return rssFeed(feed, links)
+	}
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+func rssFeed(feed models.Feed, links FeedLinks) ([]byte, error) {
+	channel := rssChannel{
+		Title: feed.Title, Link: links.Self, Description: feed.Description,
+		LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z), Items: make([]rssItem, len(feed.Events)),
+	}
+	for i, event := range feed.Events {
+		channel.Items[i] = rssItem{
+			Title: event.Name, Link: links.Event(event.ID), Description: summary(event), Categories: event.Tags,
+			GUID: rssGUID{Value: event.ID.URN()}, PubDate: event.UpdatedAt.UTC().Format(time.RFC1123Z),
+		}
+	}
+	return marshalXML(rss{Version: "2.0", Channel: channel})
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Link       atomLink       `xml:"link"`
+	Author     atomPerson     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Updated  string      `xml:"updated"`
+	Link     atomLink    `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+func atomFeed(feed models.Feed, links FeedLinks) ([]byte, error) {
+	out := atom{
+		ID: links.Self, Title: feed.Title, Subtitle: feed.Description, Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Link: atomLink{Href: links.Self, Rel: "self"}, Entries: make([]atomEntry, len(feed.Events)),
+	}
+	for i, event := range feed.Events {
+		entry := atomEntry{
+			ID: event.ID.URN(), Title: event.Name, Updated: event.UpdatedAt.UTC().Format(time.RFC3339),
+			Link: atomLink{Href: links.Event(event.ID)}, Author: atomPerson{Name: event.Producer.Name},
+			Summary: summary(event),
+		}
+		for _, tag := range event.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		out.Entries[i] = entry
+	}
+	return marshalXML(out)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonFeedEvent is the _ocall extension of an item: what a JSON Feed item has no field for.
+type jsonFeedEvent struct {
+	StartsAt          time.Time                     `json:"starts_at"`
+	ApplyBy           *time.Time                    `json:"apply_by,omitempty"`
+	ApplicationStatus models.EventApplicationStatus `json:"application_status"`
+	Venue             *models.PublicProfile         `json:"venue,omitempty"`
+	Pay               *models.PayStructure          `json:"pay,omitempty"`
+}
+
+type jsonFeedItem struct {
+	ID           string           `json:"id"`
+	URL          string           `json:"url"`
+	Title        string           `json:"title"`
+	ContentText  string           `json:"content_text"`
+	DateModified time.Time        `json:"date_modified"`
+	Tags         []string         `json:"tags,omitempty"`
+	Authors      []jsonFeedAuthor `json:"authors"`
+	Event        jsonFeedEvent    `json:"_ocall"`
+}
+
+type jsonFeedDocument struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+func jsonFeed(feed models.Feed, links FeedLinks) ([]byte, error) {
+	out := jsonFeedDocument{
+		Version: "https://jsonfeed.org/version/1.1", Title: feed.Title, Description: feed.Description,
+		FeedURL: links.Self, Items: make([]jsonFeedItem, len(feed.Events)),
+	}
+	for i, event := range feed.Events {
+		out.Items[i] = jsonFeedItem{
+			ID: event.ID.String(), URL: links.Event(event.ID), Title: event.Name, ContentText: summary(event),
+			DateModified: event.UpdatedAt.UTC(), Tags: event.Tags, Authors: []jsonFeedAuthor{{Name: event.Producer.Name}},
+			Event: jsonFeedEvent{
+				StartsAt: event.Time.UTC(), ApplyBy: event.ApplyByTime, ApplicationStatus: event.ApplicationStatus,
+				Venue: event.Venue, Pay: event.Pay,
+			},
+		}
+	}
+	return json.Marshal(out)
+}
+
+var widget = template.Must(template.New("widget").Funcs(template.FuncMap{
+	"when": func(t time.Time) string { return t.UTC().Format(feedTimeLayout) },
+	"iso":  func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 0; }
+ul { list-style: none; margin: 0; padding: 0; }
+li { padding: 0.5em 0; border-bottom: 1px solid #ddd; }
+.when, .apply-by { display: block; font-size: 0.85em; color: #555; }
+</style>
+</head>
+<body>
+<ul>
+{{- range .Events}}
+<li><a href="{{.URL}}" target="_blank" rel="noopener">{{.Name}}</a>
+<span class="when"><time datetime="{{iso .Time}}">{{when .Time}}</time>{{with .Venue}} at {{.Name}}{{end}}</span>
+{{- with .ApplyByTime}}
+<span class="apply-by">Apply by <time datetime="{{iso .}}">{{when .}}</time></span>
+{{- end}}
+</li>
+{{- else}}
+<li>No open calls for performers right now.</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+type widgetEvent struct {
+	models.PublicEvent
+	URL string
+}
+
+func htmlFeed(feed models.Feed, links FeedLinks) ([]byte, error) {
+	events := make([]widgetEvent, len(feed.Events))
+	for i, event := range feed.Events {
+		events[i] = widgetEvent{PublicEvent: event, URL: links.Event(event.ID)}
+	}
+	var out bytes.Buffer
+	if err := widget.Execute(&out, map[string]interface{}{"Title": feed.Title, "Events": events}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,157 @@
+package presenter
+
+import (
+	"backend/models"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFeedFormat(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   FeedFormat
+		wantOK bool
+	}{
+		{in: "", want: FeedRSS, wantOK: true},
+		{in: "rss", want: FeedRSS, wantOK: true},
+		{in: "Atom", want: FeedAtom, wantOK: true},
+		{in: "JSON", want: FeedJSON, wantOK: true},
+		{in: "html", want: FeedHTML, wantOK: true},
+		{in: "ical", wantOK: false},
+	}
+	for _, test := range tests {
+		if got, ok := ParseFeedFormat(test.in); got != test.want || ok != test.wantOK {
+			t.Errorf("ParseFeedFormat(%q) = %q, %v, want %q, %v", test.in, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func testFeed() (models.Feed, FeedLinks) {
+	starts := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	applyBy := starts.Add(-7 * 24 * time.Hour)
+	feed := models.Feed{
+		Title:       "Open calls from The Club",
+		Description: "Events looking for performers",
+		Updated:     starts.Add(-30 * 24 * time.Hour),
+		Events: []models.PublicEvent{{
+			ID:                uuid.MustParse("7b0f5e2c-6a8e-4b59-9d0c-2f3c1a4e5d6f"),
+			Name:              "Jazz & <Blues> night",
+			Description:       "Bring your own instrument.",
+			Tags:              []string{"jazz", "blues"},
+			Producer:          models.PublicProfile{Name: "The Club"},
+			Venue:             &models.PublicProfile{Name: "Back Room"},
+			ApplicationStatus: models.EventOpen,
+			Time:              starts,
+			ApplyByTime:       &applyBy,
+			UpdatedAt:         starts.Add(-10 * 24 * time.Hour),
+		}},
+	}
+	links := FeedLinks{
+		Self:  "https://ocall.example/public/producers/1/feed",
+		Event: func(id uuid.UUID) string { return "https://ocall.example/events/" + id.String() + "?from=feed&x=1" },
+	}
+	return feed, links
+}
+
+func TestWriteFeedRSS(t *testing.T) {
+	feed, links := testFeed()
+	data, err := WriteFeed(FeedRSS, feed, links)
+	if err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+	var out rss
+	if err := xml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("the RSS feed does not parse: %v", err)
+	}
+	event := feed.Events[0]
+	if out.Version != "2.0" || out.Channel.Title != feed.Title || out.Channel.Link != links.Self {
+		t.Errorf("channel = %+v", out.Channel)
+	}
+	if len(out.Channel.Items) != 1 {
+		t.Fatalf("RSS feed has %d items, want 1", len(out.Channel.Items))
+	}
+	item := out.Channel.Items[0]
+	if item.Title != event.Name || item.Link != links.Event(event.ID) || item.GUID.Value != event.ID.URN() || item.GUID.IsPermaLink {
+		t.Errorf("item = %+v", item)
+	}
+	if want := "Starts Sat, 1 Jun 2024 20:00 UTC at Back Room. Apply by Sat, 25 May 2024 20:00 UTC.\n\nBring your own instrument."; item.Description != want {
+		t.Errorf("item description = %q, want %q", item.Description, want)
+	}
+	if item.PubDate != "Wed, 22 May 2024 20:00:00 +0000" {
+		t.Errorf("item pubDate = %q", item.PubDate)
+	}
+}
+
+func TestWriteFeedAtom(t *testing.T) {
+	feed, links := testFeed()
+	data, err := WriteFeed(FeedAtom, feed, links)
+	if err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+	var out atom
+	if err := xml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("the Atom feed does not parse: %v", err)
+	}
+	if out.ID != links.Self || out.Link.Rel != "self" || out.Updated != "2024-05-02T20:00:00Z" {
+		t.Errorf("feed = %+v", out)
+	}
+	if len(out.Entries) != 1 {
+		t.Fatalf("Atom feed has %d entries, want 1", len(out.Entries))
+	}
+	entry := out.Entries[0]
+	if entry.Title != feed.Events[0].Name || entry.Author.Name != "The Club" || len(entry.Categories) != 2 {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+func TestWriteFeedJSON(t *testing.T) {
+	feed, links := testFeed()
+	data, err := WriteFeed(FeedJSON, feed, links)
+	if err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+	var out jsonFeedDocument
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("the JSON feed does not parse: %v", err)
+	}
+	if out.Version != "https://jsonfeed.org/version/1.1" || out.FeedURL != links.Self || len(out.Items) != 1 {
+		t.Fatalf("feed = %+v", out)
+	}
+	item := out.Items[0]
+	if item.URL != links.Event(feed.Events[0].ID) || item.Event.Venue == nil || item.Event.Pay != nil ||
+		!item.Event.StartsAt.Equal(feed.Events[0].Time) {
+		t.Errorf("item = %+v", item)
+	}
+}
+
+func TestWriteFeedHTML(t *testing.T) {
+	feed, links := testFeed()
+	data, err := WriteFeed(FeedHTML, feed, links)
+	if err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		"Jazz &amp; &lt;Blues&gt; night",
+		`href="https://ocall.example/events/7b0f5e2c-6a8e-4b59-9d0c-2f3c1a4e5d6f?from=feed&amp;x=1"`,
+		`<time datetime="2024-06-01T20:00:00Z">Sat, 1 Jun 2024 20:00 UTC</time> at Back Room`,
+		"Apply by",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("the HTML feed lacks %q:\n%s", want, page)
+		}
+	}
+
+	feed.Events = nil
+	data, err = WriteFeed(FeedHTML, feed, links)
+	if err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+	if !strings.Contains(string(data), "No open calls for performers right now.") {
+		t.Errorf("an empty HTML feed does not say so:\n%s", data)
+	}
+}
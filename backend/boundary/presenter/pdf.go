@@ -0,0 +1,109 @@
+package presenter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pdfLineWidth    = 90
+	pdfLinesPerPage = 54
+	pdfFontSize     = 10
+	pdfLeading      = 13
+)
+
+// WritePDF renders plain text as a minimal A4 PDF using the built-in Helvetica font.
+// Only WinAnsi characters survive; anything else is replaced with '?'.
+func WritePDF(w io.Writer, title string, text string) error {
+	lines := wrapLines(text, pdfLineWidth)
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	// objects 1-3 are the catalog, page tree and font; each page then takes a page object and a content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL 50 792 Td\n", pdfFontSize, pdfLeading)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDF(line))
+		}
+		content.WriteString("ET")
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			5+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+	offsets = append(offsets, buf.Len())
+	info := len(offsets)
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Title (%s) /Producer (ocall) >>\nendobj\n", info, escapePDF(title))
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", info+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", info+1, info, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func wrapLines(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 255:
+			b.WriteRune('?')
+		case r > 126:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -1,5 +1,17 @@
 package presenter
 
+import "backend/models"
+
 type IdResponse struct {
 	Id string `json:"id" format:"string"`
 }
+
+type ReviewsResponse struct {
+	Reputation models.Reputation `json:"reputation"`
+	Reviews    []models.Review   `json:"reviews"`
+}
+
+type ModerationRequest struct {
+	Status models.ModerationStatus `json:"status"`
+	Note   string                  `json:"note"`
+}
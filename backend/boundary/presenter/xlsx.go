@@ -0,0 +1,107 @@
+package presenter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// The fixed parts of a one-sheet workbook. Cells are written as inline strings, so no shared string table is needed.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter streams rows into a minimal single-sheet workbook. The sheet is written as rows arrive; the workbook
+// is only valid once Close has returned.
+type XLSXWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	name  string
+	row   int
+}
+
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.body); err != nil {
+			return nil, err
+		}
+	}
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(file)
+	if _, err := io.WriteString(sheet, xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &XLSXWriter{zip: archive, sheet: sheet, name: sheetName}, nil
+}
+
+// WriteRow appends a row of text cells.
+func (x *XLSXWriter) WriteRow(cells []string) error {
+	x.row++
+	if _, err := io.WriteString(x.sheet, `<row r="`+strconv.Itoa(x.row)+`">`); err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		if _, err := io.WriteString(x.sheet, `<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(x.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(x.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(x.sheet, `</row>`)
+	return err
+}
+
+// Close finishes the sheet, adds the workbook that names it and closes the archive. It does not close the
+// underlying writer.
+func (x *XLSXWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	file, err := x.zip.Create("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, xml.Header+
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`); err != nil {
+		return err
+	}
+	if err := xml.EscapeText(file, []byte(x.name)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, `" sheetId="1" r:id="rId1"/></sheets></workbook>`); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"backend/models"
+	"backend/usecase/imports"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, a *admin, args []string) error
+}
+
+var commands = map[string]command{
+	"tags list":          {usage: "", run: listTags},
+	"tags create":        {usage: "<name>", run: createTag},
+	"tags delete":        {usage: "[-dry-run] <id>", run: deleteTag},
+	"profiles list":      {usage: "", run: listProfiles},
+	"members list":       {usage: "<profile-id>", run: listMembers},
+	"members add":        {usage: "[-permission admin|restricted] <profile-id> <firebase-uid>", run: addMember},
+	"permissions grant":  {usage: "<user-id> admin|restricted", run: grantPermission},
+	"permissions revoke": {usage: "[-dry-run] <user-id>", run: revokePermission},
+	"events status":      {usage: "[-dry-run] <event-id> draft|open|closed|cancelled|unknown", run: forceEventStatus},
+	"events import":      {usage: "[-dry-run] <producer-id> <file.csv>", run: importEvents},
+	"profiles import":    {usage: "[-dry-run] <producer-id> <file.csv>", run: importProfiles},
+	"export":             {usage: "[-o file]", run: exportData},
+	"import":             {usage: "[-dry-run] <file>", run: importData},
+}
+
+// parse reads a command's flags and checks it got exactly n positional arguments.
+func parse(flags *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != n {
+		return nil, fmt.Errorf("expected %d arguments, got %d", n, flags.NArg())
+	}
+	return flags.Args(), nil
+}
+
+func table(w io.Writer, header string, rows func(w io.Writer)) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	rows(tw)
+	_ = tw.Flush()
+}
+
+func listTags(ctx context.Context, a *admin, args []string) error {
+	if _, err := parse(flag.NewFlagSet("tags list", flag.ExitOnError), args, 0); err != nil {
+		return err
+	}
+	tags, err := a.agenda.ListTags(ctx)
+	if err != nil {
+		return err
+	}
+	return a.out.print(tags, func(w io.Writer) {
+		table(w, "ID\tNAME\t", func(w io.Writer) {
+			for _, tag := range tags {
+				fmt.Fprintf(w, "%d\t%s\t\n", tag.ID, tag.Name)
+			}
+		})
+	})
+}
+
+func createTag(ctx context.Context, a *admin, args []string) error {
+	args, err := parse(flag.NewFlagSet("tags create", flag.ExitOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	tag := models.Tag{Name: args[0]}
+	if tag.ID, err = a.agenda.CreateTag(ctx, tag); err != nil {
+		return err
+	}
+	return a.out.print(tag, func(w io.Writer) { fmt.Fprintf(w, "created tag %d %s\n", tag.ID, tag.Name) })
+}
+
+func deleteTag(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("tags delete", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "show the tag that would be deleted")
+	args, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse tag id")
+	}
+	tag, err := a.agenda.GetTag(ctx, uint(id))
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return a.out.dryRun(fmt.Sprintf("delete tag %d %s", tag.ID, tag.Name), tag, nil)
+	}
+	if err := a.agenda.DeleteTag(ctx, tag); err != nil {
+		return err
+	}
+	return a.out.print(tag, func(w io.Writer) { fmt.Fprintf(w, "deleted tag %d %s\n", tag.ID, tag.Name) })
+}
+
+func listProfiles(ctx context.Context, a *admin, args []string) error {
+	if _, err := parse(flag.NewFlagSet("profiles list", flag.ExitOnError), args, 0); err != nil {
+		return err
+	}
+	profiles, err := a.users.ListProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	return a.out.print(profiles, func(w io.Writer) {
+		table(w, "ID\tTYPE\tNAME\t", func(w io.Writer) {
+			for _, profile := range profiles {
+				fmt.Fprintf(w, "%s\t%s\t%s\t\n", profile.ID, profile.ProfileType, profile.Name)
+			}
+		})
+	})
+}
+
+func listMembers(ctx context.Context, a *admin, args []string) error {
+	args, err := parse(flag.NewFlagSet("members list", flag.ExitOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	profileID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse profile id")
+	}
+	members, err := a.users.GetUsersByProfileId(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	return a.out.print(members, func(w io.Writer) {
+		table(w, "ID\tFIREBASE UID\tPERMISSION\t", func(w io.Writer) {
+			for _, member := range members {
+				fmt.Fprintf(w, "%s\t%s\t%s\t\n", member.ID, member.FirebaseId, member.Permissions)
+			}
+		})
+	})
+}
+
+func addMember(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("members add", flag.ExitOnError)
+	permission := flags.String("permission", string(models.Restricted), "admin or restricted")
+	args, err := parse(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	profileID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse profile id")
+	}
+	member, err := a.users.AddMember(ctx, profileID, args[1], models.Permission(*permission))
+	if err != nil {
+		return err
+	}
+	return a.out.print(member, func(w io.Writer) {
+		fmt.Fprintf(w, "added %s to %s as %s with user id %s\n", member.FirebaseId, profileID, member.Permissions, member.ID)
+	})
+}
+
+func grantPermission(ctx context.Context, a *admin, args []string) error {
+	args, err := parse(flag.NewFlagSet("permissions grant", flag.ExitOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse user id")
+	}
+	permission := models.Permission(args[1])
+	if permission != models.Admin && permission != models.Restricted {
+		return fmt.Errorf("cannot grant %q, only admin or restricted", args[1])
+	}
+	member, err := a.users.SetPermission(ctx, userID, permission)
+	if err != nil {
+		return err
+	}
+	return a.out.print(member, func(w io.Writer) { fmt.Fprintf(w, "%s is now %s\n", member.ID, member.Permissions) })
+}
+
+func revokePermission(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("permissions revoke", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "show the member whose permission would be revoked")
+	args, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse user id")
+	}
+	if *dryRun {
+		member, err := a.users.GetMember(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return a.out.dryRun(fmt.Sprintf("revoke %s from %s", member.Permissions, userID), member, nil)
+	}
+	member, err := a.users.SetPermission(ctx, userID, models.PermissionUnknown)
+	if err != nil {
+		return err
+	}
+	return a.out.print(member, func(w io.Writer) { fmt.Fprintf(w, "revoked the permission of %s\n", member.ID) })
+}
+
+func forceEventStatus(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("events status", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "show the transition without making it")
+	args, err := parse(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	eventID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse event id")
+	}
+	status := models.EventApplicationStatus(args[1])
+	switch status {
+	case models.EventDraft, models.EventOpen, models.EventClosed, models.EventCancelled, models.EventUnknown:
+	default:
+		return fmt.Errorf("invalid status %q. Allowed: draft, open, closed, cancelled, unknown", args[1])
+	}
+	if *dryRun {
+		event, err := a.agenda.GetEvent(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		action := fmt.Sprintf("move event %s from %s to %s", event.ID, event.Status, status)
+		return a.out.dryRun(action, event.Status, status)
+	}
+	event, err := a.agenda.ForceEventStatus(ctx, eventID, status)
+	if err != nil {
+		return err
+	}
+	return a.out.print(event, func(w io.Writer) { fmt.Fprintf(w, "event %s is now %s\n", event.ID, event.Status) })
+}
+
+func importEvents(ctx context.Context, a *admin, args []string) error {
+	return importCSV(ctx, a, "events import", args, a.imports.ImportEvents)
+}
+
+func importProfiles(ctx context.Context, a *admin, args []string) error {
+	return importCSV(ctx, a, "profiles import", args, a.imports.ImportProfiles)
+}
+
+// importCSV prints the report row by row. A rejected import still prints it, so every failing row is listed, and then
+// fails with the rejection.
+func importCSV(
+	ctx context.Context, a *admin, name string, args []string,
+	run func(ctx context.Context, producerID uuid.UUID, r io.Reader, dryRun bool) (imports.Report, error),
+) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "validate every row and report without creating anything")
+	args, err := parse(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	producerID, err := uuid.Parse(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to parse producer id")
+	}
+	file, err := os.Open(args[1])
+	if err != nil {
+		return errors.Wrap(err, "unable to open csv")
+	}
+	defer file.Close()
+	report, runErr := run(ctx, producerID, file, *dryRun)
+	if report.Rows == nil {
+		return runErr
+	}
+	if err := a.out.print(report, func(w io.Writer) {
+		table(w, "LINE\tRESULT\t", func(w io.Writer) {
+			for _, row := range report.Rows {
+				switch {
+				case len(row.Errors) > 0:
+					for _, e := range row.Errors {
+						fmt.Fprintf(w, "%d\t%s: %s\t\n", row.Line, e.Field, e.Message)
+					}
+				case row.ID != nil:
+					fmt.Fprintf(w, "%d\tcreated %s\t\n", row.Line, row.ID)
+				default:
+					fmt.Fprintf(w, "%d\tok\t\n", row.Line)
+				}
+			}
+		})
+		switch {
+		case report.Committed:
+			fmt.Fprintf(w, "imported %d rows\n", len(report.Rows))
+		case report.DryRun:
+			fmt.Fprintf(w, "dry run, %d of %d rows would fail\n", report.Failed, len(report.Rows))
+		}
+	}); err != nil {
+		return err
+	}
+	return runErr
+}
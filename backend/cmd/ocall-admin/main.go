@@ -0,0 +1,188 @@
+// Command ocall-admin runs operator tasks against the database through the same services as the API, so every change
+// is validated, audited and published exactly as if it had come in over HTTP.
+package main
+
+import (
+	"backend/data/geocoding"
+	"backend/data/migrations"
+	"backend/data/pubsub"
+	"backend/data/repository"
+	"backend/models"
+	"backend/usecase/agenda"
+	"backend/usecase/audit"
+	"backend/usecase/contracts"
+	"backend/usecase/imports"
+	"backend/usecase/messages"
+	"backend/usecase/stream"
+	"backend/usecase/users"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+)
+
+const usage = `usage: ocall-admin [-json] <command> [flags] [args]
+
+commands:
+`
+
+type admin struct {
+	users   users.Service
+	agenda  agenda.Service
+	imports imports.Service
+	out     output
+
+	transactor imports.Transactor
+	// snapshots is agenda holding back its stream events in held until a snapshot import has committed.
+	snapshots agenda.Service
+	held      *heldPublisher
+}
+
+// output prints a result either as indented JSON for scripts or as text for people.
+type output struct {
+	json bool
+	w    io.Writer
+}
+
+func (o output) print(v interface{}, text func(w io.Writer)) error {
+	if o.json {
+		encoder := json.NewEncoder(o.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	text(o.w)
+	return nil
+}
+
+// DryRun is what a destructive command prints instead of acting when -dry-run is set.
+type DryRun struct {
+	DryRun bool        `json:"dry_run"`
+	Action string      `json:"action"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+func (o output) dryRun(action string, before interface{}, after interface{}) error {
+	return o.print(DryRun{DryRun: true, Action: action, Before: before, After: after}, func(w io.Writer) {
+		fmt.Fprintf(w, "dry run, would %s\n", action)
+	})
+}
+
+func main() {
+	jsonOutput := flag.Bool("json", false, "print results as JSON")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(flag.CommandLine.Output(), "  %s %s\n", name, commands[name].usage)
+		}
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	name, args, ok := lookup(flag.Args())
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+	a, err := connect(*jsonOutput)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	operator := "unknown"
+	if current, err := user.Current(); err == nil {
+		operator = current.Username
+	}
+	ctx := models.ContextWithActor(context.Background(), models.Actor{Type: models.ActorSystem, ID: "ocall-admin:" + operator})
+	if err := commands[name].run(ctx, a, args); err != nil {
+		fmt.Fprintln(os.Stderr, errors.Wrap(err, name).Error())
+		os.Exit(1)
+	}
+}
+
+// lookup finds the longest command name that prefixes args, so "tags delete 3" runs "tags delete" with ["3"].
+func lookup(args []string) (string, []string, bool) {
+	for n := 2; n >= 1; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		if _, ok := commands[name]; ok {
+			return name, args[n:], true
+		}
+	}
+	return "", nil, false
+}
+
+// connect wires the services the same way the API does, from the same OCALL_ environment, and refuses a database whose
+// schema has drifted from this binary.
+func connect(jsonOutput bool) (*admin, error) {
+	viper.AutomaticEnv()
+	_ = viper.BindEnv("dbUri", "OCALL_DB_URI")
+	_ = viper.BindEnv("pubsub", "OCALL_PUBSUB")
+	_ = viper.BindEnv("geocoderUrl", "OCALL_GEOCODER_URL")
+	uri := viper.GetString("dbUri")
+
+	orm, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: uri, PreferSimpleProtocol: true}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to connect to db")
+	}
+	migrator, err := migrations.NewMigrator(orm)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "refusing to run, migrate the database first")
+	}
+
+	uRepo := repository.NewUserRepo(orm)
+	auRepo := repository.NewAuditRepo(orm)
+	aRepo := repository.NewAgendaRepo(orm)
+	cRepo := repository.NewContractRepo(orm)
+	mRepo := repository.NewMessageRepo(orm)
+	var broker stream.Broker
+	if viper.GetString("pubsub") == "postgres" {
+		if broker, err = pubsub.NewPostgresBroker(orm, uri); err != nil {
+			return nil, errors.Wrap(err, "unable to start postgres pubsub")
+		}
+	} else {
+		broker = pubsub.NewMemoryBroker()
+	}
+	auService := audit.NewService(&auRepo)
+	sService := stream.NewService(broker, &uRepo)
+	cService := contracts.NewService(&cRepo, &aRepo, &uRepo)
+	mService := messages.NewService(&mRepo, &aRepo, &uRepo, &sService, &auService)
+	transactor := repository.NewTransactor(orm)
+	var geocoder imports.Geocoder
+	if geocoderURL := viper.GetString("geocoderUrl"); geocoderURL != "" {
+		geocoder = geocoding.NewNominatim(geocoderURL, "ocall-admin")
+	}
+	held := &heldPublisher{publisher: &sService}
+	return &admin{
+		users:      users.NewService(&uRepo, &auService),
+		agenda:     agenda.NewService(&aRepo, &sService, &auService, &mService, &cService),
+		imports:    imports.NewService(&aRepo, &uRepo, &transactor, geocoder, &auService),
+		out:        output{json: jsonOutput, w: os.Stdout},
+		transactor: &transactor,
+		snapshots:  agenda.NewService(&aRepo, held, &auService, &mService, &cService),
+		held:       held,
+	}, nil
+}
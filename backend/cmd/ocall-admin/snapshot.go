@@ -0,0 +1,272 @@
+package main
+
+import (
+	"backend/models"
+	"backend/usecase/agenda"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// Snapshot is the document export writes and import reads. The references the API hides are spelled out, since
+// import creates every row anew and has to point them at the new IDs.
+type Snapshot struct {
+	ExportedAt   time.Time        `json:"exported_at"`
+	Tags         []models.Tag     `json:"tags"`
+	Profiles     []ProfileRow     `json:"profiles"`
+	Events       []EventRow       `json:"events"`
+	Applications []ApplicationRow `json:"applications"`
+}
+
+type ProfileRow struct {
+	models.Profile
+	Members []models.UserID `json:"members"`
+}
+
+type EventRow struct {
+	models.Event
+	ProducerID uuid.UUID  `json:"producer_id"`
+	VenueID    *uuid.UUID `json:"venue_id,omitempty"`
+}
+
+type ApplicationRow struct {
+	models.Application
+	PerformerID uuid.UUID `json:"performer_id"`
+}
+
+// ImportReport counts what an import created, or created and rolled back on a dry run. Unresolved lists references to rows
+// outside the snapshot, which are kept as they are.
+type ImportReport struct {
+	DryRun       bool     `json:"dry_run"`
+	Tags         int      `json:"tags"`
+	SkippedTags  []string `json:"skipped_tags,omitempty"`
+	Profiles     int      `json:"profiles"`
+	Members      int      `json:"members"`
+	Events       int      `json:"events"`
+	Applications int      `json:"applications"`
+	Unresolved   []string `json:"unresolved,omitempty"`
+}
+
+func exportData(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	path := flags.String("o", "", "write to this file instead of stdout")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	snapshot := Snapshot{ExportedAt: time.Now().UTC()}
+	var err error
+	if snapshot.Tags, err = a.agenda.ListTags(ctx); err != nil {
+		return err
+	}
+	profiles, err := a.users.ListProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		members, err := a.users.GetUsersByProfileId(ctx, profile.ID)
+		if err != nil {
+			return err
+		}
+		snapshot.Profiles = append(snapshot.Profiles, ProfileRow{Profile: profile, Members: members})
+	}
+	events, err := a.agenda.ListEvents(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		snapshot.Events = append(snapshot.Events, EventRow{Event: event, ProducerID: event.ProducerID, VenueID: event.VenueID})
+		applications, err := a.agenda.GetApplicationsByEvent(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+		for _, application := range applications {
+			snapshot.Applications = append(snapshot.Applications,
+				ApplicationRow{Application: application, PerformerID: application.PerformerID},
+			)
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			return errors.Wrap(err, "unable to create export file")
+		}
+		defer file.Close()
+		w = file
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// errDryRun rolls back the transaction of an import that was only a dry run.
+var errDryRun = errors.New("dry run rolled back")
+
+// heldPublisher keeps the stream events published during an import until it knows whether the import committed, so
+// subscribers never hear of rows that a dry run or a failure rolled back.
+type heldPublisher struct {
+	publisher agenda.Publisher
+	events    []models.StreamEvent
+}
+
+func (p *heldPublisher) Publish(_ context.Context, event models.StreamEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+// flush publishes the held events, or drops them if the import did not commit.
+func (p *heldPublisher) flush(ctx context.Context, committed bool) {
+	events := p.events
+	p.events = nil
+	if !committed {
+		return
+	}
+	for _, event := range events {
+		if err := p.publisher.Publish(ctx, event); err != nil {
+			log.Printf("unable to publish %s stream event: %v", event.Type, err)
+		}
+	}
+}
+
+// importData recreates a snapshot through the services, so every row is validated and audited, in one transaction
+// that a dry run rolls back after making every write. Rows get new IDs; references are rewritten to them, and tags
+// whose name already exists are reused.
+func importData(ctx context.Context, a *admin, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "import and report, then roll everything back")
+	args, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	body, err := os.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrap(err, "unable to read snapshot")
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return errors.Wrap(err, "unable to parse snapshot")
+	}
+
+	var report ImportReport
+	err = a.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		report = ImportReport{DryRun: *dryRun}
+		if err := importSnapshot(ctx, a, snapshot, &report); err != nil {
+			return err
+		}
+		if *dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	a.held.flush(ctx, err == nil)
+	if err != nil && err != errDryRun {
+		return err
+	}
+
+	return a.out.print(report, func(w io.Writer) {
+		verb := "created"
+		if report.DryRun {
+			verb = "dry run, rolled back"
+		}
+		fmt.Fprintf(w, "%s %d tags, %d profiles, %d members, %d events and %d applications\n",
+			verb, report.Tags, report.Profiles, report.Members, report.Events, report.Applications)
+		for _, name := range report.SkippedTags {
+			fmt.Fprintf(w, "tag %s already exists\n", name)
+		}
+		for _, reference := range report.Unresolved {
+			fmt.Fprintf(w, "%s is not in the snapshot and was kept as is\n", reference)
+		}
+	})
+}
+
+// importSnapshot makes the writes of importData with the transaction ctx carries.
+func importSnapshot(ctx context.Context, a *admin, snapshot Snapshot, report *ImportReport) error {
+	existing, err := a.snapshots.ListTags(ctx)
+	if err != nil {
+		return err
+	}
+	tags := map[string]models.Tag{}
+	for _, tag := range existing {
+		tags[tag.Name] = tag
+	}
+	for _, tag := range snapshot.Tags {
+		if _, ok := tags[tag.Name]; ok {
+			report.SkippedTags = append(report.SkippedTags, tag.Name)
+			continue
+		}
+		created := models.Tag{Name: tag.Name}
+		if created.ID, err = a.snapshots.CreateTag(ctx, created); err != nil {
+			return errors.Wrapf(err, "tag %s", tag.Name)
+		}
+		tags[tag.Name] = created
+		report.Tags++
+	}
+
+	// ids maps every snapshot ID to the ID its row got here.
+	ids := map[uuid.UUID]uuid.UUID{}
+	resolve := func(kind string, id uuid.UUID) uuid.UUID {
+		if newID, ok := ids[id]; ok {
+			return newID
+		}
+		report.Unresolved = append(report.Unresolved, fmt.Sprintf("%s %s", kind, id))
+		return id
+	}
+	for _, row := range snapshot.Profiles {
+		profile := row.Profile
+		oldID := profile.ID
+		profile.ID, profile.Version, profile.UserIDs, profile.Reputation = uuid.Nil, 0, nil, nil
+		if ids[oldID], err = a.users.CreateProfile(ctx, profile); err != nil {
+			return errors.Wrapf(err, "profile %s", oldID)
+		}
+		report.Profiles++
+		for _, member := range row.Members {
+			if _, err := a.users.AddMember(ctx, ids[oldID], member.FirebaseId, member.Permissions); err != nil {
+				return errors.Wrapf(err, "member %s of profile %s", member.FirebaseId, oldID)
+			}
+			report.Members++
+		}
+	}
+	for _, row := range snapshot.Events {
+		event := row.Event
+		oldID := event.ID
+		event.ID, event.Version, event.Tags, event.Applications = uuid.Nil, 0, nil, nil
+		event.Producer, event.Venue = models.Profile{}, nil
+		event.ProducerID = resolve("producer", row.ProducerID)
+		if row.VenueID != nil {
+			venueID := resolve("venue", *row.VenueID)
+			event.VenueID = &venueID
+		}
+		// Tags are matched by name, as their IDs are those of the exporting database.
+		for _, tag := range row.Event.Tags {
+			if existing, ok := tags[tag.Name]; ok {
+				event.Tags = append(event.Tags, existing)
+			} else {
+				report.Unresolved = append(report.Unresolved, fmt.Sprintf("tag %s", tag.Name))
+			}
+		}
+		if ids[oldID], err = a.snapshots.CreateEvent(ctx, event); err != nil {
+			return errors.Wrapf(err, "event %s", oldID)
+		}
+		report.Events++
+	}
+	for _, row := range snapshot.Applications {
+		application := row.Application
+		oldID := application.ID
+		application.ID, application.Version, application.Performer = uuid.Nil, 0, models.Profile{}
+		application.PerformerID = resolve("performer", row.PerformerID)
+		application.EventRef = resolve("event", application.EventRef)
+		if _, err := a.snapshots.CreateApplication(ctx, application); err != nil {
+			return errors.Wrapf(err, "application %s", oldID)
+		}
+		report.Applications++
+	}
+	return nil
+}
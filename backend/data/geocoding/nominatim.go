@@ -0,0 +1,90 @@
+package geocoding
+
+import (
+	"backend/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nferruzzi/gormGIS"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MinRequestInterval is how long Nominatim waits between two requests, the most the public instance's usage policy
+// allows.
+const MinRequestInterval = time.Second
+
+// Nominatim geocodes free-text addresses against a Nominatim (OpenStreetMap) search endpoint. Requests are spaced
+// MinRequestInterval apart across every caller, so deployments that import a lot should point it at their own.
+type Nominatim struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func NewNominatim(baseURL string, userAgent string) *Nominatim {
+	return &Nominatim{baseURL: baseURL, userAgent: userAgent, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// wait blocks until the caller may send the next request, or ctx is done.
+func (n *Nominatim) wait(ctx context.Context) error {
+	n.mu.Lock()
+	now := time.Now()
+	at := n.next
+	if at.Before(now) {
+		at = now
+	}
+	n.next = at.Add(MinRequestInterval)
+	n.mu.Unlock()
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (n *Nominatim) Geocode(ctx context.Context, address string) (gormGIS.GeoPoint, error) {
+	if err := n.wait(ctx); err != nil {
+		return gormGIS.GeoPoint{}, errors.Wrap(err, "geocoding request cancelled")
+	}
+	query := url.Values{"q": {address}, "format": {"json"}, "limit": {"1"}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return gormGIS.GeoPoint{}, errors.Wrap(err, "unable to build geocoding request")
+	}
+	request.Header.Set("User-Agent", n.userAgent)
+	response, err := n.client.Do(request)
+	if err != nil {
+		return gormGIS.GeoPoint{}, errors.Wrap(err, "geocoding request failed")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return gormGIS.GeoPoint{}, fmt.Errorf("geocoding answered %s", response.Status)
+	}
+	var places []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&places); err != nil {
+		return gormGIS.GeoPoint{}, errors.Wrap(err, "unable to decode geocoding response")
+	}
+	if len(places) == 0 {
+		return gormGIS.GeoPoint{}, domain.NotFound("address_not_found", fmt.Sprintf("no place matches %q", address))
+	}
+	lat, latErr := strconv.ParseFloat(places[0].Lat, 64)
+	lng, lngErr := strconv.ParseFloat(places[0].Lon, 64)
+	if latErr != nil || lngErr != nil {
+		return gormGIS.GeoPoint{}, fmt.Errorf("geocoding returned an invalid position %s,%s", places[0].Lat, places[0].Lon)
+	}
+	return gormGIS.GeoPoint{Lat: lat, Lng: lng}, nil
+}
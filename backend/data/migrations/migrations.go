@@ -0,0 +1,423 @@
+package migrations
+
+import (
+	"backend/models"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// NoTransaction marks a script that postgres refuses to run in a transaction block, such as ALTER TYPE ... ADD VALUE
+// before postgres 12. Such scripts run one statement at a time and must not contain DO blocks.
+const NoTransaction = "-- migrate:no-transaction"
+
+// lockID keys the advisory lock that keeps two migrators, say two replicas booting, from racing.
+const lockID = 74501130
+
+var ErrDrift = errors.New("schema drift")
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// after holds the data conversions that SQL cannot express, keyed by the migration they follow.
+var after = map[int64]func(tx *gorm.DB) error{
+	1: models.MigrateLegacyPayStructure,
+}
+
+// tables are checked column by column against the models at startup.
+var tables = []interface{}{
+	&models.Profile{}, &models.UserID{}, &models.Tag{}, &models.Event{}, &models.Application{},
+	&models.ContractTemplate{}, &models.Contract{}, &models.EventRevenue{}, &models.Payment{},
+	&models.Review{}, &models.AuditEntry{}, &models.IdempotencyRecord{}, &models.Rubric{}, &models.ApplicationScore{},
+	&models.ApplicationForm{}, &models.EnsembleMember{}, &models.Organization{}, &models.OrganizationAdmin{},
+	&models.Message{},
+}
+
+type Script struct {
+	SQL         string
+	Transaction bool
+}
+
+// Migration is one numbered step, read from sql/<version>_<name>.up.sql and its .down.sql counterpart.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Script
+	Down    Script
+}
+
+// Checksum identifies the up script, so editing a migration that already ran counts as drift.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up.SQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// Applied is a row of schema_migrations.
+type Applied struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (Applied) TableName() string { return "schema_migrations" }
+
+// Status is a known migration and when it ran, if it did. Modified means the file changed since.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+	Modified  bool       `json:"modified"`
+}
+
+// Load reads the embedded migrations in version order.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list migrations")
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.(up|down).sql", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		body, err := files.ReadFile("sql/" + entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read migration %s", entry.Name())
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, match[2])
+		}
+		script := Script{SQL: string(body), Transaction: !strings.HasPrefix(string(body), NoTransaction)}
+		if match[3] == "up" {
+			migration.Up = script
+		} else {
+			migration.Down = script
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up.SQL == "" || migration.Down.SQL == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return Migrator{}, err
+	}
+	return Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order and returns the ones it applied. It refuses to touch a database whose
+// history does not match the binary.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		if problems := m.history(applied); len(problems) > 0 {
+			return drift(problems)
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			migration := migration
+			err := run(conn, migration.Up, func(tx *gorm.DB) error {
+				if step, ok := after[migration.Version]; ok {
+					if err := step(tx); err != nil {
+						return err
+					}
+				}
+				return tx.Create(&Applied{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum(),
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "migration %d_%s failed", migration.Version, migration.Name)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns the ones it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		if problems := m.history(applied); len(problems) > 0 {
+			return drift(problems)
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := run(conn, migration.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&Applied{}, migration.Version).Error
+			})
+			if err != nil {
+				return errors.Wrapf(err, "reverting migration %d_%s failed", migration.Version, migration.Name)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists, by version, the known migrations and any the database applied that this binary does not know.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	db := m.db.WithContext(ctx)
+	applied := map[int64]Applied{}
+	if db.Migrator().HasTable(&Applied{}) {
+		var err error
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = row.Checksum != migration.Checksum()
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check returns ErrDrift when the database is not the schema this binary expects: migrations pending, unknown or
+// edited since they ran, enum types whose values differ from models.EnumTypes, or tables missing mapped columns.
+func (m *Migrator) Check(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&Applied{}) {
+		return drift([]string{"schema_migrations does not exist, run migrate up"})
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	problems := m.history(applied)
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			problems = append(problems, fmt.Sprintf("migration %d_%s is pending", migration.Version, migration.Name))
+		}
+	}
+	enumProblems, err := checkEnums(db)
+	if err != nil {
+		return err
+	}
+	columnProblems, err := checkColumns(db)
+	if err != nil {
+		return err
+	}
+	problems = append(append(problems, enumProblems...), columnProblems...)
+	if len(problems) > 0 {
+		return drift(problems)
+	}
+	return nil
+}
+
+// history reports applied migrations that this binary does not know or whose file has changed since.
+func (m *Migrator) history(applied map[int64]Applied) []string {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	var problems []string
+	for version, row := range applied {
+		migration, ok := known[version]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("migration %d_%s is applied but unknown to this binary", version, row.Name))
+		case row.Checksum != migration.Checksum():
+			problems = append(problems, fmt.Sprintf("migration %d_%s changed after it was applied", version, row.Name))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// locked runs fn on a single connection holding the migration lock, with schema_migrations in place.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockID).Error; err != nil {
+			return errors.Wrap(err, "unable to take the migration lock")
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", lockID)
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`).Error; err != nil {
+			return errors.Wrap(err, "unable to create schema_migrations")
+		}
+		return fn(conn)
+	})
+}
+
+// run executes script and then record, together in one transaction unless the script opts out.
+func run(conn *gorm.DB, script Script, record func(tx *gorm.DB) error) error {
+	if script.Transaction {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(script.SQL).Error; err != nil {
+				return err
+			}
+			return record(tx)
+		})
+	}
+	for _, statement := range statements(script.SQL) {
+		if err := conn.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return conn.Transaction(record)
+}
+
+// statements splits a no-transaction script at the semicolons that end a line, dropping comment lines.
+func statements(sql string) []string {
+	var out []string
+	for _, part := range strings.SplitAfter(sql, ";\n") {
+		var lines []string
+		for _, line := range strings.Split(part, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				lines = append(lines, line)
+			}
+		}
+		if statement := strings.TrimSpace(strings.Join(lines, "\n")); statement != "" {
+			out = append(out, statement)
+		}
+	}
+	return out
+}
+
+func appliedMigrations(db *gorm.DB) (map[int64]Applied, error) {
+	var rows []Applied
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "unable to read schema_migrations")
+	}
+	applied := make(map[int64]Applied, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func checkEnums(db *gorm.DB) ([]string, error) {
+	var labels []struct {
+		TypeName string
+		Label    string
+	}
+	if err := db.Raw(`SELECT t.typname AS type_name, e.enumlabel AS label
+FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid`).Scan(&labels).Error; err != nil {
+		return nil, errors.Wrap(err, "unable to read pg_enum")
+	}
+	actual := map[string]map[string]bool{}
+	for _, label := range labels {
+		if actual[label.TypeName] == nil {
+			actual[label.TypeName] = map[string]bool{}
+		}
+		actual[label.TypeName][label.Label] = true
+	}
+	var problems []string
+	for name, values := range models.EnumTypes {
+		have, ok := actual[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("enum %s does not exist", name))
+			continue
+		}
+		for _, value := range values {
+			if !have[value] {
+				problems = append(problems, fmt.Sprintf("enum %s lacks %q", name, value))
+			}
+			delete(have, value)
+		}
+		for value := range have {
+			problems = append(problems, fmt.Sprintf("enum %s has %q, which the code does not know", name, value))
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+func checkColumns(db *gorm.DB) ([]string, error) {
+	var problems []string
+	for _, table := range tables {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(table); err != nil {
+			return nil, errors.Wrap(err, "unable to parse model")
+		}
+		if !db.Migrator().HasTable(table) {
+			problems = append(problems, fmt.Sprintf("table %s does not exist", stmt.Schema.Table))
+			continue
+		}
+		columns, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the columns of %s", stmt.Schema.Table)
+		}
+		have := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			have[column.Name()] = true
+		}
+		for _, name := range stmt.Schema.DBNames {
+			if !have[name] {
+				problems = append(problems, fmt.Sprintf("column %s.%s does not exist", stmt.Schema.Table, name))
+			}
+		}
+	}
+	return problems, nil
+}
+
+func drift(problems []string) error {
+	return errors.Wrap(ErrDrift, strings.Join(problems, "; "))
+}
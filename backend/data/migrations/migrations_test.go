@@ -0,0 +1,145 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	checksums := map[string]int64{}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s is at position %d, want versions to run 1, 2, 3...", migration.Version, migration.Name, i)
+		}
+		if migration.Down.SQL == "" {
+			t.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		if version, ok := checksums[migration.Checksum()]; ok {
+			t.Errorf("migrations %d and %d have the same checksum", version, migration.Version)
+		}
+		checksums[migration.Checksum()] = migration.Version
+		if migration.Up.Transaction == strings.HasPrefix(migration.Up.SQL, NoTransaction) {
+			t.Errorf("migration %d_%s does not follow its %q header", migration.Version, migration.Name, NoTransaction)
+		}
+		if !migration.Up.Transaction && strings.Contains(migration.Up.SQL, "DO $$") {
+			t.Errorf("migration %d_%s runs without a transaction but has a DO block", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{name: "empty", sql: "", want: nil},
+		{
+			name: "one statement per line",
+			sql:  NoTransaction + "\nALTER TYPE a ADD VALUE 'x';\nALTER TYPE a ADD VALUE 'y';\n",
+			want: []string{"ALTER TYPE a ADD VALUE 'x';", "ALTER TYPE a ADD VALUE 'y';"},
+		},
+		{
+			name: "statement over several lines",
+			sql:  "-- comment\nALTER TABLE a\n    ADD COLUMN b text;\n",
+			want: []string{"ALTER TABLE a\n    ADD COLUMN b text;"},
+		},
+		{
+			name: "semicolon inside a line",
+			sql:  "UPDATE a SET b = ';' WHERE c;\n",
+			want: []string{"UPDATE a SET b = ';' WHERE c;"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := statements(test.sql); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("statements() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	first := Migration{Version: 1, Name: "first", Up: Script{SQL: "SELECT 1;"}}
+	second := Migration{Version: 2, Name: "second", Up: Script{SQL: "SELECT 2;"}}
+	m := Migrator{migrations: []Migration{first, second}}
+	tests := []struct {
+		name    string
+		applied map[int64]Applied
+		want    []string
+	}{
+		{name: "nothing applied", applied: map[int64]Applied{}},
+		{name: "partly applied", applied: map[int64]Applied{1: {Version: 1, Name: "first", Checksum: first.Checksum()}}},
+		{
+			name:    "edited after it ran",
+			applied: map[int64]Applied{1: {Version: 1, Name: "first", Checksum: "old"}},
+			want:    []string{"migration 1_first changed after it was applied"},
+		},
+		{
+			name:    "unknown to the binary",
+			applied: map[int64]Applied{3: {Version: 3, Name: "third", Checksum: "any"}},
+			want:    []string{"migration 3_third is applied but unknown to this binary"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := m.history(test.applied); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("history() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestUpDown migrates the database named by OCALL_TEST_DATABASE all the way down and back up. It drops every table, so
+// the database must be a scratch one.
+func TestUpDown(t *testing.T) {
+	uri := os.Getenv("OCALL_TEST_DATABASE")
+	if uri == "" {
+		t.Skip("OCALL_TEST_DATABASE is not set")
+	}
+	ctx := context.Background()
+	orm, err := gorm.Open(postgres.New(postgres.Config{DSN: uri, PreferSimpleProtocol: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("unable to connect to the test database: %v", err)
+	}
+	migrator, err := NewMigrator(orm)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatalf("Check() after Up() error = %v", err)
+	}
+	reverted, err := migrator.Down(ctx, len(migrator.migrations))
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != len(migrator.migrations) {
+		t.Errorf("Down() reverted %d migrations, want %d", len(reverted), len(migrator.migrations))
+	}
+	if err := migrator.Check(ctx); !errors.Is(err, ErrDrift) {
+		t.Errorf("Check() after Down() error = %v, want %v", err, ErrDrift)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() after Down() error = %v", err)
+	}
+	if len(applied) != len(migrator.migrations) {
+		t.Errorf("Up() applied %d migrations, want %d", len(applied), len(migrator.migrations))
+	}
+	if err := migrator.Check(ctx); err != nil {
+		t.Errorf("Check() after a round trip error = %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+DROP TABLE IF EXISTS idempotency_records;
+DROP TABLE IF EXISTS audit_entries;
+DROP FUNCTION IF EXISTS audit_entries_immutable();
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS event_revenues;
+DROP TABLE IF EXISTS contracts;
+DROP TABLE IF EXISTS contract_templates;
+DROP TABLE IF EXISTS applications;
+DROP TABLE IF EXISTS event_tags;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS user_ids;
+DROP TABLE IF EXISTS profiles;
+
+DROP TYPE IF EXISTS actor_type;
+DROP TYPE IF EXISTS moderation_status;
+DROP TYPE IF EXISTS review_direction;
+DROP TYPE IF EXISTS payment_method;
+DROP TYPE IF EXISTS contract_status;
+DROP TYPE IF EXISTS pay_basis;
+DROP TYPE IF EXISTS pay_type;
+DROP TYPE IF EXISTS event_application_status;
+DROP TYPE IF EXISTS application_status;
+DROP TYPE IF EXISTS profile_type;
+DROP TYPE IF EXISTS permission;
@@ -0,0 +1,255 @@
+-- The schema as the AutoMigrate release left it, then everything added before numbered migrations existed. Every
+-- statement is guarded, so a database that release created keeps its tables and data and is brought up to date: the
+-- tables it lacks are created, the columns added since are added to the tables it has, and MigrateLegacyPayStructure
+-- then moves the free-text pay_structure into the pay_* columns.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+DO $$ BEGIN CREATE TYPE permission AS ENUM ('admin', 'restricted', 'unknown'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE profile_type AS ENUM ('producer', 'performer', 'venue'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE application_status AS ENUM ('pending', 'offered', 'unknown', 'rejected', 'accepted'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE event_application_status AS ENUM ('open', 'unknown', 'closed', 'cancelled'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE pay_type AS ENUM ('flat_fee', 'door_split', 'ticket_tiers', 'unpaid', 'unspecified'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE pay_basis AS ENUM ('per_performer', 'per_act'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE contract_status AS ENUM ('pending', 'signed', 'void'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE payment_method AS ENUM ('cash', 'bank_transfer', 'check', 'paypal', 'venmo', 'other'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE review_direction AS ENUM ('producer_to_performer', 'performer_to_producer'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE moderation_status AS ENUM ('visible', 'flagged', 'removed'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE actor_type AS ENUM ('firebase', 'super_user', 'system'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS profiles (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text NOT NULL,
+	profile_type profile_type NOT NULL,
+	location text,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_profiles_deleted_at ON profiles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_ids (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	firebase_id text,
+	permissions permission DEFAULT 'unknown',
+	profile_id text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_profiles_user_ids FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_ids_deleted_at ON user_ids (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id bigserial,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text UNIQUE,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags (deleted_at);
+
+CREATE TABLE IF NOT EXISTS events (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	description text,
+	producer_id text,
+	venue_id text,
+	google_form text,
+	location text,
+	status event_application_status DEFAULT 'unknown',
+	time timestamptz,
+	apply_by_time timestamptz,
+	pay_structure text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_events_producer FOREIGN KEY (producer_id) REFERENCES profiles (id),
+	CONSTRAINT fk_events_venue FOREIGN KEY (venue_id) REFERENCES profiles (id)
+);
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events (deleted_at);
+
+CREATE TABLE IF NOT EXISTS event_tags (
+	event_id text,
+	tag_id bigserial,
+	PRIMARY KEY (event_id, tag_id),
+	CONSTRAINT fk_event_tags_event FOREIGN KEY (event_id) REFERENCES events (id),
+	CONSTRAINT fk_event_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE TABLE IF NOT EXISTS applications (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text,
+	status application_status DEFAULT 'unknown',
+	performer_id text,
+	event_ref text,
+	google_response_id text,
+	PRIMARY KEY (id),
+	CONSTRAINT fk_applications_performer FOREIGN KEY (performer_id) REFERENCES profiles (id),
+	CONSTRAINT fk_events_applications FOREIGN KEY (event_ref) REFERENCES events (id)
+);
+CREATE INDEX IF NOT EXISTS idx_applications_deleted_at ON applications (deleted_at);
+
+-- Since the AutoMigrate release. Its uuid-tagged references may have come out as uuid columns, which cannot be
+-- compared with the text ids they point at, so they are made text like the ids.
+ALTER TABLE user_ids ALTER COLUMN profile_id TYPE text;
+ALTER TABLE events ALTER COLUMN producer_id TYPE text, ALTER COLUMN venue_id TYPE text;
+ALTER TABLE applications ALTER COLUMN performer_id TYPE text, ALTER COLUMN event_ref TYPE text;
+
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE events
+	ADD COLUMN IF NOT EXISTS pay_type pay_type DEFAULT 'unspecified',
+	ADD COLUMN IF NOT EXISTS pay_basis pay_basis DEFAULT 'per_performer',
+	ADD COLUMN IF NOT EXISTS pay_currency varchar(3),
+	ADD COLUMN IF NOT EXISTS pay_flat_fee bigint,
+	ADD COLUMN IF NOT EXISTS pay_door_split_percent decimal,
+	ADD COLUMN IF NOT EXISTS pay_tiers jsonb,
+	ADD COLUMN IF NOT EXISTS pay_notes text,
+	ADD COLUMN IF NOT EXISTS pay_min_amount bigint,
+	ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_events_min_amount ON events (pay_min_amount);
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS contract_templates (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	producer_id text,
+	name text,
+	body text,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_contract_templates_producer_id ON contract_templates (producer_id);
+CREATE INDEX IF NOT EXISTS idx_contract_templates_deleted_at ON contract_templates (deleted_at);
+
+CREATE TABLE IF NOT EXISTS contracts (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	application_id text,
+	event_ref text,
+	producer_id text,
+	performer_id text,
+	template_id text,
+	status contract_status DEFAULT 'pending',
+	body text,
+	body_hash text,
+	producer_signer_uid text,
+	producer_signed_at timestamptz,
+	performer_signer_uid text,
+	performer_signed_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_contracts_deleted_at ON contracts (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_contracts_application_id ON contracts (application_id);
+
+CREATE TABLE IF NOT EXISTS event_revenues (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	event_ref text,
+	door_revenue bigint,
+	tickets_sold bigint,
+	currency varchar(3),
+	notes text,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_revenues_event_ref ON event_revenues (event_ref);
+CREATE INDEX IF NOT EXISTS idx_event_revenues_deleted_at ON event_revenues (deleted_at);
+
+CREATE TABLE IF NOT EXISTS payments (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	application_id text,
+	event_ref text,
+	performer_id text,
+	amount bigint,
+	currency varchar(3),
+	method payment_method,
+	reference text,
+	paid_at timestamptz,
+	recorded_by text,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_payments_performer_id ON payments (performer_id);
+CREATE INDEX IF NOT EXISTS idx_payments_event_ref ON payments (event_ref);
+CREATE INDEX IF NOT EXISTS idx_payments_application_id ON payments (application_id);
+CREATE INDEX IF NOT EXISTS idx_payments_deleted_at ON payments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS reviews (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	event_ref text,
+	application_id text,
+	direction review_direction,
+	reviewer_profile_id text,
+	subject_profile_id text,
+	rating bigint,
+	body text,
+	submitted_by text,
+	moderation moderation_status DEFAULT 'visible',
+	moderation_note text,
+	published_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reviews_published_at ON reviews (published_at);
+CREATE INDEX IF NOT EXISTS idx_reviews_subject_profile_id ON reviews (subject_profile_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_application_direction ON reviews (application_id, direction);
+CREATE INDEX IF NOT EXISTS idx_reviews_event_ref ON reviews (event_ref);
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews (deleted_at);
+
+CREATE TABLE IF NOT EXISTS audit_entries (
+	id uuid,
+	created_at timestamptz,
+	actor_type actor_type,
+	actor_id text,
+	profile_ids uuid[],
+	action text,
+	resource_type text,
+	resource_id text,
+	before jsonb,
+	after jsonb,
+	diff jsonb,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_action ON audit_entries (action);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_actor_id ON audit_entries (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_created_at ON audit_entries (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_resource_id ON audit_entries (resource_id);
+
+-- audit_entries is append-only at the database level.
+CREATE OR REPLACE FUNCTION audit_entries_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries;
+CREATE TRIGGER audit_entries_append_only BEFORE UPDATE OR DELETE ON audit_entries
+	FOR EACH ROW EXECUTE FUNCTION audit_entries_immutable();
+
+CREATE TABLE IF NOT EXISTS idempotency_records (
+	caller text,
+	key text,
+	request_hash text,
+	completed boolean,
+	status_code bigint,
+	content_type text,
+	body bytea,
+	created_at timestamptz,
+	expires_at timestamptz,
+	PRIMARY KEY (caller, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,8 @@
+-- Postgres cannot drop an enum value, so the type is rebuilt without it. Drafts fall back to unknown.
+UPDATE events SET status = 'unknown' WHERE status = 'draft';
+ALTER TYPE event_application_status RENAME TO event_application_status_old;
+CREATE TYPE event_application_status AS ENUM ('open', 'unknown', 'closed', 'cancelled');
+ALTER TABLE events ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE events ALTER COLUMN status TYPE event_application_status USING status::text::event_application_status;
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'unknown';
+DROP TYPE event_application_status_old;
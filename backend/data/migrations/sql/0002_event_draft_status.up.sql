@@ -0,0 +1,3 @@
+-- migrate:no-transaction
+-- AutoMigrate created event_application_status before drafts existed and could never add the value.
+ALTER TYPE event_application_status ADD VALUE IF NOT EXISTS 'draft' BEFORE 'open';
@@ -0,0 +1 @@
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS portfolio_links jsonb NOT NULL DEFAULT '[]';
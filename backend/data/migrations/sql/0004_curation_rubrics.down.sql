@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS application_scores;
+DROP TABLE IF EXISTS rubrics;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS rubrics (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	event_ref text,
+	criteria jsonb NOT NULL DEFAULT '[]',
+	reviewer_ids uuid[] NOT NULL DEFAULT '{}',
+	blind boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rubrics_event_ref ON rubrics (event_ref);
+CREATE INDEX IF NOT EXISTS idx_rubrics_deleted_at ON rubrics (deleted_at);
+
+CREATE TABLE IF NOT EXISTS application_scores (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	event_ref text,
+	application_id text,
+	reviewer_id text,
+	scores jsonb NOT NULL DEFAULT '{}',
+	comment text,
+	total decimal,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_application_scores_event_ref ON application_scores (event_ref);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_score_application_reviewer ON application_scores (application_id, reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_application_scores_deleted_at ON application_scores (deleted_at);
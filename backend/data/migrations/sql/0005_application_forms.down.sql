@@ -0,0 +1,3 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS answers;
+ALTER TABLE applications DROP COLUMN IF EXISTS form_version;
+DROP TABLE IF EXISTS application_forms;
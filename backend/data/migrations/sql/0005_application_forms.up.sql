@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS application_forms (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	event_ref text,
+	version bigint NOT NULL,
+	questions jsonb NOT NULL DEFAULT '[]',
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_form_event_version ON application_forms (event_ref, version);
+CREATE INDEX IF NOT EXISTS idx_application_forms_deleted_at ON application_forms (deleted_at);
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS form_version bigint NOT NULL DEFAULT 0;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS answers jsonb NOT NULL DEFAULT '{}';
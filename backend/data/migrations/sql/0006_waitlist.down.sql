@@ -0,0 +1,14 @@
+-- Postgres cannot drop an enum value, so the type is rebuilt without them. The waitlist falls back to pending and
+-- declined offers to rejected.
+DROP INDEX IF EXISTS idx_applications_waitlist;
+ALTER TABLE events DROP COLUMN IF EXISTS offer_response_hours;
+ALTER TABLE applications DROP COLUMN IF EXISTS offer_expires_at;
+ALTER TABLE applications DROP COLUMN IF EXISTS waitlist_position;
+UPDATE applications SET status = 'pending' WHERE status = 'waitlisted';
+UPDATE applications SET status = 'rejected' WHERE status = 'declined';
+ALTER TYPE application_status RENAME TO application_status_old;
+CREATE TYPE application_status AS ENUM ('pending', 'offered', 'unknown', 'rejected', 'accepted');
+ALTER TABLE applications ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE applications ALTER COLUMN status TYPE application_status USING status::text::application_status;
+ALTER TABLE applications ALTER COLUMN status SET DEFAULT 'unknown';
+DROP TYPE application_status_old;
@@ -0,0 +1,7 @@
+-- migrate:no-transaction
+ALTER TYPE application_status ADD VALUE IF NOT EXISTS 'waitlisted';
+ALTER TYPE application_status ADD VALUE IF NOT EXISTS 'declined';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS waitlist_position bigint;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS offer_expires_at timestamptz;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS offer_response_hours bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_applications_waitlist ON applications (event_ref, waitlist_position) WHERE status = 'waitlisted';
@@ -0,0 +1,13 @@
+-- Postgres cannot drop an enum value, so the type is rebuilt without it. Expired offers fall back to declined. The
+-- partial indexes on status cannot survive the swap and are dropped first; the waitlist's is rebuilt after.
+DROP INDEX IF EXISTS idx_applications_offer_expires_at;
+DROP INDEX IF EXISTS idx_applications_waitlist;
+ALTER TABLE applications DROP COLUMN IF EXISTS offer_reminded_at;
+UPDATE applications SET status = 'declined' WHERE status = 'expired';
+ALTER TYPE application_status RENAME TO application_status_old;
+CREATE TYPE application_status AS ENUM ('pending', 'offered', 'unknown', 'rejected', 'accepted', 'waitlisted', 'declined');
+ALTER TABLE applications ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE applications ALTER COLUMN status TYPE application_status USING status::text::application_status;
+ALTER TABLE applications ALTER COLUMN status SET DEFAULT 'unknown';
+DROP TYPE application_status_old;
+CREATE INDEX IF NOT EXISTS idx_applications_waitlist ON applications (event_ref, waitlist_position) WHERE status = 'waitlisted';
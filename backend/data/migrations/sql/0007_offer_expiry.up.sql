@@ -0,0 +1,4 @@
+-- migrate:no-transaction
+ALTER TYPE application_status ADD VALUE IF NOT EXISTS 'expired';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS offer_reminded_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_applications_offer_expires_at ON applications (offer_expires_at) WHERE status = 'offered';
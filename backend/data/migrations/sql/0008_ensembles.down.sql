@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ensemble_members;
+ALTER TABLE profiles DROP COLUMN IF EXISTS ensemble;
+DROP TYPE IF EXISTS membership_status;
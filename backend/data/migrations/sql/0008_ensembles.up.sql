@@ -0,0 +1,18 @@
+DO $$ BEGIN CREATE TYPE membership_status AS ENUM ('invited', 'active', 'declined'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS ensemble boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS ensemble_members (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	ensemble_id text,
+	member_id text,
+	role text,
+	status membership_status NOT NULL DEFAULT 'invited',
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ensemble_member ON ensemble_members (ensemble_id, member_id);
+CREATE INDEX IF NOT EXISTS idx_ensemble_members_member_id ON ensemble_members (member_id);
+CREATE INDEX IF NOT EXISTS idx_ensemble_members_deleted_at ON ensemble_members (deleted_at);
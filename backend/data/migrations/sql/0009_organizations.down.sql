@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_profiles_organization_id;
+ALTER TABLE profiles DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_admins;
+DROP TABLE IF EXISTS organizations;
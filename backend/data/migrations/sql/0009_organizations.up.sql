@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS organizations (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name text NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_organizations_deleted_at ON organizations (deleted_at);
+
+CREATE TABLE IF NOT EXISTS organization_admins (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	organization_id text,
+	firebase_id text,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organization_admin ON organization_admins (organization_id, firebase_id);
+CREATE INDEX IF NOT EXISTS idx_organization_admins_firebase_id ON organization_admins (firebase_id);
+CREATE INDEX IF NOT EXISTS idx_organization_admins_deleted_at ON organization_admins (deleted_at);
+
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS organization_id text;
+CREATE INDEX IF NOT EXISTS idx_profiles_organization_id ON profiles (organization_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_status_time;
+ALTER TABLE events DROP COLUMN IF EXISTS hide_pay;
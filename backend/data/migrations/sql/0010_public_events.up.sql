@@ -0,0 +1,2 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS hide_pay boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_events_status_time ON events (status, time);
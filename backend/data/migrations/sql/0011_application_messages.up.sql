@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS messages (
+	id text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	application_id text,
+	author_id text,
+	sender_uid text,
+	body text NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_messages_application_id ON messages (application_id);
+CREATE INDEX IF NOT EXISTS idx_messages_deleted_at ON messages (deleted_at);
@@ -0,0 +1,12 @@
+-- The postgres broker writes each stream event here and only notifies its seq and type, since pg_notify payloads
+-- are capped at 8000 bytes. Rows are pruned once every instance has had time to read them.
+CREATE TABLE IF NOT EXISTS stream_events (
+	seq bigserial,
+	type text NOT NULL,
+	resource_id text NOT NULL,
+	audience jsonb NOT NULL DEFAULT '[]',
+	payload jsonb,
+	time timestamptz NOT NULL,
+	PRIMARY KEY (seq)
+);
+CREATE INDEX IF NOT EXISTS idx_stream_events_time ON stream_events (time);
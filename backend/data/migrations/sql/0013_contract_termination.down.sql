@@ -0,0 +1 @@
+ALTER TABLE contracts DROP COLUMN IF EXISTS terminated_at;
@@ -0,0 +1,3 @@
+-- A fully signed contract is never voided; when its application stops being accepted the termination is recorded
+-- next to the signatures.
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS terminated_at timestamptz;
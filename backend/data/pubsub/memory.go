@@ -0,0 +1,48 @@
+package pubsub
+
+import (
+	"backend/models"
+	"context"
+	"sync"
+)
+
+const subscriberBuffer = 32
+
+// MemoryBroker is an in-process broker. It only reaches subscribers connected to the same instance.
+type MemoryBroker struct {
+	mu          sync.RWMutex
+	subscribers map[chan models.StreamEvent]struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subscribers: map[chan models.StreamEvent]struct{}{}}
+}
+
+// Publish never blocks; a subscriber that is too slow to drain its buffer misses the event.
+func (b *MemoryBroker) Publish(_ context.Context, event models.StreamEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context) (<-chan models.StreamEvent, error) {
+	subscriber := make(chan models.StreamEvent, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+		b.mu.Unlock()
+	}()
+	return subscriber, nil
+}
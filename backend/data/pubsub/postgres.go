@@ -0,0 +1,155 @@
+package pubsub
+
+import (
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const notifyChannel = "ocall_stream"
+
+// retention is how long stream_events rows are kept for instances catching up after a reconnect.
+const retention = time.Hour
+
+// PostgresBroker relays events through LISTEN/NOTIFY so that every instance sharing the database sees them. An event
+// is stored in stream_events and only its seq and type are notified; each instance re-reads the row and hands it to a
+// local MemoryBroker for fan-out.
+type PostgresBroker struct {
+	orm      *gorm.DB
+	listener *pq.Listener
+	local    *MemoryBroker
+	// last is the highest seq relayed, where catching up after a reconnect starts from.
+	last int64
+}
+
+// streamRow is a row of stream_events.
+type streamRow struct {
+	Seq        int64
+	Type       string
+	ResourceID string
+	Audience   string
+	Payload    string
+	Time       time.Time
+}
+
+func (r streamRow) event() (models.StreamEvent, error) {
+	event := models.StreamEvent{Type: models.StreamEventType(r.Type), Payload: json.RawMessage(r.Payload), Time: r.Time}
+	var err error
+	if event.ResourceID, err = uuid.Parse(r.ResourceID); err != nil {
+		return event, err
+	}
+	return event, json.Unmarshal([]byte(r.Audience), &event.Audience)
+}
+
+func NewPostgresBroker(db *gorm.DB, dsn string) (*PostgresBroker, error) {
+	listener := pq.NewListener(dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("pubsub listener: %v", err)
+		}
+	})
+	if err := listener.Listen(notifyChannel); err != nil {
+		return nil, errors.Wrap(err, "unable to listen")
+	}
+	broker := &PostgresBroker{orm: db, listener: listener, local: NewMemoryBroker()}
+	if err := db.Raw("SELECT COALESCE(MAX(seq), 0) FROM stream_events").Scan(&broker.last).Error; err != nil {
+		return nil, errors.Wrap(err, "unable to read stream_events")
+	}
+	go broker.relay()
+	return broker, nil
+}
+
+func (b *PostgresBroker) relay() {
+	prune := time.NewTicker(retention / 4)
+	defer prune.Stop()
+	for {
+		select {
+		case notification, ok := <-b.listener.NotificationChannel():
+			if !ok {
+				return
+			}
+			// A nil notification means the connection was re-established; whatever was notified meanwhile is lost.
+			if notification == nil {
+				log.Printf("pubsub: listener reconnected, catching up from seq %d", b.last)
+				b.catchUp()
+				continue
+			}
+			seq, err := strconv.ParseInt(strings.SplitN(notification.Extra, " ", 2)[0], 10, 64)
+			if err != nil {
+				log.Printf("pubsub: dropping malformed notification %q", notification.Extra)
+				continue
+			}
+			b.deliver("seq = ?", seq)
+		case <-prune.C:
+			if err := b.orm.Exec("DELETE FROM stream_events WHERE time < ?", time.Now().UTC().Add(-retention)).Error; err != nil {
+				log.Printf("pubsub: unable to prune stream_events: %v", err)
+			}
+		}
+	}
+}
+
+func (b *PostgresBroker) catchUp() {
+	b.deliver("seq > ?", b.last)
+}
+
+// deliver re-reads the matching rows and publishes them locally in seq order.
+func (b *PostgresBroker) deliver(query string, args ...interface{}) {
+	var rows []streamRow
+	if err := b.orm.Table("stream_events").
+		Select("seq, type, resource_id, audience::text AS audience, payload::text AS payload, time").Where(query, args...).Order("seq").Find(&rows).Error; err != nil {
+		log.Printf("pubsub: unable to read stream_events: %v", err)
+		return
+	}
+	for _, row := range rows {
+		if row.Seq > b.last {
+			b.last = row.Seq
+		}
+		event, err := row.event()
+		if err != nil {
+			log.Printf("pubsub: dropping malformed stream event %d: %v", row.Seq, err)
+			continue
+		}
+		_ = b.local.Publish(context.Background(), event)
+	}
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, event models.StreamEvent) error {
+	audience, err := json.Marshal(event.Audience)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal audience")
+	}
+	if event.Audience == nil {
+		audience = []byte("[]")
+	}
+	payload := string(event.Payload)
+	if payload == "" {
+		payload = "null"
+	}
+	err = b.orm.WithContext(ctx).Exec(fmt.Sprintf(`WITH stored AS (
+	INSERT INTO stream_events (type, resource_id, audience, payload, time) VALUES (?, ?, ?::jsonb, ?::jsonb, ?)
+	RETURNING seq, type
+)
+SELECT pg_notify('%s', seq || ' ' || type) FROM stored`, notifyChannel),
+		string(event.Type), event.ResourceID.String(), string(audience), payload, event.Time,
+	).Error
+	if err != nil {
+		return errors.Wrap(err, "pg_notify error")
+	}
+	return nil
+}
+
+func (b *PostgresBroker) Subscribe(ctx context.Context) (<-chan models.StreamEvent, error) {
+	return b.local.Subscribe(ctx)
+}
+
+func (b *PostgresBroker) Close() error {
+	return b.listener.Close()
+}
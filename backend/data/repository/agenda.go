@@ -5,8 +5,8 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/nferruzzi/gormGIS"
-	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -18,62 +18,124 @@ func NewAgendaRepo(db *gorm.DB) AgendaRepo {
 	return AgendaRepo{orm: db}
 }
 
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *AgendaRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
 func (r *AgendaRepo) CreateEvent(ctx context.Context, event models.Event) (uuid.UUID, error) {
-	if err := r.orm.WithContext(ctx).Create(&event).Error; err != nil {
-		return uuid.Nil, errors.Wrap(err, "gorm create error")
+	if err := conn(ctx, r.orm).Create(&event).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
 	}
 	return event.ID, nil
 }
 func (r *AgendaRepo) GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error) {
 	var event models.Event
-	if err := r.orm.WithContext(ctx).First(&event, id).Error; err != nil {
-		return event, errors.Wrap(err, "gorm first error")
+	if err := conn(ctx, r.orm).First(&event, id).Error; err != nil {
+		return event, dbErr(err, "gorm first error")
 	}
 	return event, nil
 }
-func (r *AgendaRepo) UpdateEvent(ctx context.Context, event models.Event) (models.Event, error) {
-	if err := r.orm.WithContext(ctx).Save(&event).Error; err != nil {
-		return event, errors.Wrap(err, "gorm save error")
+
+// GetEventUnscoped is GetEvent for an event that may be in the trash.
+func (r *AgendaRepo) GetEventUnscoped(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	var event models.Event
+	if err := conn(ctx, r.orm).Unscoped().First(&event, id).Error; err != nil {
+		return event, dbErr(err, "gorm first error")
 	}
 	return event, nil
 }
-func (r *AgendaRepo) DeleteEvent(ctx context.Context, id uuid.UUID) error {
-	if err := r.orm.WithContext(ctx).Delete(&models.Event{}, id).Error; err != nil {
-		return errors.Wrap(err, "gorm delete error")
+
+func (r *AgendaRepo) GetProfile(ctx context.Context, id uuid.UUID) (models.Profile, error) {
+	var profile models.Profile
+	if err := conn(ctx, r.orm).First(&profile, id).Error; err != nil {
+		return profile, dbErr(err, "gorm first error")
 	}
-	return nil
+	return profile, nil
+}
+
+// published selects the events listed publicly, with what a public listing shows of them.
+func published(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags").Preload("Producer").Preload("Venue").
+		Where("status IN ?", []models.EventApplicationStatus{models.EventOpen, models.EventClosed})
+}
+func (r *AgendaRepo) GetPublicEvent(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	var events []models.Event
+	if err := published(conn(ctx, r.orm)).Where("id = ?", id).Limit(1).Find(&events).Error; err != nil {
+		return models.Event{}, dbErr(err, "gorm find error")
+	}
+	if len(events) == 0 {
+		return models.Event{}, nil
+	}
+	return events[0], nil
+}
+func (r *AgendaRepo) GetPublicEvents(ctx context.Context, filter models.PublicEventFilter) ([]models.Event, error) {
+	db := conn(ctx, r.orm)
+	query := published(db).Where("time >= ?", filter.From)
+	if filter.To != nil {
+		query = query.Where("time < ?", *filter.To)
+	}
+	if filter.ProducerID != nil {
+		query = query.Where("producer_id = ?", *filter.ProducerID)
+	}
+	if filter.VenueID != nil {
+		query = query.Where("venue_id = ?", *filter.VenueID)
+	}
+	if filter.AcceptingApplications {
+		query = query.Where("status = ? AND (apply_by_time IS NULL OR apply_by_time >= ?)", models.EventOpen, filter.From)
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", db.Table("event_tags").Select("event_tags.event_id").
+			Joins("JOIN tags ON tags.id = event_tags.tag_id AND tags.deleted_at IS NULL").
+			Where("tags.name = ?", filter.Tag))
+	}
+	var events []models.Event
+	if err := query.Order("time").Order("id").Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return events, nil
+}
+func (r *AgendaRepo) UpdateEvent(ctx context.Context, event models.Event) (models.Event, error) {
+	if err := updateVersioned(conn(ctx, r.orm), &event, event.ID, &event.Version); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+func (r *AgendaRepo) DeleteEvent(ctx context.Context, id uuid.UUID, version int64) error {
+	return deleteVersioned(conn(ctx, r.orm), &models.Event{}, id, version)
 }
 
 func (r *AgendaRepo) CreateApplication(ctx context.Context, application models.Application) (uuid.UUID, error) {
-	if err := r.orm.WithContext(ctx).Create(&application).Error; err != nil {
-		return uuid.Nil, errors.Wrap(err, "gorm create error")
+	if err := conn(ctx, r.orm).Create(&application).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
 	}
 	return application.ID, nil
 }
 func (r *AgendaRepo) GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error) {
 	var application models.Application
-	if err := r.orm.WithContext(ctx).First(&application, id).Error; err != nil {
-		return application, errors.Wrap(err, "gorm first error")
+	if err := conn(ctx, r.orm).First(&application, id).Error; err != nil {
+		return application, dbErr(err, "gorm first error")
+	}
+	if err := attachMembers(conn(ctx, r.orm), &application); err != nil {
+		return application, err
 	}
 	return application, nil
 }
 func (r *AgendaRepo) UpdateApplication(ctx context.Context, application models.Application) (models.Application, error) {
-	if err := r.orm.WithContext(ctx).Save(&application).Error; err != nil {
-		return application, errors.Wrap(err, "gorm save error")
+	if err := updateVersioned(conn(ctx, r.orm), &application, application.ID, &application.Version); err != nil {
+		return application, err
 	}
 	return application, nil
 }
-func (r *AgendaRepo) DeleteApplication(ctx context.Context, id uuid.UUID) error {
-	if err := r.orm.WithContext(ctx).Delete(&models.Application{}, id).Error; err != nil {
-		return errors.Wrap(err, "gorm delete error")
-	}
-	return nil
+func (r *AgendaRepo) DeleteApplication(ctx context.Context, id uuid.UUID, version int64) error {
+	return deleteVersioned(conn(ctx, r.orm), &models.Application{}, id, version)
 }
 
 func (r *AgendaRepo) GetEventsByProducer(ctx context.Context, producerID uuid.UUID) ([]models.Event, error) {
 	var eventPointers []*models.Event
-	if err := r.orm.WithContext(ctx).Where("producer_id = ?", producerID).Find(&eventPointers).Error; err != nil {
-		return nil, errors.Wrap(err, "gorm find error")
+	if err := conn(ctx, r.orm).Where("producer_id = ?", producerID).Find(&eventPointers).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
 	}
 	events := make([]models.Event, len(eventPointers))
 	for i, event := range eventPointers {
@@ -84,15 +146,47 @@ func (r *AgendaRepo) GetEventsByProducer(ctx context.Context, producerID uuid.UU
 
 func (r *AgendaRepo) GetApplicationsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Application, error) {
 	var event models.Event
-	if err := r.orm.WithContext(ctx).Preload("Applications").First(&event, eventID).Error; err != nil {
-		return nil, errors.Wrap(err, "gorm first error")
+	if err := conn(ctx, r.orm).Preload("Applications.Performer").First(&event, eventID).Error; err != nil {
+		return nil, dbErr(err, "gorm first error")
+	}
+	performers := make([]*models.Profile, len(event.Applications))
+	applications := make([]*models.Application, len(event.Applications))
+	for i := range event.Applications {
+		performers[i], applications[i] = &event.Applications[i].Performer, &event.Applications[i]
+	}
+	if err := attachReputation(conn(ctx, r.orm), performers...); err != nil {
+		return nil, err
+	}
+	if err := attachMembers(conn(ctx, r.orm), applications...); err != nil {
+		return nil, err
 	}
 	return event.Applications, nil
 }
+
+// GetApplicationsByEventUnscoped is GetApplicationsByEvent for an event that may be in the trash. Applications that
+// were trashed on their own are still left out.
+func (r *AgendaRepo) GetApplicationsByEventUnscoped(ctx context.Context, eventID uuid.UUID) ([]models.Application, error) {
+	var applicationPointers []*models.Application
+	if err := conn(ctx, r.orm).Where("event_ref = ?", eventID).Find(&applicationPointers).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	if err := attachMembers(conn(ctx, r.orm), applicationPointers...); err != nil {
+		return nil, err
+	}
+	applications := make([]models.Application, len(applicationPointers))
+	for i, application := range applicationPointers {
+		applications[i] = *application
+	}
+	return applications, nil
+}
+
 func (r *AgendaRepo) GetApplicationsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Application, error) {
 	var applicationPointers []*models.Application
-	if err := r.orm.WithContext(ctx).Where("performer_id = ?", performerID).Find(&applicationPointers).Error; err != nil {
-		return nil, errors.Wrap(err, "gorm find error")
+	if err := conn(ctx, r.orm).Where("performer_id = ?", performerID).Find(&applicationPointers).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	if err := attachMembers(conn(ctx, r.orm), applicationPointers...); err != nil {
+		return nil, err
 	}
 	applications := make([]models.Application, len(applicationPointers))
 	for j, app := range applicationPointers {
@@ -103,13 +197,17 @@ func (r *AgendaRepo) GetApplicationsByPerformer(ctx context.Context, performerID
 
 func (r *AgendaRepo) GetAllEvents(
 	ctx context.Context, startTime time.Time, endTime time.Time, centerPoint gormGIS.GeoPoint, distanceKM float64,
+	minPay *models.MinimumPay,
 ) ([]models.Event, error) {
 	var eventPointers []*models.Event
-	if err := r.orm.WithContext(ctx).Where("time >= ?", startTime).
+	query := conn(ctx, r.orm).Where("time >= ?", startTime).
 		Where("time <= ?", endTime).
-		Where("ST_Distance_Sphere(location, ?) <= ?", centerPoint, 1000.0*distanceKM).
-		Find(&eventPointers).Error; err != nil {
-		return nil, errors.Wrap(err, "gorm find error")
+		Where("ST_Distance_Sphere(location, ?) <= ?", centerPoint, 1000.0*distanceKM)
+	if minPay != nil {
+		query = query.Where("pay_currency = ? AND pay_min_amount >= ?", minPay.Currency, minPay.Amount)
+	}
+	if err := query.Find(&eventPointers).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
 	}
 	events := make([]models.Event, len(eventPointers))
 	for j, e := range eventPointers {
@@ -119,14 +217,242 @@ func (r *AgendaRepo) GetAllEvents(
 }
 
 func (r *AgendaRepo) CreateTag(ctx context.Context, tag models.Tag) (uint, error) {
-	if err := r.orm.WithContext(ctx).Create(&tag).Error; err != nil {
-		return 0, errors.Wrap(err, "gorm create error")
+	if err := conn(ctx, r.orm).Create(&tag).Error; err != nil {
+		return 0, dbErr(err, "gorm create error")
 	}
 	return tag.ID, nil
 }
 func (r *AgendaRepo) DeleteTag(ctx context.Context, tag models.Tag) error {
-	if err := r.orm.WithContext(ctx).Delete(&tag).Error; err != nil {
-		return errors.Wrap(err, "gorm delete error")
+	if err := conn(ctx, r.orm).Delete(&tag).Error; err != nil {
+		return dbErr(err, "gorm delete error")
 	}
 	return nil
 }
+func (r *AgendaRepo) GetTag(ctx context.Context, id uint) (models.Tag, error) {
+	var tag models.Tag
+	if err := conn(ctx, r.orm).First(&tag, id).Error; err != nil {
+		return tag, dbErr(err, "gorm first error")
+	}
+	return tag, nil
+}
+func (r *AgendaRepo) GetTagByName(ctx context.Context, name string) (models.Tag, error) {
+	var tag models.Tag
+	if err := conn(ctx, r.orm).Where("name = ?", name).First(&tag).Error; err != nil {
+		return tag, dbErr(err, "gorm first error")
+	}
+	return tag, nil
+}
+func (r *AgendaRepo) ListTags(ctx context.Context) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := conn(ctx, r.orm).Order("name").Find(&tags).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return tags, nil
+}
+func (r *AgendaRepo) ListEvents(ctx context.Context) ([]models.Event, error) {
+	var events []models.Event
+	if err := conn(ctx, r.orm).Order("created_at").Find(&events).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return events, nil
+}
+
+// exportBatchSize is how many applications EachApplicationByEvent holds in memory at once.
+const exportBatchSize = 500
+
+// EachApplicationByEvent calls fn for every application to the event, oldest first, with the performer attached. It
+// pages through the rows by (created_at, id) so an event of any size streams in constant memory.
+func (r *AgendaRepo) EachApplicationByEvent(
+	ctx context.Context, eventID uuid.UUID, status models.ApplicationStatus, fn func(models.Application) error,
+) error {
+	var last *models.Application
+	for {
+		query := conn(ctx, r.orm).Preload("Performer").Where("event_ref = ?", eventID)
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		if last != nil {
+			query = query.Where("(created_at, id) > (?, ?)", last.CreatedAt, last.ID)
+		}
+		var batch []models.Application
+		if err := query.Order("created_at, id").Limit(exportBatchSize).Find(&batch).Error; err != nil {
+			return dbErr(err, "gorm find error")
+		}
+		performers := make([]*models.Profile, len(batch))
+		applications := make([]*models.Application, len(batch))
+		for i := range batch {
+			performers[i], applications[i] = &batch[i].Performer, &batch[i]
+		}
+		if err := attachReputation(conn(ctx, r.orm), performers...); err != nil {
+			return err
+		}
+		if err := attachMembers(conn(ctx, r.orm), applications...); err != nil {
+			return err
+		}
+		for _, application := range batch {
+			if err := fn(application); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		last = &batch[len(batch)-1]
+	}
+}
+
+// GetScoreSummaries averages the reviewers' totals per application of the event. Unscored applications are absent.
+func (r *AgendaRepo) GetScoreSummaries(ctx context.Context, eventID uuid.UUID) (map[uuid.UUID]models.ScoreSummary, error) {
+	var rows []struct {
+		ApplicationID uuid.UUID
+		Average       float64
+		Reviews       int
+	}
+	if err := conn(ctx, r.orm).Model(&models.ApplicationScore{}).
+		Select("application_id, AVG(total) AS average, COUNT(*) AS reviews").
+		Where("event_ref = ?", eventID).
+		Group("application_id").
+		Scan(&rows).Error; err != nil {
+		return nil, dbErr(err, "gorm score error")
+	}
+	out := make(map[uuid.UUID]models.ScoreSummary, len(rows))
+	for _, row := range rows {
+		out[row.ApplicationID] = models.ScoreSummary{Average: row.Average, Reviews: row.Reviews}
+	}
+	return out, nil
+}
+
+// GetForm returns the given version of the event's application form, or its latest for version 0. It returns the zero
+// form, not an error, if there is none.
+func (r *AgendaRepo) GetForm(ctx context.Context, eventID uuid.UUID, version int) (models.ApplicationForm, error) {
+	var forms []models.ApplicationForm
+	query := conn(ctx, r.orm).Where("event_ref = ?", eventID)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	if err := query.Order("version DESC").Limit(1).Find(&forms).Error; err != nil {
+		return models.ApplicationForm{}, dbErr(err, "gorm find error")
+	}
+	if len(forms) == 0 {
+		return models.ApplicationForm{}, nil
+	}
+	return forms[0], nil
+}
+
+// SaveForm creates the form, or replaces the questions of the version it already is.
+func (r *AgendaRepo) SaveForm(ctx context.Context, form models.ApplicationForm) (models.ApplicationForm, error) {
+	if form.ID == uuid.Nil {
+		if err := conn(ctx, r.orm).Create(&form).Error; err != nil {
+			return form, dbErr(err, "gorm create error")
+		}
+		return form, nil
+	}
+	if err := conn(ctx, r.orm).Model(&form).Select("questions", "updated_at").Updates(&form).Error; err != nil {
+		return form, dbErr(err, "gorm update error")
+	}
+	return form, nil
+}
+
+func (r *AgendaRepo) CountApplicationsByForm(ctx context.Context, eventID uuid.UUID, version int) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.orm).Model(&models.Application{}).
+		Where("event_ref = ? AND form_version = ?", eventID, version).
+		Count(&count).Error; err != nil {
+		return 0, dbErr(err, "gorm count error")
+	}
+	return count, nil
+}
+
+// GetWaitlist returns the event's waitlisted applications in line order, with their performers.
+func (r *AgendaRepo) GetWaitlist(ctx context.Context, eventID uuid.UUID) ([]models.Application, error) {
+	var applications []models.Application
+	if err := conn(ctx, r.orm).Preload("Performer").
+		Where("event_ref = ? AND status = ?", eventID, models.StatusWaitlisted).
+		Order("waitlist_position, created_at").
+		Find(&applications).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	pointers := make([]*models.Application, len(applications))
+	for i := range applications {
+		pointers[i] = &applications[i]
+	}
+	if err := attachMembers(conn(ctx, r.orm), pointers...); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// NextWaitlisted locks and returns the first application in the event's waitlist, passing over those in skip and any
+// that a concurrent promotion holds. It returns the zero application if there is none.
+func (r *AgendaRepo) NextWaitlisted(ctx context.Context, eventID uuid.UUID, skip ...uuid.UUID) (models.Application, error) {
+	var applications []models.Application
+	query := conn(ctx, r.orm).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("event_ref = ? AND status = ?", eventID, models.StatusWaitlisted)
+	if len(skip) > 0 {
+		query = query.Where("id NOT IN ?", skip)
+	}
+	if err := query.Order("waitlist_position, created_at").
+		Limit(1).
+		Find(&applications).Error; err != nil {
+		return models.Application{}, dbErr(err, "gorm find error")
+	}
+	if len(applications) == 0 {
+		return models.Application{}, nil
+	}
+	return applications[0], nil
+}
+
+// NextWaitlistPosition is the position at the end of the event's waitlist.
+func (r *AgendaRepo) NextWaitlistPosition(ctx context.Context, eventID uuid.UUID) (int, error) {
+	var position int
+	if err := conn(ctx, r.orm).Model(&models.Application{}).
+		Select("COALESCE(MAX(waitlist_position), 0) + 1").
+		Where("event_ref = ? AND status = ?", eventID, models.StatusWaitlisted).
+		Scan(&position).Error; err != nil {
+		return 0, dbErr(err, "gorm waitlist error")
+	}
+	return position, nil
+}
+
+// offerDeadlineBatch bounds how many offers one run of the deadline job handles; the rest wait for the next run.
+const offerDeadlineBatch = 500
+
+// GetExpiredOffers returns the offered applications whose deadline is not after now, oldest deadline first.
+func (r *AgendaRepo) GetExpiredOffers(ctx context.Context, now time.Time) ([]models.Application, error) {
+	var applications []models.Application
+	if err := conn(ctx, r.orm).
+		Where("status = ? AND offer_expires_at <= ?", models.StatusOffered, now).
+		Order("offer_expires_at").
+		Limit(offerDeadlineBatch).
+		Find(&applications).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return applications, nil
+}
+
+// GetOffersToRemind returns the offered applications whose deadline falls after now and no later than until, and
+// whose performer has not been reminded yet.
+func (r *AgendaRepo) GetOffersToRemind(ctx context.Context, now time.Time, until time.Time) ([]models.Application, error) {
+	var applications []models.Application
+	if err := conn(ctx, r.orm).
+		Where("status = ? AND offer_expires_at > ? AND offer_expires_at <= ?", models.StatusOffered, now, until).
+		Where("offer_reminded_at IS NULL").
+		Order("offer_expires_at").
+		Limit(offerDeadlineBatch).
+		Find(&applications).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return applications, nil
+}
+
+// MarkOfferReminded records the reminder without bumping the application's version, so it never makes a client's
+// If-Match stale. It reports false if another run got there first or the offer has changed since.
+func (r *AgendaRepo) MarkOfferReminded(ctx context.Context, application models.Application, at time.Time) (bool, error) {
+	result := conn(ctx, r.orm).Model(&models.Application{}).
+		Where("id = ? AND version = ? AND offer_reminded_at IS NULL", application.ID, application.Version).
+		UpdateColumn("offer_reminded_at", at)
+	if result.Error != nil {
+		return false, dbErr(result.Error, "gorm update error")
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"backend/models"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestEachApplicationByEvent(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewAgendaRepo(orm)
+
+	producer := models.Profile{Name: "producer", ProfileType: models.ProducerType}
+	performer := models.Profile{Name: "performer", ProfileType: models.PerformerType}
+	create(t, db, &producer)
+	create(t, db, &performer)
+	event := models.Event{Name: "event", ProducerID: producer.ID, Time: time.Now()}
+	other := models.Event{Name: "other", ProducerID: producer.ID, Time: time.Now()}
+	create(t, db, &event)
+	create(t, db, &other)
+	var accepted []uuid.UUID
+	for i, status := range []models.ApplicationStatus{models.StatusAccepted, models.StatusPending, models.StatusAccepted} {
+		application := models.Application{Name: "act", PerformerID: performer.ID, EventRef: event.ID, Status: status}
+		application.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		create(t, db, &application)
+		if status == models.StatusAccepted {
+			accepted = append(accepted, application.ID)
+		}
+	}
+	create(t, db, &models.Application{Name: "elsewhere", PerformerID: performer.ID, EventRef: other.ID, Status: models.StatusAccepted})
+
+	tests := []struct {
+		status models.ApplicationStatus
+		want   int
+	}{
+		{status: "", want: 3},
+		{status: models.StatusAccepted, want: 2},
+		{status: models.StatusPending, want: 1},
+		{status: models.StatusRejected, want: 0},
+	}
+	for _, test := range tests {
+		var got []models.Application
+		if err := repo.EachApplicationByEvent(ctx, event.ID, test.status, func(application models.Application) error {
+			got = append(got, application)
+			return nil
+		}); err != nil {
+			t.Fatalf("EachApplicationByEvent(%q) error = %v", test.status, err)
+		}
+		if len(got) != test.want {
+			t.Errorf("EachApplicationByEvent(%q) streamed %d applications, want %d", test.status, len(got), test.want)
+		}
+		for _, application := range got {
+			if test.status != "" && application.Status != test.status {
+				t.Errorf("EachApplicationByEvent(%q) streamed a %s application", test.status, application.Status)
+			}
+			if application.Performer.Name != performer.Name {
+				t.Errorf("the performer is not attached to application %s", application.ID)
+			}
+		}
+		if test.status == models.StatusAccepted && len(got) == 2 && (got[0].ID != accepted[0] || got[1].ID != accepted[1]) {
+			t.Errorf("accepted applications streamed out of submission order")
+		}
+	}
+}
+
+func TestOfferDeadlines(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewAgendaRepo(orm)
+
+	producer := models.Profile{Name: "producer", ProfileType: models.ProducerType}
+	create(t, db, &producer)
+	event := models.Event{Name: "event", ProducerID: producer.ID, Time: time.Now()}
+	create(t, db, &event)
+	now := time.Now().UTC()
+	offer := func(in time.Duration, status models.ApplicationStatus) models.Application {
+		deadline := now.Add(in)
+		application := models.Application{Name: "act", PerformerID: producer.ID, EventRef: event.ID, Status: status, OfferExpiresAt: &deadline, Version: 1}
+		create(t, db, &application)
+		return application
+	}
+	stale := offer(-time.Minute, models.StatusOffered)
+	soon := offer(time.Hour, models.StatusOffered)
+	later := offer(48*time.Hour, models.StatusOffered)
+	offer(-time.Minute, models.StatusAccepted)
+
+	ids := func(applications []models.Application) map[uuid.UUID]bool {
+		out := map[uuid.UUID]bool{}
+		for _, application := range applications {
+			if application.EventRef == event.ID {
+				out[application.ID] = true
+			}
+		}
+		return out
+	}
+	expired, err := repo.GetExpiredOffers(ctx, now)
+	if err != nil {
+		t.Fatalf("GetExpiredOffers() error = %v", err)
+	}
+	if got := ids(expired); len(got) != 1 || !got[stale.ID] {
+		t.Errorf("GetExpiredOffers() = %v, want only %s", got, stale.ID)
+	}
+
+	due, err := repo.GetOffersToRemind(ctx, now, now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetOffersToRemind() error = %v", err)
+	}
+	if got := ids(due); len(got) != 1 || !got[soon.ID] {
+		t.Errorf("GetOffersToRemind() = %v, want only %s", got, soon.ID)
+	}
+	for attempt, want := range []bool{true, false} {
+		if marked, err := repo.MarkOfferReminded(ctx, soon, now); err != nil || marked != want {
+			t.Errorf("MarkOfferReminded() attempt %d = %v, %v, want %v", attempt+1, marked, err, want)
+		}
+	}
+	if due, err = repo.GetOffersToRemind(ctx, now, now.Add(72*time.Hour)); err != nil {
+		t.Fatalf("GetOffersToRemind() error = %v", err)
+	}
+	if got := ids(due); len(got) != 1 || !got[later.ID] {
+		t.Errorf("GetOffersToRemind() after the reminder = %v, want only %s", got, later.ID)
+	}
+	stored, err := repo.GetApplication(ctx, soon.ID)
+	if err != nil {
+		t.Fatalf("GetApplication() error = %v", err)
+	}
+	if stored.Version != soon.Version {
+		t.Errorf("MarkOfferReminded() moved the version from %d to %d", soon.Version, stored.Version)
+	}
+}
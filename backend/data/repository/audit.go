@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"gorm.io/gorm"
+)
+
+type AuditRepo struct {
+	orm *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) AuditRepo {
+	return AuditRepo{orm: db}
+}
+
+func (r *AuditRepo) CreateEntry(ctx context.Context, entry models.AuditEntry) error {
+	if err := conn(ctx, r.orm).Create(&entry).Error; err != nil {
+		return dbErr(err, "gorm create error")
+	}
+	return nil
+}
+
+func (r *AuditRepo) GetEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := conn(ctx, r.orm).Model(&models.AuditEntry{})
+	if filter.ProfileID != nil {
+		query = query.Where("? = ANY(profile_ids)", *filter.ProfileID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	var entries []models.AuditEntry
+	if err := query.Order("created_at DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&entries).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestAuditEntriesAreAppendOnly(t *testing.T) {
+	ctx, orm := testDB(t)
+	repo := NewAuditRepo(orm)
+	entry, err := models.NewAuditEntry(models.Actor{Type: models.ActorFirebase, ID: "uid"}, "event.create", "event", "1", nil, nil)
+	if err != nil {
+		t.Fatalf("NewAuditEntry() error = %v", err)
+	}
+	if err := repo.CreateEntry(ctx, entry); err != nil {
+		t.Fatalf("CreateEntry() error = %v", err)
+	}
+
+	transactor := Transactor{orm: orm}
+	for _, statement := range []string{
+		"UPDATE audit_entries SET action = 'event.delete' WHERE resource_id = '1'",
+		"DELETE FROM audit_entries WHERE resource_id = '1'",
+	} {
+		err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+			return conn(ctx, orm).Exec(statement).Error
+		})
+		if err == nil {
+			t.Errorf("%q succeeded on an append-only table", statement)
+		}
+	}
+	entries, err := repo.GetEntries(ctx, models.AuditFilter{ResourceID: "1", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "event.create" {
+		t.Errorf("entries after the refused writes = %+v, want the original one", entries)
+	}
+}
+
+func TestGetAuditEntriesByProfile(t *testing.T) {
+	ctx, orm := testDB(t)
+	repo := NewAuditRepo(orm)
+	producer, performer, other := uuid.New(), uuid.New(), uuid.New()
+	resource := uuid.NewString()
+	for _, record := range []struct {
+		action   string
+		profiles []uuid.UUID
+	}{
+		{"event.create", []uuid.UUID{producer}},
+		{"application.create", []uuid.UUID{performer, producer}},
+		{"profile.update", []uuid.UUID{other}},
+	} {
+		entry, err := models.NewAuditEntry(models.Actor{Type: models.ActorFirebase, ID: "uid"}, record.action, "test", resource, nil, nil, record.profiles...)
+		if err != nil {
+			t.Fatalf("NewAuditEntry() error = %v", err)
+		}
+		if err := repo.CreateEntry(ctx, entry); err != nil {
+			t.Fatalf("CreateEntry() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		profile uuid.UUID
+		action  string
+		want    int
+	}{
+		{name: "producer", profile: producer, want: 2},
+		{name: "performer", profile: performer, want: 1},
+		{name: "other", profile: other, want: 1},
+		{name: "stranger", profile: uuid.New(), want: 0},
+		{name: "producer and action", profile: producer, action: "event.create", want: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile := test.profile
+			entries, err := repo.GetEntries(ctx, models.AuditFilter{ProfileID: &profile, ResourceID: resource, Action: test.action, Limit: 10})
+			if err != nil {
+				t.Fatalf("GetEntries() error = %v", err)
+			}
+			if len(entries) != test.want {
+				t.Errorf("GetEntries() returned %d entries, want %d", len(entries), test.want)
+			}
+			for _, entry := range entries {
+				found := false
+				for _, id := range entry.ProfileIDs {
+					found = found || id == profile
+				}
+				if !found {
+					t.Errorf("entry %s does not concern profile %s", entry.Action, profile)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"backend/models"
+	"backend/usecase/contracts"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ContractRepo struct {
+	orm *gorm.DB
+}
+
+func NewContractRepo(db *gorm.DB) ContractRepo {
+	return ContractRepo{orm: db}
+}
+
+func (r *ContractRepo) CreateTemplate(ctx context.Context, template models.ContractTemplate) (uuid.UUID, error) {
+	if err := conn(ctx, r.orm).Create(&template).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
+	}
+	return template.ID, nil
+}
+func (r *ContractRepo) GetLatestTemplate(ctx context.Context, producerID uuid.UUID) (models.ContractTemplate, error) {
+	var templates []models.ContractTemplate
+	if err := conn(ctx, r.orm).Where("producer_id = ?", producerID).
+		Order("created_at DESC").Limit(1).Find(&templates).Error; err != nil {
+		return models.ContractTemplate{}, dbErr(err, "gorm find error")
+	}
+	if len(templates) == 0 {
+		return models.ContractTemplate{}, nil
+	}
+	return templates[0], nil
+}
+func (r *ContractRepo) GetTemplatesByProducer(ctx context.Context, producerID uuid.UUID) ([]models.ContractTemplate, error) {
+	var templates []models.ContractTemplate
+	if err := conn(ctx, r.orm).Where("producer_id = ?", producerID).
+		Order("created_at DESC").Find(&templates).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return templates, nil
+}
+
+func (r *ContractRepo) CreateContract(ctx context.Context, contract models.Contract) (uuid.UUID, error) {
+	if err := conn(ctx, r.orm).Create(&contract).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
+	}
+	return contract.ID, nil
+}
+func (r *ContractRepo) GetContract(ctx context.Context, id uuid.UUID) (models.Contract, error) {
+	var contract models.Contract
+	if err := conn(ctx, r.orm).First(&contract, id).Error; err != nil {
+		return contract, dbErr(err, "gorm first error")
+	}
+	return contract, nil
+}
+func (r *ContractRepo) GetContractByApplication(ctx context.Context, applicationID uuid.UUID) (models.Contract, error) {
+	var contracts []models.Contract
+	if err := conn(ctx, r.orm).Where("application_id = ?", applicationID).
+		Order("created_at DESC").Limit(1).Find(&contracts).Error; err != nil {
+		return models.Contract{}, dbErr(err, "gorm find error")
+	}
+	if len(contracts) == 0 {
+		return models.Contract{}, nil
+	}
+	return contracts[0], nil
+}
+
+// ReplaceContractBody only matches pending rows without signatures, so a signed contract can never be rewritten.
+func (r *ContractRepo) ReplaceContractBody(ctx context.Context, contract models.Contract) (models.Contract, error) {
+	result := conn(ctx, r.orm).Model(&models.Contract{}).
+		Where("id = ? AND status = ? AND producer_signed_at IS NULL AND performer_signed_at IS NULL", contract.ID, models.ContractPending).
+		Updates(map[string]interface{}{
+			"template_id": contract.TemplateID,
+			"body":        contract.Body,
+			"body_hash":   contract.BodyHash,
+		})
+	if result.Error != nil {
+		return contract, dbErr(result.Error, "gorm update error")
+	}
+	if result.RowsAffected == 0 {
+		return contract, contracts.ErrAlreadySigned
+	}
+	return r.GetContract(ctx, contract.ID)
+}
+
+func (r *ContractRepo) SignContract(
+	ctx context.Context, id uuid.UUID, party models.ContractParty, signerUID string, at time.Time,
+) (models.Contract, error) {
+	if party != models.ProducerParty && party != models.PerformerParty {
+		return models.Contract{}, fmt.Errorf("unknown contract party %s", party)
+	}
+	err := conn(ctx, r.orm).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Contract{}).
+			Where(fmt.Sprintf("id = ? AND status = ? AND %s_signed_at IS NULL", party), id, models.ContractPending).
+			Updates(map[string]interface{}{
+				fmt.Sprintf("%s_signer_uid", party): signerUID,
+				fmt.Sprintf("%s_signed_at", party):  at,
+			})
+		if result.Error != nil {
+			return dbErr(result.Error, "gorm update error")
+		}
+		if result.RowsAffected == 0 {
+			return contracts.ErrAlreadySigned
+		}
+		if err := tx.Model(&models.Contract{}).
+			Where("id = ? AND producer_signed_at IS NOT NULL AND performer_signed_at IS NOT NULL", id).
+			Update("status", models.ContractSigned).Error; err != nil {
+			return dbErr(err, "gorm update error")
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Contract{}, err
+	}
+	return r.GetContract(ctx, id)
+}
+
+func (r *ContractRepo) VoidContract(ctx context.Context, id uuid.UUID) error {
+	if err := conn(ctx, r.orm).Model(&models.Contract{}).Where("id = ? AND status = ?", id, models.ContractPending).
+		Update("status", models.ContractVoid).Error; err != nil {
+		return dbErr(err, "gorm update error")
+	}
+	return nil
+}
+
+func (r *ContractRepo) TerminateContract(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := conn(ctx, r.orm).Model(&models.Contract{}).
+		Where("id = ? AND status = ? AND terminated_at IS NULL", id, models.ContractSigned).
+		Update("terminated_at", at).Error; err != nil {
+		return dbErr(err, "gorm update error")
+	}
+	return nil
+}
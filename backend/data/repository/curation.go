@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CurationRepo struct {
+	orm *gorm.DB
+}
+
+func NewCurationRepo(db *gorm.DB) CurationRepo {
+	return CurationRepo{orm: db}
+}
+
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *CurationRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
+func (r *CurationRepo) GetRubric(ctx context.Context, eventID uuid.UUID) (models.Rubric, error) {
+	var rubrics []models.Rubric
+	if err := conn(ctx, r.orm).Where("event_ref = ?", eventID).Limit(1).Find(&rubrics).Error; err != nil {
+		return models.Rubric{}, dbErr(err, "gorm find error")
+	}
+	if len(rubrics) == 0 {
+		return models.Rubric{}, nil
+	}
+	return rubrics[0], nil
+}
+func (r *CurationRepo) UpsertRubric(ctx context.Context, rubric models.Rubric) (models.Rubric, error) {
+	if err := conn(ctx, r.orm).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_ref"}},
+		DoUpdates: clause.AssignmentColumns([]string{"criteria", "reviewer_ids", "blind", "updated_at"}),
+	}).Create(&rubric).Error; err != nil {
+		return rubric, dbErr(err, "gorm upsert error")
+	}
+	return r.GetRubric(ctx, rubric.EventRef)
+}
+
+func (r *CurationRepo) CountScores(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.orm).Model(&models.ApplicationScore{}).Where("event_ref = ?", eventID).Count(&count).Error; err != nil {
+		return 0, dbErr(err, "gorm count error")
+	}
+	return count, nil
+}
+func (r *CurationRepo) UpsertScore(ctx context.Context, score models.ApplicationScore) (models.ApplicationScore, error) {
+	if err := conn(ctx, r.orm).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "application_id"}, {Name: "reviewer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scores", "comment", "total", "updated_at"}),
+	}).Create(&score).Error; err != nil {
+		return score, dbErr(err, "gorm upsert error")
+	}
+	var out models.ApplicationScore
+	if err := conn(ctx, r.orm).Where("application_id = ? AND reviewer_id = ?", score.ApplicationID, score.ReviewerID).
+		First(&out).Error; err != nil {
+		return score, dbErr(err, "gorm first error")
+	}
+	return out, nil
+}
+func (r *CurationRepo) GetScoresByReviewer(ctx context.Context, eventID uuid.UUID, reviewerID uuid.UUID) ([]models.ApplicationScore, error) {
+	var scores []models.ApplicationScore
+	if err := conn(ctx, r.orm).Where("event_ref = ? AND reviewer_id = ?", eventID, reviewerID).Find(&scores).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return scores, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// activeMemberships lists the IDs on the other side of the active memberships whose column side is one of ids.
+func activeMemberships(db *gorm.DB, column string, other string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	if len(ids) == 0 {
+		return out, nil
+	}
+	if err := db.Model(&models.EnsembleMember{}).
+		Where(column+" IN ? AND status = ?", ids, models.MembershipActive).
+		Pluck(other, &out).Error; err != nil {
+		return nil, dbErr(err, "gorm pluck error")
+	}
+	return out, nil
+}
+
+// attachMembers lists the active members of every application whose performer is an ensemble.
+func attachMembers(db *gorm.DB, applications ...*models.Application) error {
+	if len(applications) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(applications))
+	for i, application := range applications {
+		ids[i] = application.PerformerID
+	}
+	var memberships []models.EnsembleMember
+	if err := db.Preload("Member").
+		Where("ensemble_id IN ? AND status = ?", ids, models.MembershipActive).
+		Order("created_at").
+		Find(&memberships).Error; err != nil {
+		return dbErr(err, "gorm find error")
+	}
+	members := map[uuid.UUID][]models.Profile{}
+	for _, membership := range memberships {
+		if membership.Member != nil {
+			members[membership.EnsembleID] = append(members[membership.EnsembleID], *membership.Member)
+		}
+	}
+	for _, application := range applications {
+		application.Members = members[application.PerformerID]
+	}
+	return nil
+}
+
+// GetCommitments returns the offered and accepted applications, other than except, to events starting strictly
+// between from and to that tie up the performer: its own, its active members' if it is an ensemble, and those of every
+// ensemble any of them actively belongs to.
+func (r *AgendaRepo) GetCommitments(
+	ctx context.Context, performerID uuid.UUID, except uuid.UUID, from time.Time, to time.Time,
+) ([]models.Application, error) {
+	members, err := activeMemberships(conn(ctx, r.orm), "ensemble_id", "member_id", []uuid.UUID{performerID})
+	if err != nil {
+		return nil, err
+	}
+	people := append([]uuid.UUID{performerID}, members...)
+	ensembles, err := activeMemberships(conn(ctx, r.orm), "member_id", "ensemble_id", people)
+	if err != nil {
+		return nil, err
+	}
+	var applications []models.Application
+	if err := conn(ctx, r.orm).
+		Joins("JOIN events ON events.id = applications.event_ref AND events.deleted_at IS NULL").
+		Where("applications.performer_id IN ? AND applications.id <> ?", append(people, ensembles...), except).
+		Where("applications.status IN ?", []models.ApplicationStatus{models.StatusOffered, models.StatusAccepted}).
+		Where("events.time > ? AND events.time < ?", from, to).
+		Find(&applications).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return applications, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"backend/domain"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// dbErr wraps a gorm error for the layers above, turning a missing row into domain.ErrNotFound.
+func dbErr(err error, op string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrNotFound.Wrap(err)
+	}
+	return errors.Wrap(err, op)
+}
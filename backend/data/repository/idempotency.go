@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type IdempotencyRepo struct {
+	orm *gorm.DB
+}
+
+func NewIdempotencyRepo(db *gorm.DB) IdempotencyRepo {
+	return IdempotencyRepo{orm: db}
+}
+
+func (r *IdempotencyRepo) Reserve(
+	ctx context.Context, record models.IdempotencyRecord, staleBefore time.Time,
+) (models.IdempotencyRecord, bool, error) {
+	db := conn(ctx, r.orm)
+	if err := db.Where("caller = ? AND key = ?", record.Caller, record.Key).
+		Where("expires_at <= ? OR (NOT completed AND created_at <= ?)", record.CreatedAt, staleBefore).
+		Delete(&models.IdempotencyRecord{}).Error; err != nil {
+		return record, false, dbErr(err, "gorm delete error")
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return record, false, dbErr(result.Error, "gorm create error")
+	}
+	if result.RowsAffected == 1 {
+		return record, true, nil
+	}
+	var stored models.IdempotencyRecord
+	if err := db.Where("caller = ? AND key = ?", record.Caller, record.Key).First(&stored).Error; err != nil {
+		return stored, false, dbErr(err, "gorm first error")
+	}
+	return stored, false, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	if err := conn(ctx, r.orm).Model(&models.IdempotencyRecord{}).
+		Where("caller = ? AND key = ? AND created_at = ?", record.Caller, record.Key, record.CreatedAt).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+		}).Error; err != nil {
+		return dbErr(err, "gorm update error")
+	}
+	return nil
+}
+
+func (r *IdempotencyRepo) Release(ctx context.Context, record models.IdempotencyRecord) error {
+	if err := conn(ctx, r.orm).
+		Where("caller = ? AND key = ? AND created_at = ? AND NOT completed", record.Caller, record.Key, record.CreatedAt).
+		Delete(&models.IdempotencyRecord{}).Error; err != nil {
+		return dbErr(err, "gorm delete error")
+	}
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.orm).Where("expires_at <= ?", before).Delete(&models.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, dbErr(result.Error, "gorm delete error")
+	}
+	return result.RowsAffected, nil
+}
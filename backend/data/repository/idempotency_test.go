@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"backend/models"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyReserve(t *testing.T) {
+	ctx, orm := testDB(t)
+	repo := NewIdempotencyRepo(orm)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	record := models.IdempotencyRecord{
+		Caller: "caller", Key: "key", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+
+	if _, reserved, err := repo.Reserve(ctx, record, now.Add(-time.Minute)); err != nil || !reserved {
+		t.Fatalf("first Reserve() = reserved %v, error %v", reserved, err)
+	}
+	retry := record
+	retry.CreatedAt = now.Add(time.Second)
+	stored, reserved, err := repo.Reserve(ctx, retry, now.Add(-time.Minute))
+	if err != nil || reserved {
+		t.Fatalf("Reserve() of a held key = reserved %v, error %v", reserved, err)
+	}
+	if !stored.CreatedAt.Equal(now) {
+		t.Errorf("Reserve() returned the reservation made at %v, want %v", stored.CreatedAt, now)
+	}
+
+	// past the lease the retry takes the key over, and the first request can no longer complete it
+	stored, reserved, err = repo.Reserve(ctx, retry, now.Add(time.Millisecond))
+	if err != nil || !reserved {
+		t.Fatalf("Reserve() of a stale key = reserved %v, error %v", reserved, err)
+	}
+	record.Completed, record.StatusCode = true, 500
+	if err := repo.Complete(ctx, record); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := repo.Release(ctx, record); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	stored, _, err = repo.Reserve(ctx, retry, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if stored.Completed || !stored.CreatedAt.Equal(retry.CreatedAt) {
+		t.Errorf("the stale request changed the key to %+v", stored)
+	}
+
+	retry.Completed, retry.StatusCode, retry.Body = true, 201, []byte("created")
+	if err := repo.Complete(ctx, retry); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := repo.Release(ctx, retry); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	stored, reserved, err = repo.Reserve(ctx, record, now.Add(time.Hour))
+	if err != nil || reserved {
+		t.Fatalf("Reserve() of a completed key = reserved %v, error %v", reserved, err)
+	}
+	if stored.StatusCode != 201 || string(stored.Body) != "created" {
+		t.Errorf("stored response %d %q, want 201 %q", stored.StatusCode, stored.Body, "created")
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MessageRepo struct {
+	orm *gorm.DB
+}
+
+func NewMessageRepo(db *gorm.DB) MessageRepo {
+	return MessageRepo{orm: db}
+}
+
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *MessageRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
+func (r *MessageRepo) CreateMessage(ctx context.Context, message models.Message) (models.Message, error) {
+	if err := conn(ctx, r.orm).Create(&message).Error; err != nil {
+		return message, dbErr(err, "gorm create error")
+	}
+	return message, nil
+}
+
+func (r *MessageRepo) GetMessage(ctx context.Context, id uuid.UUID) (models.Message, error) {
+	var message models.Message
+	if err := conn(ctx, r.orm).First(&message, id).Error; err != nil {
+		return message, dbErr(err, "gorm first error")
+	}
+	return message, nil
+}
+
+func (r *MessageRepo) GetMessagesByApplication(ctx context.Context, applicationID uuid.UUID) ([]models.Message, error) {
+	var messages []models.Message
+	if err := conn(ctx, r.orm).Where("application_id = ?", applicationID).
+		Order("created_at, id").Find(&messages).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return messages, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepo struct {
+	orm *gorm.DB
+}
+
+func NewOrganizationRepo(db *gorm.DB) OrganizationRepo {
+	return OrganizationRepo{orm: db}
+}
+
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *OrganizationRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
+// owned selects the IDs of the organization's profiles.
+func owned(db *gorm.DB, organizationID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Profile{}).Select("id").Where("organization_id = ?", organizationID)
+}
+
+func (r *OrganizationRepo) CreateOrganization(
+	ctx context.Context, organization models.Organization, admins ...string,
+) (models.Organization, error) {
+	err := conn(ctx, r.orm).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&organization).Error; err != nil {
+			return dbErr(err, "gorm create error")
+		}
+		for _, firebaseID := range admins {
+			admin := models.OrganizationAdmin{OrganizationID: organization.ID, FirebaseId: firebaseID}
+			if err := tx.Create(&admin).Error; err != nil {
+				return dbErr(err, "gorm create error")
+			}
+		}
+		return nil
+	})
+	return organization, err
+}
+func (r *OrganizationRepo) GetOrganization(ctx context.Context, id uuid.UUID) (models.Organization, error) {
+	var organization models.Organization
+	if err := conn(ctx, r.orm).Preload("Profiles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).First(&organization, id).Error; err != nil {
+		return organization, dbErr(err, "gorm first error")
+	}
+	return organization, nil
+}
+func (r *OrganizationRepo) GetOrganizationsByFirebaseId(ctx context.Context, firebaseID string) ([]models.Organization, error) {
+	db := conn(ctx, r.orm)
+	var organizations []models.Organization
+	if err := db.Where("id IN (?)",
+		db.Model(&models.OrganizationAdmin{}).Select("organization_id").Where("firebase_id = ?", firebaseID),
+	).Order("name").Find(&organizations).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return organizations, nil
+}
+
+func (r *OrganizationRepo) AddAdmin(ctx context.Context, admin models.OrganizationAdmin) (models.OrganizationAdmin, error) {
+	if err := conn(ctx, r.orm).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "firebase_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "deleted_at"}),
+	}).Create(&admin).Error; err != nil {
+		return admin, dbErr(err, "gorm upsert error")
+	}
+	return r.GetAdmin(ctx, admin.OrganizationID, admin.FirebaseId)
+}
+func (r *OrganizationRepo) GetAdmin(
+	ctx context.Context, organizationID uuid.UUID, firebaseID string,
+) (models.OrganizationAdmin, error) {
+	var admins []models.OrganizationAdmin
+	if err := conn(ctx, r.orm).Where("organization_id = ? AND firebase_id = ?", organizationID, firebaseID).
+		Limit(1).Find(&admins).Error; err != nil {
+		return models.OrganizationAdmin{}, dbErr(err, "gorm find error")
+	}
+	if len(admins) == 0 {
+		return models.OrganizationAdmin{}, nil
+	}
+	return admins[0], nil
+}
+func (r *OrganizationRepo) GetAdmins(ctx context.Context, organizationID uuid.UUID) ([]models.OrganizationAdmin, error) {
+	var admins []models.OrganizationAdmin
+	if err := conn(ctx, r.orm).Where("organization_id = ?", organizationID).
+		Order("created_at").Find(&admins).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return admins, nil
+}
+func (r *OrganizationRepo) RemoveAdmin(ctx context.Context, organizationID uuid.UUID, firebaseID string) error {
+	result := conn(ctx, r.orm).Where("organization_id = ? AND firebase_id = ?", organizationID, firebaseID).
+		Delete(&models.OrganizationAdmin{})
+	if result.Error != nil {
+		return dbErr(result.Error, "gorm delete error")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OrganizationRepo) SetProfileOrganization(
+	ctx context.Context, profileID uuid.UUID, organizationID *uuid.UUID,
+) (models.Profile, error) {
+	result := conn(ctx, r.orm).Model(&models.Profile{}).Where("id = ?", profileID).Updates(map[string]interface{}{
+		"organization_id": organizationID,
+		"version":         gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return models.Profile{}, dbErr(result.Error, "gorm update error")
+	}
+	if result.RowsAffected == 0 {
+		return models.Profile{}, domain.ErrNotFound
+	}
+	var profile models.Profile
+	if err := conn(ctx, r.orm).First(&profile, profileID).Error; err != nil {
+		return profile, dbErr(err, "gorm first error")
+	}
+	return profile, nil
+}
+func (r *OrganizationRepo) GetEvents(ctx context.Context, organizationID uuid.UUID) ([]models.Event, error) {
+	db := conn(ctx, r.orm)
+	var events []models.Event
+	if err := db.Preload("Producer").Preload("Venue").
+		Where("producer_id IN (?) OR venue_id IN (?)", owned(db, organizationID), owned(db, organizationID)).
+		Order("time").Find(&events).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return events, nil
+}
+func (r *OrganizationRepo) GetApplications(ctx context.Context, organizationID uuid.UUID) ([]models.Application, error) {
+	db := conn(ctx, r.orm)
+	events := db.Model(&models.Event{}).Select("id").
+		Where("producer_id IN (?) OR venue_id IN (?)", owned(db, organizationID), owned(db, organizationID))
+	var applicationPointers []*models.Application
+	if err := db.Preload("Performer").Where("event_ref IN (?)", events).
+		Order("created_at").Find(&applicationPointers).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	performers := make([]*models.Profile, len(applicationPointers))
+	for i, application := range applicationPointers {
+		performers[i] = &application.Performer
+	}
+	if err := attachReputation(db, performers...); err != nil {
+		return nil, err
+	}
+	if err := attachMembers(db, applicationPointers...); err != nil {
+		return nil, err
+	}
+	applications := make([]models.Application, len(applicationPointers))
+	for i, application := range applicationPointers {
+		applications[i] = *application
+	}
+	return applications, nil
+}
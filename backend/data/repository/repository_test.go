@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"backend/data/migrations"
+	"context"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"testing"
+)
+
+// testDB opens the database named by OCALL_TEST_DATABASE, migrates it, and returns a context carrying a transaction
+// that is rolled back when the test ends. Tests that need it are skipped when the variable is unset.
+func testDB(t *testing.T) (context.Context, *gorm.DB) {
+	t.Helper()
+	uri := os.Getenv("OCALL_TEST_DATABASE")
+	if uri == "" {
+		t.Skip("OCALL_TEST_DATABASE is not set")
+	}
+	orm, err := gorm.Open(postgres.New(postgres.Config{DSN: uri, PreferSimpleProtocol: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("unable to connect to the test database: %v", err)
+	}
+	migrator, err := migrations.NewMigrator(orm)
+	if err != nil {
+		t.Fatalf("unable to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("unable to migrate the test database: %v", err)
+	}
+	tx := orm.Begin()
+	if tx.Error != nil {
+		t.Fatalf("unable to begin a transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return context.WithValue(context.Background(), txKey{}, tx), orm
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type ReviewRepo struct {
+	orm *gorm.DB
+}
+
+func NewReviewRepo(db *gorm.DB) ReviewRepo {
+	return ReviewRepo{orm: db}
+}
+
+// publishedReviews restricts a query to reviews that are public at the time of the call.
+func publishedReviews(db *gorm.DB) *gorm.DB {
+	return db.Where("published_at <= ? AND moderation <> ?", time.Now().UTC(), models.ModerationRemoved)
+}
+
+// loadReputations aggregates published reviews for every given subject profile.
+func loadReputations(db *gorm.DB, profileIDs []uuid.UUID) (map[uuid.UUID]models.Reputation, error) {
+	out := map[uuid.UUID]models.Reputation{}
+	if len(profileIDs) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		SubjectProfileID uuid.UUID
+		Average          float64
+		Count            int
+	}
+	if err := publishedReviews(db.Model(&models.Review{})).
+		Select("subject_profile_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("subject_profile_id IN ?", profileIDs).
+		Group("subject_profile_id").
+		Scan(&rows).Error; err != nil {
+		return nil, dbErr(err, "gorm reputation error")
+	}
+	for _, row := range rows {
+		out[row.SubjectProfileID] = models.Reputation{Average: row.Average, Count: row.Count}
+	}
+	return out, nil
+}
+
+func attachReputation(db *gorm.DB, profiles ...*models.Profile) error {
+	ids := make([]uuid.UUID, len(profiles))
+	for i, profile := range profiles {
+		ids[i] = profile.ID
+	}
+	reputations, err := loadReputations(db, ids)
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		reputation := reputations[profile.ID]
+		profile.Reputation = &reputation
+	}
+	return nil
+}
+
+func (r *ReviewRepo) CreateReview(ctx context.Context, review models.Review) (uuid.UUID, error) {
+	result := conn(ctx, r.orm).Clauses(clause.OnConflict{DoNothing: true}).Create(&review)
+	if result.Error != nil {
+		return uuid.Nil, dbErr(result.Error, "gorm create error")
+	}
+	if result.RowsAffected == 0 {
+		return uuid.Nil, nil
+	}
+	return review.ID, nil
+}
+func (r *ReviewRepo) GetReview(ctx context.Context, id uuid.UUID) (models.Review, error) {
+	var review models.Review
+	if err := conn(ctx, r.orm).First(&review, id).Error; err != nil {
+		return review, dbErr(err, "gorm first error")
+	}
+	return review, nil
+}
+func (r *ReviewRepo) GetReviewByApplication(
+	ctx context.Context, applicationID uuid.UUID, direction models.ReviewDirection,
+) (models.Review, error) {
+	var reviews []models.Review
+	if err := conn(ctx, r.orm).Where("application_id = ? AND direction = ?", applicationID, direction).
+		Limit(1).Find(&reviews).Error; err != nil {
+		return models.Review{}, dbErr(err, "gorm find error")
+	}
+	if len(reviews) == 0 {
+		return models.Review{}, nil
+	}
+	return reviews[0], nil
+}
+func (r *ReviewRepo) PublishReviews(ctx context.Context, applicationID uuid.UUID, at time.Time) error {
+	if err := conn(ctx, r.orm).Model(&models.Review{}).
+		Where("application_id = ? AND published_at > ?", applicationID, at).
+		Update("published_at", at).Error; err != nil {
+		return dbErr(err, "gorm update error")
+	}
+	return nil
+}
+func (r *ReviewRepo) GetPublishedReviewsBySubject(ctx context.Context, subjectID uuid.UUID) ([]models.Review, error) {
+	var reviews []models.Review
+	if err := publishedReviews(conn(ctx, r.orm)).Where("subject_profile_id = ?", subjectID).
+		Order("published_at DESC").Find(&reviews).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return reviews, nil
+}
+func (r *ReviewRepo) GetFlaggedReviews(ctx context.Context) ([]models.Review, error) {
+	var reviews []models.Review
+	if err := conn(ctx, r.orm).Where("moderation = ?", models.ModerationFlagged).
+		Order("updated_at").Find(&reviews).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return reviews, nil
+}
+func (r *ReviewRepo) UpdateModeration(
+	ctx context.Context, id uuid.UUID, status models.ModerationStatus, note string,
+) (models.Review, error) {
+	if err := conn(ctx, r.orm).Model(&models.Review{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"moderation": status, "moderation_note": note}).Error; err != nil {
+		return models.Review{}, dbErr(err, "gorm update error")
+	}
+	return r.GetReview(ctx, id)
+}
+func (r *ReviewRepo) GetReputation(ctx context.Context, profileID uuid.UUID) (models.Reputation, error) {
+	reputations, err := loadReputations(conn(ctx, r.orm), []uuid.UUID{profileID})
+	if err != nil {
+		return models.Reputation{}, err
+	}
+	return reputations[profileID], nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"backend/models"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestPublishedReviews(t *testing.T) {
+	ctx, orm := testDB(t)
+	repo := NewReviewRepo(orm)
+	subject := uuid.New()
+	now := time.Now().UTC()
+	review := func(direction models.ReviewDirection, publishedAt time.Time, moderation models.ModerationStatus) models.Review {
+		return models.Review{
+			ApplicationID: uuid.New(), Direction: direction, SubjectProfileID: subject, ReviewerProfileID: uuid.New(),
+			Rating: 4, Moderation: moderation, PublishedAt: publishedAt,
+		}
+	}
+	pending := review(models.ProducerReviewsPerformer, now.Add(models.ReviewWindow), models.ModerationVisible)
+	published := review(models.ProducerReviewsPerformer, now.Add(-time.Hour), models.ModerationVisible)
+	removed := review(models.ProducerReviewsPerformer, now.Add(-time.Hour), models.ModerationRemoved)
+	for _, r := range []*models.Review{&pending, &published, &removed} {
+		id, err := repo.CreateReview(ctx, *r)
+		if err != nil || id == uuid.Nil {
+			t.Fatalf("CreateReview() = %s, %v", id, err)
+		}
+		r.ID = id
+	}
+	duplicate := pending
+	duplicate.ID, duplicate.Rating = uuid.Nil, 1
+	if id, err := repo.CreateReview(ctx, duplicate); err != nil || id != uuid.Nil {
+		t.Errorf("CreateReview() of a second review from the same side = %s, %v, want uuid.Nil", id, err)
+	}
+
+	visible := func() map[uuid.UUID]bool {
+		t.Helper()
+		reviews, err := repo.GetPublishedReviewsBySubject(ctx, subject)
+		if err != nil {
+			t.Fatalf("GetPublishedReviewsBySubject() error = %v", err)
+		}
+		out := map[uuid.UUID]bool{}
+		for _, r := range reviews {
+			out[r.ID] = true
+		}
+		return out
+	}
+	if got := visible(); got[pending.ID] || !got[published.ID] || got[removed.ID] {
+		t.Errorf("published reviews = %v, want only %s", got, published.ID)
+	}
+	if err := repo.PublishReviews(ctx, pending.ApplicationID, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("PublishReviews() error = %v", err)
+	}
+	if got := visible(); !got[pending.ID] {
+		t.Errorf("a review is still hidden after PublishReviews()")
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettlementRepo struct {
+	orm *gorm.DB
+}
+
+func NewSettlementRepo(db *gorm.DB) SettlementRepo {
+	return SettlementRepo{orm: db}
+}
+
+func (r *SettlementRepo) GetRevenue(ctx context.Context, eventID uuid.UUID) (models.EventRevenue, error) {
+	var revenues []models.EventRevenue
+	if err := conn(ctx, r.orm).Where("event_ref = ?", eventID).Limit(1).Find(&revenues).Error; err != nil {
+		return models.EventRevenue{}, dbErr(err, "gorm find error")
+	}
+	if len(revenues) == 0 {
+		return models.EventRevenue{}, nil
+	}
+	return revenues[0], nil
+}
+func (r *SettlementRepo) UpsertRevenue(ctx context.Context, revenue models.EventRevenue) (models.EventRevenue, error) {
+	if err := conn(ctx, r.orm).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_ref"}},
+		DoUpdates: clause.AssignmentColumns([]string{"door_revenue", "tickets_sold", "currency", "notes", "updated_at"}),
+	}).Create(&revenue).Error; err != nil {
+		return revenue, dbErr(err, "gorm upsert error")
+	}
+	return r.GetRevenue(ctx, revenue.EventRef)
+}
+
+func (r *SettlementRepo) CreatePayment(ctx context.Context, payment models.Payment) (uuid.UUID, error) {
+	if err := conn(ctx, r.orm).Create(&payment).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
+	}
+	return payment.ID, nil
+}
+func (r *SettlementRepo) GetPaymentsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := conn(ctx, r.orm).Where("event_ref = ?", eventID).Order("paid_at").Find(&payments).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return payments, nil
+}
+func (r *SettlementRepo) GetPaymentsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := conn(ctx, r.orm).Where("performer_id = ?", performerID).Order("paid_at").Find(&payments).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return payments, nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"backend/models"
+	"backend/usecase/trash"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+type TrashRepo struct {
+	orm *gorm.DB
+}
+
+func NewTrashRepo(db *gorm.DB) TrashRepo {
+	return TrashRepo{orm: db}
+}
+
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *TrashRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
+var trashTables = map[models.TrashKind]string{
+	models.TrashEvent:       "events",
+	models.TrashApplication: "applications",
+	models.TrashProfile:     "profiles",
+}
+
+type trashRow struct {
+	ID          uuid.UUID
+	Name        string
+	DeletedAt   time.Time
+	ProducerID  *uuid.UUID
+	PerformerID *uuid.UUID
+}
+
+// deleted selects the soft-deleted rows of one kind together with the profiles that own them.
+func deleted(db *gorm.DB, kind models.TrashKind) *gorm.DB {
+	switch kind {
+	case models.TrashEvent:
+		return db.Unscoped().Table("events").
+			Select("events.id, events.name, events.deleted_at, events.producer_id").
+			Where("events.deleted_at IS NOT NULL")
+	case models.TrashApplication:
+		return db.Unscoped().Table("applications").
+			Select("applications.id, applications.name, applications.deleted_at, applications.performer_id, events.producer_id").
+			Joins("LEFT JOIN events ON events.id = applications.event_ref").
+			Where("applications.deleted_at IS NOT NULL")
+	default:
+		return db.Unscoped().Table("profiles").
+			Select("profiles.id, profiles.name, profiles.deleted_at, profiles.id AS producer_id").
+			Where("profiles.deleted_at IS NOT NULL")
+	}
+}
+
+func ownedBy(query *gorm.DB, kind models.TrashKind, profileID uuid.UUID) *gorm.DB {
+	switch kind {
+	case models.TrashEvent:
+		return query.Where("events.producer_id = ?", profileID)
+	case models.TrashApplication:
+		return query.Where("applications.performer_id = ? OR events.producer_id = ?", profileID, profileID)
+	default:
+		return query.Where("profiles.id = ?", profileID)
+	}
+}
+
+func scanTrash(query *gorm.DB, kind models.TrashKind) ([]models.TrashItem, error) {
+	var rows []trashRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	items := make([]models.TrashItem, len(rows))
+	for i, row := range rows {
+		items[i] = models.TrashItem{Kind: kind, ID: row.ID, Name: row.Name, DeletedAt: row.DeletedAt}
+		for _, owner := range []*uuid.UUID{row.ProducerID, row.PerformerID} {
+			if owner != nil {
+				items[i].ProfileIDs = append(items[i].ProfileIDs, *owner)
+			}
+		}
+	}
+	return items, nil
+}
+
+func (r *TrashRepo) collect(ctx context.Context, filter func(query *gorm.DB, kind models.TrashKind) *gorm.DB) ([]models.TrashItem, error) {
+	var items []models.TrashItem
+	for _, kind := range []models.TrashKind{models.TrashProfile, models.TrashEvent, models.TrashApplication} {
+		found, err := scanTrash(filter(deleted(conn(ctx, r.orm), kind), kind), kind)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+func (r *TrashRepo) GetTrash(ctx context.Context, profileID uuid.UUID) ([]models.TrashItem, error) {
+	return r.collect(ctx, func(query *gorm.DB, kind models.TrashKind) *gorm.DB {
+		return ownedBy(query, kind, profileID)
+	})
+}
+
+func (r *TrashRepo) GetTrashItem(
+	ctx context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID,
+) (models.TrashItem, error) {
+	query := ownedBy(deleted(conn(ctx, r.orm), kind), kind, profileID).Where(trashTables[kind]+".id = ?", id)
+	items, err := scanTrash(query, kind)
+	if err != nil || len(items) == 0 {
+		return models.TrashItem{}, err
+	}
+	return items[0], nil
+}
+
+func (r *TrashRepo) GetExpiredTrash(ctx context.Context, cutoff time.Time) ([]models.TrashItem, error) {
+	return r.collect(ctx, func(query *gorm.DB, kind models.TrashKind) *gorm.DB {
+		return query.Where(trashTables[kind]+".deleted_at < ?", cutoff)
+	})
+}
+
+// parentDeleted reports whether the event or profiles an item hangs off are themselves still in the trash.
+func parentDeleted(tx *gorm.DB, item models.TrashItem) (bool, error) {
+	var count int64
+	var err error
+	switch item.Kind {
+	case models.TrashEvent:
+		err = tx.Unscoped().Table("profiles").
+			Where("deleted_at IS NOT NULL AND id = (SELECT producer_id FROM events WHERE id = ?)", item.ID).
+			Count(&count).Error
+	case models.TrashApplication:
+		err = tx.Raw(`SELECT COUNT(*) FROM applications a
+			LEFT JOIN events e ON e.id = a.event_ref
+			LEFT JOIN profiles p ON p.id = a.performer_id
+			WHERE a.id = ? AND (e.deleted_at IS NOT NULL OR p.deleted_at IS NOT NULL)`, item.ID).
+			Scan(&count).Error
+	}
+	return count > 0, err
+}
+
+func (r *TrashRepo) Restore(ctx context.Context, item models.TrashItem) error {
+	return conn(ctx, r.orm).Transaction(func(tx *gorm.DB) error {
+		if blocked, err := parentDeleted(tx, item); err != nil {
+			return dbErr(err, "gorm find error")
+		} else if blocked {
+			return trash.ErrParentDeleted
+		}
+		if err := tx.Unscoped().Table(trashTables[item.Kind]).Where("id = ?", item.ID).
+			UpdateColumns(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return dbErr(err, "gorm update error")
+		}
+		return nil
+	})
+}
+
+// purgeApplications hard-deletes the applications the condition selects, with their messages and scores.
+func purgeApplications(tx *gorm.DB, query string, args ...interface{}) error {
+	var ids []uuid.UUID
+	if err := tx.Unscoped().Model(&models.Application{}).Where(query, args...).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Where("application_id IN ?", ids).Delete(&models.Message{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("application_id IN ?", ids).Delete(&models.ApplicationScore{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Application{}).Error
+}
+
+// purgeEvents hard-deletes events with their applications, rubrics, forms and tag links.
+func purgeEvents(tx *gorm.DB, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM event_tags WHERE event_id IN ?", ids).Error; err != nil {
+		return err
+	}
+	if err := purgeApplications(tx, "event_ref IN ?", ids); err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("event_ref IN ?", ids).Delete(&models.Rubric{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("event_ref IN ?", ids).Delete(&models.ApplicationForm{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Event{}).Error
+}
+
+// purgeProfile hard-deletes a profile, its events, its applications, its members and its ensemble memberships either
+// way, and unlinks it as a venue.
+func purgeProfile(tx *gorm.DB, id uuid.UUID) error {
+	var eventIDs []uuid.UUID
+	if err := tx.Unscoped().Model(&models.Event{}).Where("producer_id = ?", id).Pluck("id", &eventIDs).Error; err != nil {
+		return err
+	}
+	if err := purgeEvents(tx, eventIDs); err != nil {
+		return err
+	}
+	if err := purgeApplications(tx, "performer_id = ?", id); err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.Event{}).Where("venue_id = ?", id).UpdateColumn("venue_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("profile_id = ?", id).Delete(&models.UserID{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("ensemble_id = ? OR member_id = ?", id, id).Delete(&models.EnsembleMember{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.Profile{}, id).Error
+}
+
+// Purge leaves contracts, payments, reviews and audit entries in place; they record what happened and reference the
+// purged rows by ID only. Messages and scores go with their application, rubrics and forms with their event.
+func (r *TrashRepo) Purge(ctx context.Context, item models.TrashItem) error {
+	err := conn(ctx, r.orm).Transaction(func(tx *gorm.DB) error {
+		switch item.Kind {
+		case models.TrashEvent:
+			return purgeEvents(tx, []uuid.UUID{item.ID})
+		case models.TrashApplication:
+			return purgeApplications(tx, "id = ?", item.ID)
+		default:
+			return purgeProfile(tx, item.ID)
+		}
+	})
+	if err != nil {
+		return dbErr(err, "gorm delete error")
+	}
+	return nil
+}
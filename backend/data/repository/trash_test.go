@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"backend/models"
+	"backend/usecase/trash"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+// remaining counts the rows of table that match the condition, soft-deleted or not.
+func remaining(t *testing.T, db *gorm.DB, table string, query string, args ...interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Unscoped().Table(table).Where(query, args...).Count(&count).Error; err != nil {
+		t.Fatalf("counting %s: %v", table, err)
+	}
+	return count
+}
+
+func create(t *testing.T, db *gorm.DB, value interface{}) {
+	t.Helper()
+	if err := db.Create(value).Error; err != nil {
+		t.Fatalf("Create(%T) error = %v", value, err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewTrashRepo(orm)
+
+	producer := models.Profile{Name: "producer", ProfileType: models.ProducerType}
+	performer := models.Profile{Name: "performer", ProfileType: models.PerformerType}
+	create(t, db, &producer)
+	create(t, db, &performer)
+	event := models.Event{Name: "event", ProducerID: producer.ID, Time: time.Now()}
+	create(t, db, &event)
+	application := models.Application{Name: "act", PerformerID: performer.ID, EventRef: event.ID}
+	create(t, db, &application)
+	create(t, db, &models.Message{ApplicationID: application.ID, AuthorID: performer.ID, Body: "hello"})
+	create(t, db, &models.Rubric{EventRef: event.ID})
+	create(t, db, &models.ApplicationForm{EventRef: event.ID, Version: 1})
+	create(t, db, &models.ApplicationScore{EventRef: event.ID, ApplicationID: application.ID, ReviewerID: producer.ID})
+
+	if err := db.Delete(&event).Error; err != nil {
+		t.Fatalf("deleting the event: %v", err)
+	}
+	if err := repo.Purge(ctx, models.TrashItem{Kind: models.TrashEvent, ID: event.ID}); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	for _, left := range []struct {
+		table string
+		query string
+		arg   uuid.UUID
+	}{
+		{"events", "id = ?", event.ID},
+		{"applications", "id = ?", application.ID},
+		{"messages", "application_id = ?", application.ID},
+		{"rubrics", "event_ref = ?", event.ID},
+		{"application_forms", "event_ref = ?", event.ID},
+		{"application_scores", "application_id = ?", application.ID},
+	} {
+		if n := remaining(t, db, left.table, left.query, left.arg); n != 0 {
+			t.Errorf("%d rows left in %s after purging the event", n, left.table)
+		}
+	}
+	if n := remaining(t, db, "profiles", "id IN ?", []uuid.UUID{producer.ID, performer.ID}); n != 2 {
+		t.Errorf("%d of the 2 profiles left after purging the event", n)
+	}
+}
+
+func TestPurgeProfile(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewTrashRepo(orm)
+
+	ensemble := models.Profile{Name: "ensemble", ProfileType: models.PerformerType, Ensemble: true}
+	performer := models.Profile{Name: "performer", ProfileType: models.PerformerType}
+	band := models.Profile{Name: "band", ProfileType: models.PerformerType, Ensemble: true}
+	create(t, db, &ensemble)
+	create(t, db, &performer)
+	create(t, db, &band)
+	create(t, db, &models.EnsembleMember{EnsembleID: ensemble.ID, MemberID: performer.ID, Status: models.MembershipActive})
+	create(t, db, &models.EnsembleMember{EnsembleID: band.ID, MemberID: performer.ID, Status: models.MembershipActive})
+	create(t, db, &models.EnsembleMember{EnsembleID: band.ID, MemberID: ensemble.ID, Status: models.MembershipInvited})
+
+	if err := db.Delete(&performer).Error; err != nil {
+		t.Fatalf("deleting the profile: %v", err)
+	}
+	if err := repo.Purge(ctx, models.TrashItem{Kind: models.TrashProfile, ID: performer.ID}); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	if n := remaining(t, db, "profiles", "id = ?", performer.ID); n != 0 {
+		t.Errorf("the purged profile is still there")
+	}
+	if n := remaining(t, db, "ensemble_members", "ensemble_id = ? OR member_id = ?", performer.ID, performer.ID); n != 0 {
+		t.Errorf("%d memberships of the purged profile left", n)
+	}
+	if n := remaining(t, db, "ensemble_members", "ensemble_id = ? AND member_id = ?", band.ID, ensemble.ID); n != 1 {
+		t.Errorf("purging a profile removed a membership it is not part of")
+	}
+}
+
+func TestPurgeProfileDependents(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewTrashRepo(orm)
+
+	profile := models.Profile{Name: "producing venue", ProfileType: models.ProducerType}
+	other := models.Profile{Name: "other producer", ProfileType: models.ProducerType}
+	create(t, db, &profile)
+	create(t, db, &other)
+	create(t, db, &models.UserID{FirebaseId: "admin", Permissions: models.Admin, ProfileId: profile.ID})
+	own := models.Event{Name: "own", ProducerID: profile.ID, Time: time.Now()}
+	hosted := models.Event{Name: "hosted", ProducerID: other.ID, VenueID: &profile.ID, Time: time.Now()}
+	create(t, db, &own)
+	create(t, db, &hosted)
+	toOwn := models.Application{Name: "to own", PerformerID: other.ID, EventRef: own.ID}
+	byProfile := models.Application{Name: "by profile", PerformerID: profile.ID, EventRef: hosted.ID}
+	create(t, db, &toOwn)
+	create(t, db, &byProfile)
+	create(t, db, &models.Message{ApplicationID: byProfile.ID, AuthorID: profile.ID, Body: "hello"})
+
+	if err := db.Delete(&profile).Error; err != nil {
+		t.Fatalf("deleting the profile: %v", err)
+	}
+	if err := repo.Purge(ctx, models.TrashItem{Kind: models.TrashProfile, ID: profile.ID}); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	for _, left := range []struct {
+		table string
+		query string
+		arg   uuid.UUID
+	}{
+		{"profiles", "id = ?", profile.ID},
+		{"user_ids", "profile_id = ?", profile.ID},
+		{"events", "id = ?", own.ID},
+		{"applications", "id = ?", toOwn.ID},
+		{"applications", "id = ?", byProfile.ID},
+		{"messages", "application_id = ?", byProfile.ID},
+		{"events", "venue_id = ?", profile.ID},
+	} {
+		if n := remaining(t, db, left.table, left.query, left.arg); n != 0 {
+			t.Errorf("%d rows left in %s where %s after purging the profile", n, left.table, left.query)
+		}
+	}
+	if n := remaining(t, db, "events", "id = ?", hosted.ID); n != 1 {
+		t.Errorf("purging the venue removed the event it hosted")
+	}
+}
+
+func TestRestoreNeedsParent(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewTrashRepo(orm)
+
+	producer := models.Profile{Name: "producer", ProfileType: models.ProducerType}
+	create(t, db, &producer)
+	event := models.Event{Name: "event", ProducerID: producer.ID, Time: time.Now()}
+	create(t, db, &event)
+	application := models.Application{Name: "act", PerformerID: producer.ID, EventRef: event.ID}
+	create(t, db, &application)
+	for _, value := range []interface{}{&application, &event} {
+		if err := db.Delete(value).Error; err != nil {
+			t.Fatalf("deleting %T: %v", value, err)
+		}
+	}
+
+	item, err := repo.GetTrashItem(ctx, producer.ID, models.TrashApplication, application.ID)
+	if err != nil || item.ID != application.ID {
+		t.Fatalf("GetTrashItem() = %+v, %v, want the deleted application", item, err)
+	}
+	if err := repo.Restore(ctx, item); !errors.Is(err, trash.ErrParentDeleted) {
+		t.Fatalf("Restore() of an application of a deleted event error = %v, want %v", err, trash.ErrParentDeleted)
+	}
+	if err := repo.Restore(ctx, models.TrashItem{Kind: models.TrashEvent, ID: event.ID}); err != nil {
+		t.Fatalf("Restore() of the event error = %v", err)
+	}
+	if err := repo.Restore(ctx, item); err != nil {
+		t.Fatalf("Restore() of the application after its event error = %v", err)
+	}
+	if n := remaining(t, db, "applications", "id = ? AND deleted_at IS NULL", application.ID); n != 1 {
+		t.Errorf("the application is still deleted after Restore()")
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// conn returns the transaction ctx carries, if any, so repository calls made inside Transactor.InTransaction join it.
+func conn(ctx context.Context, orm *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return orm.WithContext(ctx)
+}
+
+type Transactor struct {
+	orm *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return Transactor{orm: db}
+}
+
+// InTransaction runs fn in a transaction carried by the context it is given; every repository sharing the connection
+// joins it. An error from fn rolls back and is returned as is. Nested calls become savepoints, so an inner failure
+// can be rolled back without giving up the outer transaction.
+func (t *Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.orm).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
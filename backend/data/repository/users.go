@@ -1,11 +1,12 @@
 package repository
 
 import (
+	"backend/domain"
 	"backend/models"
 	"context"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepo struct {
@@ -16,36 +17,170 @@ func NewUserRepo(db *gorm.DB) UserRepo {
 	return UserRepo{orm: db}
 }
 
+// InTransaction runs fn in a transaction, or a savepoint when ctx already carries one. See Transactor.
+func (r *UserRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	transactor := Transactor{orm: r.orm}
+	return transactor.InTransaction(ctx, fn)
+}
+
 func (r *UserRepo) CreateProfile(ctx context.Context, profile models.Profile) (uuid.UUID, error) {
-	result := r.orm.WithContext(ctx).Create(&profile)
+	result := conn(ctx, r.orm).Create(&profile)
 	if result.Error != nil {
-		return uuid.Nil, errors.Wrap(result.Error, "gorm create error")
+		return uuid.Nil, dbErr(result.Error, "gorm create error")
 	}
 	return profile.ID, nil
 }
 func (r *UserRepo) GetProfileByID(ctx context.Context, id uuid.UUID) (models.Profile, error) {
 	var profile models.Profile
-	if err := r.orm.WithContext(ctx).First(&profile, id).Error; err != nil {
-		return profile, errors.Wrap(err, "gorm first error")
+	if err := conn(ctx, r.orm).First(&profile, id).Error; err != nil {
+		return profile, dbErr(err, "gorm first error")
+	}
+	if err := attachReputation(conn(ctx, r.orm), &profile); err != nil {
+		return profile, err
 	}
 	return profile, nil
 }
 func (r *UserRepo) UpdateProfile(ctx context.Context, profile models.Profile) (models.Profile, error) {
-	if err := r.orm.WithContext(ctx).Save(&profile).Error; err != nil {
-		return profile, errors.Wrap(err, "gorm save error")
+	if err := updateVersioned(conn(ctx, r.orm), &profile, profile.ID, &profile.Version); err != nil {
+		return profile, err
 	}
 	return profile, nil
 }
-func (r *UserRepo) DeleteProfile(ctx context.Context, id uuid.UUID) error {
-	if err := r.orm.WithContext(ctx).Delete(&models.Profile{}, id).Error; err != nil {
-		return errors.Wrap(err, "gorm delete error")
-	}
-	return nil
+func (r *UserRepo) DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error {
+	return deleteVersioned(conn(ctx, r.orm), &models.Profile{}, id, version)
 }
 func (r *UserRepo) GetUsersByProfileId(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
 	var profile models.Profile
-	if err := r.orm.WithContext(ctx).Preload("UserID").Where("id = ?", id).First(&profile).Error; err != nil {
-		return nil, errors.Wrap(err, "gorm find error")
+	if err := conn(ctx, r.orm).Preload("UserIDs").Where("id = ?", id).First(&profile).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
 	}
 	return profile.UserIDs, nil
 }
+func (r *UserRepo) GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
+	return authorizedUsers(conn(ctx, r.orm), id, false)
+}
+
+// GetAuthorizedUsersUnscoped is GetAuthorizedUsers for a profile that may be soft-deleted. Its members are still the
+// ones that have not been removed.
+func (r *UserRepo) GetAuthorizedUsersUnscoped(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
+	return authorizedUsers(conn(ctx, r.orm), id, true)
+}
+
+func authorizedUsers(db *gorm.DB, id uuid.UUID, deleted bool) ([]models.UserID, error) {
+	profiles := db
+	if deleted {
+		profiles = db.Unscoped()
+	}
+	var profile models.Profile
+	if err := profiles.Where("id = ?", id).First(&profile).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	var users []models.UserID
+	if err := db.Where("profile_id = ?", profile.ID).Find(&users).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	if profile.OrganizationID == nil {
+		return users, nil
+	}
+	var admins []models.OrganizationAdmin
+	if err := db.Where("organization_id = ?", *profile.OrganizationID).
+		Order("created_at").Find(&admins).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	for _, admin := range admins {
+		users = append(users, models.UserID{
+			Model: admin.Model, FirebaseId: admin.FirebaseId, Permissions: models.Admin, ProfileId: profile.ID,
+		})
+	}
+	return users, nil
+}
+func (r *UserRepo) GetProfilesByFirebaseId(ctx context.Context, firebaseID string) ([]models.Profile, error) {
+	db := conn(ctx, r.orm)
+	var profiles []models.Profile
+	if err := db.
+		Where("id IN (?)", db.Model(&models.UserID{}).Select("profile_id").Where("firebase_id = ?", firebaseID)).
+		Or("organization_id IN (?)",
+			db.Model(&models.OrganizationAdmin{}).Select("organization_id").Where("firebase_id = ?", firebaseID)).
+		Find(&profiles).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return profiles, nil
+}
+func (r *UserRepo) ListProfiles(ctx context.Context) ([]models.Profile, error) {
+	var profiles []models.Profile
+	if err := conn(ctx, r.orm).Order("created_at").Find(&profiles).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return profiles, nil
+}
+func (r *UserRepo) CreateUserID(ctx context.Context, user models.UserID) (uuid.UUID, error) {
+	if err := conn(ctx, r.orm).Create(&user).Error; err != nil {
+		return uuid.Nil, dbErr(err, "gorm create error")
+	}
+	return user.ID, nil
+}
+func (r *UserRepo) GetUserID(ctx context.Context, id uuid.UUID) (models.UserID, error) {
+	var user models.UserID
+	if err := conn(ctx, r.orm).First(&user, id).Error; err != nil {
+		return user, dbErr(err, "gorm first error")
+	}
+	return user, nil
+}
+func (r *UserRepo) UpdatePermission(ctx context.Context, id uuid.UUID, permission models.Permission) error {
+	result := conn(ctx, r.orm).Model(&models.UserID{}).Where("id = ?", id).Update("permissions", permission)
+	if result.Error != nil {
+		return dbErr(result.Error, "gorm update error")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) UpsertMembership(ctx context.Context, membership models.EnsembleMember) (models.EnsembleMember, error) {
+	if err := conn(ctx, r.orm).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ensemble_id"}, {Name: "member_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "status", "updated_at", "deleted_at"}),
+	}).Create(&membership).Error; err != nil {
+		return membership, dbErr(err, "gorm upsert error")
+	}
+	return r.GetMembership(ctx, membership.EnsembleID, membership.MemberID)
+}
+func (r *UserRepo) GetMembership(ctx context.Context, ensembleID uuid.UUID, memberID uuid.UUID) (models.EnsembleMember, error) {
+	var memberships []models.EnsembleMember
+	if err := conn(ctx, r.orm).Where("ensemble_id = ? AND member_id = ?", ensembleID, memberID).
+		Limit(1).Find(&memberships).Error; err != nil {
+		return models.EnsembleMember{}, dbErr(err, "gorm find error")
+	}
+	if len(memberships) == 0 {
+		return models.EnsembleMember{}, nil
+	}
+	return memberships[0], nil
+}
+func (r *UserRepo) DeleteMembership(ctx context.Context, ensembleID uuid.UUID, memberID uuid.UUID) error {
+	result := conn(ctx, r.orm).Where("ensemble_id = ? AND member_id = ?", ensembleID, memberID).
+		Delete(&models.EnsembleMember{})
+	if result.Error != nil {
+		return dbErr(result.Error, "gorm delete error")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+func (r *UserRepo) GetMembers(ctx context.Context, ensembleID uuid.UUID) ([]models.EnsembleMember, error) {
+	var memberships []models.EnsembleMember
+	if err := conn(ctx, r.orm).Preload("Member").Where("ensemble_id = ?", ensembleID).
+		Order("created_at").Find(&memberships).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return memberships, nil
+}
+func (r *UserRepo) GetEnsembles(ctx context.Context, memberID uuid.UUID) ([]models.EnsembleMember, error) {
+	var memberships []models.EnsembleMember
+	if err := conn(ctx, r.orm).Preload("Ensemble").Where("member_id = ?", memberID).
+		Order("created_at").Find(&memberships).Error; err != nil {
+		return nil, dbErr(err, "gorm find error")
+	}
+	return memberships, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"backend/models"
+	"testing"
+)
+
+func TestAuthorizedUsersIncludeOrganizationAdmins(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewUserRepo(orm)
+
+	organization := models.Organization{Name: "company"}
+	create(t, db, &organization)
+	owned := models.Profile{Name: "brand", ProfileType: models.ProducerType, OrganizationID: &organization.ID}
+	alone := models.Profile{Name: "venue", ProfileType: models.VenueType}
+	create(t, db, &owned)
+	create(t, db, &alone)
+	create(t, db, &models.UserID{FirebaseId: "booker", Permissions: models.Restricted, ProfileId: owned.ID})
+	create(t, db, &models.OrganizationAdmin{OrganizationID: organization.ID, FirebaseId: "org-admin"})
+	removed := models.OrganizationAdmin{OrganizationID: organization.ID, FirebaseId: "former"}
+	create(t, db, &removed)
+	if err := db.Delete(&removed).Error; err != nil {
+		t.Fatalf("removing an admin: %v", err)
+	}
+
+	users, err := repo.GetAuthorizedUsers(ctx, owned.ID)
+	if err != nil {
+		t.Fatalf("GetAuthorizedUsers() error = %v", err)
+	}
+	got := map[string]models.Permission{}
+	for _, user := range users {
+		if user.ProfileId != owned.ID {
+			t.Errorf("user %s is authorized for profile %s, want %s", user.FirebaseId, user.ProfileId, owned.ID)
+		}
+		got[user.FirebaseId] = user.Permissions
+	}
+	if len(got) != 2 || got["booker"] != models.Restricted || got["org-admin"] != models.Admin {
+		t.Errorf("GetAuthorizedUsers() = %v, want the restricted member and the organization admin as admin", got)
+	}
+
+	if users, err := repo.GetAuthorizedUsers(ctx, alone.ID); err != nil || len(users) != 0 {
+		t.Errorf("GetAuthorizedUsers() of a profile outside the organization = %v, %v, want nobody", users, err)
+	}
+
+	if err := db.Delete(&owned).Error; err != nil {
+		t.Fatalf("deleting the profile: %v", err)
+	}
+	if _, err := repo.GetAuthorizedUsers(ctx, owned.ID); err == nil {
+		t.Errorf("GetAuthorizedUsers() of a deleted profile found it")
+	}
+	if users, err := repo.GetAuthorizedUsersUnscoped(ctx, owned.ID); err != nil || len(users) != 2 {
+		t.Errorf("GetAuthorizedUsersUnscoped() of a deleted profile = %v, %v, want its two users", users, err)
+	}
+}
+
+func TestGetProfilesByFirebaseIdThroughOrganization(t *testing.T) {
+	ctx, orm := testDB(t)
+	db := conn(ctx, orm)
+	repo := NewUserRepo(orm)
+
+	organization := models.Organization{Name: "company"}
+	create(t, db, &organization)
+	owned := models.Profile{Name: "brand", ProfileType: models.ProducerType, OrganizationID: &organization.ID}
+	own := models.Profile{Name: "solo", ProfileType: models.PerformerType}
+	other := models.Profile{Name: "other", ProfileType: models.VenueType}
+	create(t, db, &owned)
+	create(t, db, &own)
+	create(t, db, &other)
+	create(t, db, &models.OrganizationAdmin{OrganizationID: organization.ID, FirebaseId: "org-admin"})
+	create(t, db, &models.UserID{FirebaseId: "org-admin", Permissions: models.Admin, ProfileId: own.ID})
+
+	profiles, err := repo.GetProfilesByFirebaseId(ctx, "org-admin")
+	if err != nil {
+		t.Fatalf("GetProfilesByFirebaseId() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, profile := range profiles {
+		got[profile.Name] = true
+	}
+	if len(got) != 2 || !got["brand"] || !got["solo"] {
+		t.Errorf("GetProfilesByFirebaseId() = %v, want the organization's profile and the user's own", got)
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend/domain"
+	"backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// exists tells a version conflict apart from a row that is gone, once a conditional write has matched nothing.
+func exists(db *gorm.DB, model interface{}, id uuid.UUID) error {
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return dbErr(err, "gorm count error")
+	}
+	if count == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// updateVersioned writes every column of model only if the row is still at *version, and bumps *version on success.
+// Every update of a versioned model goes through here, so no caller can overwrite a change it has not seen.
+func updateVersioned(db *gorm.DB, model interface{}, id uuid.UUID, version *int64) error {
+	expected := *version
+	*version = expected + 1
+	result := db.Model(model).Where("version = ?", expected).
+		Select("*").Omit("id", "created_at", "deleted_at").
+		Updates(model)
+	if result.Error != nil {
+		*version = expected
+		return dbErr(result.Error, "gorm update error")
+	}
+	if result.RowsAffected == 0 {
+		*version = expected
+		if err := exists(db, model, id); err != nil {
+			return err
+		}
+		return models.ErrVersionConflict
+	}
+	return nil
+}
+
+// deleteVersioned soft-deletes the row, only if it is still at version unless version is 0.
+func deleteVersioned(db *gorm.DB, model interface{}, id uuid.UUID, version int64) error {
+	query := db.Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Delete(model)
+	if result.Error != nil {
+		return dbErr(result.Error, "gorm delete error")
+	}
+	if version != 0 && result.RowsAffected == 0 {
+		if err := exists(db, model, id); err != nil {
+			return err
+		}
+		return models.ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"backend/domain"
+	"backend/models"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestUpdateVersioned(t *testing.T) {
+	ctx, orm := testDB(t)
+	repo := NewUserRepo(orm)
+	id, err := repo.CreateProfile(ctx, models.Profile{Name: "before", ProfileType: models.PerformerType})
+	if err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	first, err := repo.GetProfileByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetProfileByID() error = %v", err)
+	}
+	second := first
+
+	first.Name = "first"
+	updated, err := repo.UpdateProfile(ctx, first)
+	if err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if updated.Version != second.Version+1 {
+		t.Errorf("UpdateProfile() version = %d, want %d", updated.Version, second.Version+1)
+	}
+
+	second.Name = "second"
+	conflicted, err := repo.UpdateProfile(ctx, second)
+	if !errors.Is(err, models.ErrVersionConflict) {
+		t.Fatalf("UpdateProfile() of a stale profile error = %v, want %v", err, models.ErrVersionConflict)
+	}
+	if conflicted.Version != second.Version {
+		t.Errorf("a conflicting update moved the version to %d", conflicted.Version)
+	}
+	stored, err := repo.GetProfileByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetProfileByID() error = %v", err)
+	}
+	if stored.Name != "first" || stored.Version != updated.Version {
+		t.Errorf("stored profile = %q at version %d, want %q at version %d", stored.Name, stored.Version, "first", updated.Version)
+	}
+
+	gone := second
+	gone.ID = uuid.New()
+	if _, err := repo.UpdateProfile(ctx, gone); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateProfile() of a missing profile error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
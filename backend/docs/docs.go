@@ -23,7 +23,7 @@ const docTemplate = `{
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new application",
+                "description": "Create a new application. If the event has an application form, answers are keyed by question ID and\nchecked against its current version, which the application records as form_version.",
                 "consumes": [
                     "application/json"
                 ],
@@ -35,6 +35,12 @@ const docTemplate = `{
                 ],
                 "summary": "Create a new application",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a retry sends the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Application object to be created",
                         "name": "application",
@@ -55,19 +61,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
@@ -103,24 +121,30 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Application"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version, for If-Match"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
@@ -143,6 +167,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -152,19 +182,25 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
@@ -175,9 +211,10 @@ const docTemplate = `{
                         "BearerToken": []
                     }
                 ],
-                "description": "Update an event by ID",
+                "description": "RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps, EventRef,\nPerformer, the form answers, the waitlist position and the offer deadline cannot be changed. The\nperformer may only accept or decline an offer; every other status change is the producer's and fails\nwith status_producer_only. Declining an offer offers the place to the first waitlisted application.\nOffering or accepting fails with booking_conflict when the performer, a member of it or an ensemble it\nbelongs to is already offered or accepted for an event starting within four hours.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
@@ -185,7 +222,7 @@ const docTemplate = `{
                 "tags": [
                     "Applications"
                 ],
-                "summary": "Update an event by ID",
+                "summary": "Update an application by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -195,7 +232,13 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Application object",
+                        "type": "string",
+                        "description": "ETag of the version being patched",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch of the application",
                         "name": "application",
                         "in": "body",
                         "required": true,
@@ -214,133 +257,75 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
-                    }
-                }
-            }
-        },
-        "/event/{id}/applications": {
-            "get": {
-                "security": [
-                    {
-                        "BearerToken": []
-                    }
-                ],
-                "description": "Returns the applications submitted to an event",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Applications"
-                ],
-                "summary": "Get Applications by Event ID",
-                "operationId": "get-applications-by-event-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Application"
-                            }
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "415": {
+                        "description": "Unsupported Media Type",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/events": {
+        "/applications/{id}/contract": {
             "get": {
-                "description": "Returns all events within a specified time range and distance from a center point.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
                 ],
+                "description": "When the application stops being accepted, a contract not yet signed by both parties is voided, and a\nsigned one keeps its status with terminated_at set.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Contracts"
                 ],
-                "summary": "Get all events",
+                "summary": "Get the contract for an application",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start time of the range (RFC3339)",
-                        "name": "start_time",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End time of the range (RFC3339)",
-                        "name": "end_time",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "latitude of search point",
-                        "name": "lat",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "longitude of search point",
-                        "name": "lon",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Distance from the center point in kilometers",
-                        "name": "distance_km",
-                        "in": "query",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -348,22 +333,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Event"
-                            }
+                            "$ref": "#/definitions/models.Contract"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
@@ -374,76 +356,70 @@ const docTemplate = `{
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new event",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Renders the agreement for an accepted application. Unsigned contracts are regenerated in place; a void or\nterminated one is kept and a new one drafted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Contracts"
                 ],
-                "summary": "Create a new event",
+                "summary": "Generate the contract for an application",
                 "parameters": [
                     {
-                        "description": "Event object to be created",
-                        "name": "event",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Event"
-                        }
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/presenter.IdResponse"
+                            "$ref": "#/definitions/models.Contract"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/events/{id}": {
+        "/applications/{id}/messages": {
             "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Returns the event with the specified ID",
+                "description": "Oldest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Messages"
                 ],
-                "summary": "Get an event by ID",
-                "operationId": "get-event-by-id",
+                "summary": "List the messages on an application",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Application ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -453,80 +429,109 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Message"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete an event by ID",
+                "description": "Either side of the application may write; profile_id says which one the message is sent for. Both\nsides are told through the stream as message.created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Events"
+                    "Messages"
                 ],
-                "summary": "Delete an event by ID",
+                "summary": "Send a message on an application",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Application ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/applications/{id}/reviews": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update an event by ID",
+                "description": "Producers review the performer and performers review the producer of an accepted application once the\nevent has taken place. Reviews stay hidden until both sides have submitted or the review window closes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -534,140 +539,201 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Reviews"
                 ],
-                "summary": "Update an event by ID",
+                "summary": "Review the other side of a booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "Application ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Event object",
-                        "name": "event",
+                        "description": "Rating (1-5) and body",
+                        "name": "review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "$ref": "#/definitions/models.Review"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Event"
+                            "$ref": "#/definitions/models.Review"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/performer/{id}/applications": {
-            "get": {
+        "/applications/{id}/score": {
+            "put": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Returns the applications submitted to an event",
+                "description": "Records or replaces the caller's score of the application against the event's rubric. Every criterion\nneeds a score from 0 to 10. Only the event's assigned reviewers may score.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Applications"
+                    "Curation"
                 ],
-                "summary": "Get Applications by Performer ID",
-                "operationId": "get-applications-by-performer-id",
+                "summary": "Score an application",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Performer ID",
+                        "description": "Application ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Scores by criterion name and an optional comment",
+                        "name": "score",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationScore"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Application"
-                            }
+                            "$ref": "#/definitions/models.ApplicationScore"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/producer/{id}/events": {
+        "/audit": {
             "get": {
                 "security": [
                     {
-                        "BearerToken": []
+                        "BasicAuth": []
                     }
                 ],
-                "description": "Returns the events belonging to producer",
+                "description": "Every recorded mutation across all profiles, newest first. Super user only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Audit"
                 ],
-                "summary": "Get Events by Producer ID",
-                "operationId": "get-events-by-producer-id",
+                "summary": "Get the full audit trail",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Producer ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Restrict to one profile",
+                        "name": "profile_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Firebase UID of the actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the changed resource",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. event.delete",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest entry (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest entry, exclusive (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -676,102 +742,88 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Event"
+                                "$ref": "#/definitions/models.AuditEntry"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/profiles": {
-            "post": {
+        "/contracts/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Create a new profile",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profiles"
+                    "Contracts"
                 ],
-                "summary": "Create a new profile",
+                "summary": "Get a contract by ID",
                 "parameters": [
                     {
-                        "description": "Profile object to be created",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Profile"
-                        }
+                        "type": "string",
+                        "description": "Contract ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/presenter.IdResponse"
+                            "$ref": "#/definitions/models.Contract"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/profiles/{id}": {
+        "/contracts/{id}/pdf": {
             "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Returns the profile with the specified ID",
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "Profiles"
+                    "Contracts"
                 ],
-                "summary": "Get a profile by ID",
-                "operationId": "get-profile-by-id",
+                "summary": "Export a contract as PDF",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Contract ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -781,199 +833,291 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Profile"
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/contracts/{id}/sign": {
+            "post": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Delete a profile by ID",
+                "description": "Records the caller's firebase UID and the current time as the signature of the party named, which the\ncaller has to be a member of. The contract becomes immutable once signed and is marked signed when both\nparties have signed.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Profiles"
+                    "Contracts"
                 ],
-                "summary": "Delete a profile by ID",
+                "summary": "Sign a contract",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Contract ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "producer",
+                            "performer"
+                        ],
+                        "type": "string",
+                        "description": "Side the caller signs for",
+                        "name": "party",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Contract"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/event/{id}/applications": {
+            "get": {
                 "security": [
                     {
                         "BearerToken": []
                     }
                 ],
-                "description": "Update a profile by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the applications submitted to an event",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profiles"
+                    "Applications"
                 ],
-                "summary": "Update a profile by ID",
+                "summary": "Get Applications by Event ID",
+                "operationId": "get-applications-by-event-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Profile ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Profile object",
-                        "name": "application",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Profile"
-                        }
+                        "enum": [
+                            "accepted",
+                            "rejected",
+                            "pending",
+                            "offered",
+                            "unknown",
+                            "waitlisted",
+                            "declined",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "score"
+                        ],
+                        "type": "string",
+                        "description": "score ranks by the reviewers' average rubric score and includes it",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Profile"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Application"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
         },
-        "/tag/{name}": {
-            "post": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    }
+        "/events": {
+            "get": {
+                "description": "Returns all events within a specified time range and distance from a center point.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Create a new tag",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tags"
+                    "Events"
+                ],
+                "summary": "Get all events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start time of the range (RFC3339)",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time of the range (RFC3339)",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "latitude of search point",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "longitude of search point",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Distance from the center point in kilometers",
+                        "name": "distance_km",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum guaranteed pay in minor currency units (e.g. cents)",
+                        "name": "min_pay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "USD",
+                        "description": "ISO 4217 currency for min_pay",
+                        "name": "currency",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Create a new tag",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/presenter.IdResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
-                        "BasicAuth": []
+                        "BearerToken": []
                     }
                 ],
-                "description": "Delete a tag",
+                "description": "Create a new event",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tags"
+                    "Events"
                 ],
-                "summary": "Delete a tag",
+                "summary": "Create a new event",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a retry sends the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Event object to be created",
                         "name": "event",
@@ -994,175 +1138,4902 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/presenter.ErrorResponse"
+                            "$ref": "#/definitions/presenter.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "gorm.DeletedAt": {
-            "type": "object",
-            "properties": {
-                "time": {
+        },
+        "/events/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Returns the event with the specified ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get an event by ID",
+                "operationId": "get-event-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete an event by ID",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Delete an event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps, Producer\nand Applications cannot be changed.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Update an event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being patched",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch of the event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/applications/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Streams the applications submitted to an event, with their performers' profiles, as CSV, JSON or XLSX",
+                "produces": [
+                    "text/csv",
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Export Applications by Event ID",
+                "operationId": "export-applications-by-event-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "File format, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "accepted",
+                            "rejected",
+                            "pending",
+                            "offered",
+                            "unknown",
+                            "waitlisted",
+                            "declined",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/presenter.ApplicationExport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/applications/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Moves the listed applications of the event to one status in a single transaction. Every item is checked\nlike a single update and fails on its own; the results say which were updated, already had the status\nor failed and why. message is an optional Go template rendered with .Event, .Performer, .Application\nand .PreviousStatus and sent to each performer whose status changed. Notifications go out in the\nbackground once the change has been committed. offer_expires_at sets the response deadline of offers,\nincluding ones already offered; unanswered offers expire at their deadline and cannot change after.\nItems whose act is already booked around the event's time fail with booking_conflict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Change the status of many applications",
+                "operationId": "change-application-statuses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a retry sends the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status, applications and optional message template",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkStatusChange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkStatusReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/form": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The current version of the form, or the one an application answered when version is given.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get an event's application form",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Form version",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Replaces the questions applicants answer: text, choice, url, file (a link to the file) or number, each\noptionally required. Only a draft event's form can be edited. Once an application has answered the\ncurrent version, an edit starts a new version and earlier applications keep the one they answered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Set an event's application form",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Questions",
+                        "name": "form",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationForm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/payments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Appends a payment to the event's ledger. Corrections are recorded as negative amounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settlement"
+                ],
+                "summary": "Record a payment to a performer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Payment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/revenue": {
+            "put": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Door revenue (minor currency units) and tickets sold, used to settle door splits and ticket tiers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settlement"
+                ],
+                "summary": "Record the actual revenue of an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Revenue",
+                        "name": "revenue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EventRevenue"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventRevenue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/review-queue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Every application of the event with the caller's own score, if any. Only assigned reviewers may ask.\nUnder a blind rubric the performer, the application name and the form answers are left out, and the\nreviewers may not see the event's applications anywhere else.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Curation"
+                ],
+                "summary": "Get the caller's review queue for an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReviewItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/rubric": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Curation"
+                ],
+                "summary": "Get an event's rubric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Rubric"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Defines the criteria reviewers score (0 to 10, weighted), the producer members assigned to review by\nuser ID, and whether reviewers see who the performers are. Criteria are fixed once anything is scored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Curation"
+                ],
+                "summary": "Set an event's rubric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Criteria, reviewer_ids and blind",
+                        "name": "rubric",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Rubric"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Rubric"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/settlement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Expected, paid and outstanding amounts for every accepted application of the event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settlement"
+                ],
+                "summary": "Get the settlement ledger of an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSettlement"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The waitlisted applications, first in line first. When an offer is declined, the first of them is\noffered the place automatically, with the event's offer_response_hours to answer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Get an event's waitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Application"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Waitlists the listed applications in that order, in one transaction. Pending, unknown, rejected and\nwaitlisted applications can be listed; waitlisted applications left out go back to pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Set an event's waitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Application IDs, first in line first",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitlistOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Application"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "List the caller's organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Organization"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The caller becomes its first admin. Profiles are brought in afterwards by someone who administers both.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Create an organization",
+                "parameters": [
+                    {
+                        "description": "Organization to create",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Includes the profiles it owns.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Get an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/admins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "List an organization's admins",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.OrganizationAdmin"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The user becomes an admin of every profile the organization owns.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Add an organization admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Firebase user to add",
+                        "name": "admin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationAdminRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationAdmin"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/admins/{firebase_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "They keep any membership they hold on its profiles directly. The last admin cannot be removed.",
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Remove an organization admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Firebase UID of the admin",
+                        "name": "firebase_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/applications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "List the applications to an organization's events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "accepted",
+                            "rejected",
+                            "pending",
+                            "offered",
+                            "unknown",
+                            "waitlisted",
+                            "declined",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Application"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Events produced by or held at any of its profiles, soonest first, with their producer and venue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "List an organization's events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/profiles": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Only producer and venue profiles can belong to one. The caller has to be an admin of the profile as well\nas of the organization; a profile owned by another organization moves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Bring a profile into an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile to bring in",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/profiles/{profile_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The profile has to have an admin of its own, so it is not left without anyone to run it.",
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Hand a profile back from an organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "profile_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/performer/{id}/applications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Returns the applications submitted to an event. Offers carry the offer_expires_at deadline to answer by.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Get Applications by Performer ID",
+                "operationId": "get-applications-by-performer-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Performer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Application"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/performer/{id}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Expected, paid and outstanding amounts across every event the performer was accepted to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settlement"
+                ],
+                "summary": "Get a performer's outstanding balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Performer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PerformerBalance"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/producer/{id}/contract-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Returns the producer's contract templates, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contracts"
+                ],
+                "summary": "List contract templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ContractTemplate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Create a contract template for a producer. Templates use Go text/template syntax and are rendered\nwith .Event, .Producer, .Performer, .Application, .Pay, .Slot and .Date. The newest template is used.\nA template that does not parse, or names a field the data lacks, is refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contracts"
+                ],
+                "summary": "Create a contract template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Contract template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ContractTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/producer/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Returns the events belonging to producer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Events by Producer ID",
+                "operationId": "get-events-by-producer-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Create a new profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Create a new profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a retry sends the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profile object to be created",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Returns the profile with the specified ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Get a profile by ID",
+                "operationId": "get-profile-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Delete a profile by ID",
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Delete a profile by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "RFC 7396 merge patch: only the fields sent change and null resets a field. id, timestamps and\nreputation cannot be changed.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Update a profile by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being patched",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch of the profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Every recorded mutation touching the profile, its events or their applications, newest first.\nRestricted to the profile's admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get the audit trail of a profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Firebase UID of the actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the changed resource",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. application.update",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest entry (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest entry, exclusive (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/ensemble/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Active members, pending invitations and declined ones, with the member profiles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "List an ensemble's members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ensemble profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EnsembleMember"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The performer becomes a member once their own profile accepts. Inviting an active member again only\nchanges their role. Only an ensemble profile can invite, and only a performer that is not one can be\ninvited.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Invite a performer to an ensemble",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ensemble profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Performer to invite",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EnsembleInvitation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnsembleMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/ensemble/members/{member_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Also withdraws a pending invitation.",
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Remove a member from an ensemble",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ensemble profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member profile ID",
+                        "name": "member_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/ensembles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "The ensembles the performer belongs to or is invited to, with the ensemble profiles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "List a performer's ensembles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Performer profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EnsembleMember"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/ensembles/{ensemble_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Accepting makes the performer part of the ensemble's applications and bookings; declining keeps them\nout until they are invited again. Only a pending invitation can be answered, except that accepting\nagain as an active member changes nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Answer an ensemble's invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Performer profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ensemble profile ID",
+                        "name": "ensemble_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer",
+                        "name": "response",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvitationResponse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnsembleMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "tags": [
+                    "Profiles"
+                ],
+                "summary": "Leave an ensemble",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Performer profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ensemble profile ID",
+                        "name": "ensemble_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/imports/events": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Creates a season of events for the producer from a CSV file with a header row. Columns: name, time\n(both required), description, apply_by_time, location (\"lat,lng\" or an address), venue (a venue's\nname), tags (names separated by semicolons), status and pay (free text such as \"$150\" or \"70% door\").\nAt most 20 distinct addresses are geocoded per import. Either every row is created or none is; a dry\nrun reports row by row what would fail.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Import events from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and report without creating anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/imports.Report"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/imports.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/imports/performers": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Creates the producer's roster of past performers from a CSV file with a header row. Columns: name\n(required), type (performer by default), location (\"lat,lng\" or an address) and portfolio_links\n(separated by spaces or semicolons). The producer's admins, and those of its organization, become admins\nof the imported profiles until an operator hands them over with \"ocall-admin members add\" and\n\"permissions revoke\"; a producer without admins cannot import. At most 20 distinct addresses are geocoded\nper import. Either every row is created or none is.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Import performers from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and report without creating anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/imports.Report"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/imports.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get the published reviews of a profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.ReviewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Soft-deleted events, applications and the profile itself, newest first. Items can be restored until\nexpires_at, after which they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "List a profile's trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TrashItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/trash/{kind}/{item}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Purging an application also purges its messages and scores. Purging an event also purges its\napplications, rubric and forms; purging a profile purges its events, applications and ensemble memberships.",
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Permanently delete an item from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event, application or profile",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the deleted item",
+                        "name": "item",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}/trash/{kind}/{item}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Applications can only be restored once their event and performer are, and events once their producer is.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore an item from the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event, application or profile",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the deleted item",
+                        "name": "item",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrashItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/events": {
+            "get": {
+                "description": "Open and closed events, soonest first, without applications, Google form IDs or offer settings, and\nwithout pay where the producer hides it. No authentication; rate limited per client IP. Responses carry\nan ETag and may be cached for a minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "List published events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Earliest start time (RFC3339); defaults to now",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time to list up to, exclusive (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this producer's events",
+                        "name": "producer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at this venue",
+                        "name": "venue_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "At most this many events, up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PublicEvent"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=60"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/events/{id}": {
+            "get": {
+                "description": "Drafts and cancelled events are not found. No authentication; rate limited per client IP.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Get a published event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PublicEvent"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=60"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/producers/{id}/feed": {
+            "get": {
+                "description": "The producer's upcoming open events whose apply-by deadline has not passed, soonest first, as RSS 2.0,\nAtom, JSON Feed or an HTML list to embed in another site. Items carry the start time, the venue and\nthe apply-by deadline. No authentication; rate limited per client IP and cacheable like the rest.",
+                "produces": [
+                    "application/rss+xml",
+                    "application/atom+xml",
+                    "application/feed+json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Feed of a producer's calls for performers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "rss",
+                            "atom",
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "default": "rss",
+                        "description": "Feed format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/tags/{name}/feed": {
+            "get": {
+                "description": "Like the producer feed, for the upcoming open events of any producer that have the tag.",
+                "produces": [
+                    "application/rss+xml",
+                    "application/atom+xml",
+                    "application/feed+json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Feed of the calls for performers with a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "rss",
+                            "atom",
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "default": "rss",
+                        "description": "Feed format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/venues/{id}/feed": {
+            "get": {
+                "description": "Like the producer feed, for the upcoming open events held at the venue.",
+                "produces": [
+                    "application/rss+xml",
+                    "application/atom+xml",
+                    "application/feed+json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Feed of the calls for performers at a venue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Venue profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "rss",
+                            "atom",
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "default": "rss",
+                        "description": "Feed format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/flagged": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "List flagged reviews",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Review"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/flag": {
+            "post": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Flag a review for moderation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason in note",
+                        "name": "reason",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.ModerationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/moderation": {
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Set a review to visible or removed. Removed reviews are hidden and excluded from reputation scores.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Moderate a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation decision",
+                        "name": "moderation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/presenter.ModerationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerToken": []
+                    }
+                ],
+                "description": "Server-Sent Events stream of application created/status-changed, message and event status-changed\nnotifications for every profile the caller is a member of. Memberships are re-checked every 30 seconds,\nand the stream ends if that fails. Super users receive every event.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Stream"
+                ],
+                "summary": "Subscribe to real-time updates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StreamEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/{name}": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a new tag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create a new tag",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Delete a tag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a retry sends the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event object to be created",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/presenter.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "gormGIS.GeoPoint": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "imports.Report": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/imports.RowResult"
+                    }
+                }
+            }
+        },
+        "imports.RowResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActorType": {
+            "type": "string",
+            "enum": [
+                "firebase",
+                "super_user",
+                "system"
+            ],
+            "x-enum-varnames": [
+                "ActorFirebase",
+                "ActorSuperUser",
+                "ActorSystem"
+            ]
+        },
+        "models.Application": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "object"
+                },
+                "application_status": {
+                    "$ref": "#/definitions/models.ApplicationStatus"
+                },
+                "eventRef": {
+                    "type": "string"
+                },
+                "form_version": {
+                    "description": "FormVersion is the version of the event's form the answers were given to, 0 if the event had none.",
+                    "type": "integer"
+                },
+                "googleResponseID": {
+                    "type": "string"
+                },
+                "members": {
+                    "description": "Members are the people in the performer's act, when the performer is an ensemble.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Profile"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offer_expires_at": {
+                    "description": "OfferExpiresAt is when an offered performer has to answer by, if the producer or the event's response window\nset a deadline. An expired application keeps it.",
+                    "type": "string"
+                },
+                "offer_reminded_at": {
+                    "description": "OfferRemindedAt is when the performer was reminded of the deadline; a new deadline clears it.",
+                    "type": "string"
+                },
+                "performer": {
+                    "$ref": "#/definitions/models.Profile"
+                },
+                "score": {
+                    "description": "Score is only filled in for the event's producer, when the applications are ranked.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScoreSummary"
+                        }
+                    ]
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "waitlist_position": {
+                    "description": "WaitlistPosition orders the event's waitlisted applications, lowest first. It is only set while waitlisted.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ApplicationForm": {
+            "type": "object",
+            "properties": {
+                "event_ref": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Question"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ApplicationScore": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "event_ref": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "scores": {
+                    "$ref": "#/definitions/models.CriterionScores"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ApplicationStatus": {
+            "type": "string",
+            "enum": [
+                "accepted",
+                "rejected",
+                "pending",
+                "offered",
+                "unknown",
+                "waitlisted",
+                "declined",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "StatusAccepted",
+                "StatusRejected",
+                "StatusPending",
+                "StatusOffered",
+                "StatusUnknown",
+                "StatusWaitlisted",
+                "StatusDeclined",
+                "StatusExpired"
+            ]
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_type": {
+                    "$ref": "#/definitions/models.ActorType"
+                },
+                "after": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "before": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkOutcome": {
+            "type": "string",
+            "enum": [
+                "updated",
+                "unchanged",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "BulkUpdated",
+                "BulkUnchanged",
+                "BulkFailed"
+            ]
+        },
+        "models.BulkStatusChange": {
+            "type": "object",
+            "required": [
+                "applications",
+                "status"
+            ],
+            "properties": {
+                "applications": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.BulkStatusItem"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "offer_expires_at": {
+                    "description": "OfferExpiresAt sets the response deadline of offers, instead of the event's window. It also moves the deadline\nof applications that are already offered.",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ApplicationStatus"
+                }
+            }
+        },
+        "models.BulkStatusItem": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkStatusReport": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BulkStatusResult"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkStatusResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "$ref": "#/definitions/models.BulkOutcome"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Contract": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "body_hash": {
+                    "type": "string"
+                },
+                "event_ref": {
+                    "type": "string"
+                },
+                "performer_id": {
+                    "type": "string"
+                },
+                "performer_signed_at": {
+                    "type": "string"
+                },
+                "performer_signer_uid": {
+                    "type": "string"
+                },
+                "producer_id": {
+                    "type": "string"
+                },
+                "producer_signed_at": {
+                    "type": "string"
+                },
+                "producer_signer_uid": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ContractStatus"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "terminated_at": {
+                    "description": "TerminatedAt is when the application of a signed contract stopped being accepted. The contract stays signed, as\nwhat both parties agreed to is not rewritten; one that was not signed by both is voided instead.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContractStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "signed",
+                "void"
+            ],
+            "x-enum-varnames": [
+                "ContractPending",
+                "ContractSigned",
+                "ContractVoid"
+            ]
+        },
+        "models.ContractTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "producer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Criterion": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CriterionScores": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "integer"
+            }
+        },
+        "models.CurrencyTotal": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "expected": {
+                    "type": "integer"
+                },
+                "outstanding": {
+                    "type": "integer"
+                },
+                "paid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EnsembleInvitation": {
+            "type": "object",
+            "required": [
+                "member_id"
+            ],
+            "properties": {
+                "member_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EnsembleMember": {
+            "type": "object",
+            "properties": {
+                "ensemble": {
+                    "$ref": "#/definitions/models.Profile"
+                },
+                "ensemble_id": {
+                    "type": "string"
+                },
+                "member": {
+                    "$ref": "#/definitions/models.Profile"
+                },
+                "member_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MembershipStatus"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "application_status": {
+                    "$ref": "#/definitions/models.EventApplicationStatus"
+                },
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Application"
+                    }
+                },
+                "apply_by_time": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "googleForm": {
+                    "type": "string"
+                },
+                "hide_pay": {
+                    "description": "HidePay leaves the pay out of the public listing; applicants still see it.",
+                    "type": "boolean"
+                },
+                "location": {
+                    "$ref": "#/definitions/gormGIS.GeoPoint"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offer_response_hours": {
+                    "description": "OfferResponseHours is how long a performer has to answer an offer; 0 leaves offers open-ended.",
+                    "type": "integer"
+                },
+                "pay": {
+                    "$ref": "#/definitions/models.PayStructure"
+                },
+                "producer": {
+                    "$ref": "#/definitions/models.Profile"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "time": {
+                    "type": "string"
+                },
+                "venue": {
+                    "$ref": "#/definitions/models.Profile"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EventApplicationStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "open",
+                "closed",
+                "cancelled",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "EventDraft",
+                "EventOpen",
+                "EventClosed",
+                "EventCancelled",
+                "EventUnknown"
+            ]
+        },
+        "models.EventRevenue": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "door_revenue": {
+                    "type": "integer"
+                },
+                "event_ref": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "tickets_sold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EventSettlement": {
+            "type": "object",
+            "properties": {
+                "event_ref": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SettlementLine"
+                    }
+                },
+                "pay": {
+                    "$ref": "#/definitions/models.PayStructure"
+                },
+                "revenue": {
+                    "$ref": "#/definitions/models.EventRevenue"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "accept": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MembershipStatus": {
+            "type": "string",
+            "enum": [
+                "invited",
+                "active",
+                "declined"
+            ],
+            "x-enum-varnames": [
+                "MembershipInvited",
+                "MembershipActive",
+                "MembershipDeclined"
+            ]
+        },
+        "models.Message": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MessageRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "profile_id"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "profile_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationStatus": {
+            "type": "string",
+            "enum": [
+                "visible",
+                "flagged",
+                "removed"
+            ],
+            "x-enum-varnames": [
+                "ModerationVisible",
+                "ModerationFlagged",
+                "ModerationRemoved"
+            ]
+        },
+        "models.Organization": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Profile"
+                    }
+                }
+            }
+        },
+        "models.OrganizationAdmin": {
+            "type": "object",
+            "properties": {
+                "firebase_id": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrganizationAdminRequest": {
+            "type": "object",
+            "required": [
+                "firebase_id"
+            ],
+            "properties": {
+                "firebase_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrganizationProfileRequest": {
+            "type": "object",
+            "required": [
+                "profile_id"
+            ],
+            "properties": {
+                "profile_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayBasis": {
+            "type": "string",
+            "enum": [
+                "per_performer",
+                "per_act"
+            ],
+            "x-enum-varnames": [
+                "PerPerformer",
+                "PerAct"
+            ]
+        },
+        "models.PayStructure": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "$ref": "#/definitions/models.PayBasis"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "door_split_percent": {
+                    "type": "number"
+                },
+                "flat_fee": {
+                    "type": "integer"
+                },
+                "min_amount": {
+                    "description": "MinAmount is the guaranteed minimum, kept in its own column so searches can filter on it.",
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PayTier"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/models.PayType"
+                }
+            }
+        },
+        "models.PayTier": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "min_tickets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PayType": {
+            "type": "string",
+            "enum": [
+                "flat_fee",
+                "door_split",
+                "ticket_tiers",
+                "unpaid",
+                "unspecified"
+            ],
+            "x-enum-varnames": [
+                "PayFlatFee",
+                "PayDoorSplit",
+                "PayTicketTiers",
+                "PayUnpaid",
+                "PayUnspecified"
+            ]
+        },
+        "models.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "application_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "event_ref": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.PaymentMethod"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "performer_id": {
+                    "type": "string"
+                },
+                "recorded_by": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "cash",
+                "bank_transfer",
+                "check",
+                "paypal",
+                "venmo",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "PaymentCash",
+                "PaymentBankTransfer",
+                "PaymentCheck",
+                "PaymentPaypal",
+                "PaymentVenmo",
+                "PaymentOther"
+            ]
+        },
+        "models.PerformerBalance": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SettlementLine"
+                    }
+                },
+                "performer_id": {
+                    "type": "string"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyTotal"
+                    }
+                }
+            }
+        },
+        "models.Profile": {
+            "type": "object",
+            "properties": {
+                "ensemble": {
+                    "description": "Ensemble marks a performer profile that stands for a group act; its members are in EnsembleMember.",
+                    "type": "boolean"
+                },
+                "location": {
+                    "$ref": "#/definitions/gormGIS.GeoPoint"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is the organization that owns the profile, if any; its admins act as admins of the profile.",
+                    "type": "string"
+                },
+                "portfolio_links": {
+                    "description": "PortfolioLinks point reviewers at recordings, videos or a website.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reputation": {
+                    "$ref": "#/definitions/models.Reputation"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ProfileType"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProfileType": {
+            "type": "string",
+            "enum": [
+                "producer",
+                "performer",
+                "venue"
+            ],
+            "x-enum-varnames": [
+                "ProducerType",
+                "PerformerType",
+                "VenueType"
+            ]
+        },
+        "models.PublicEvent": {
+            "type": "object",
+            "properties": {
+                "application_status": {
+                    "$ref": "#/definitions/models.EventApplicationStatus"
+                },
+                "apply_by_time": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/gormGIS.GeoPoint"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pay": {
+                    "$ref": "#/definitions/models.PayStructure"
+                },
+                "producer": {
+                    "$ref": "#/definitions/models.PublicProfile"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "venue": {
+                    "$ref": "#/definitions/models.PublicProfile"
+                }
+            }
+        },
+        "models.PublicProfile": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ProfileType"
+                }
+            }
+        },
+        "models.Question": {
+            "type": "object",
+            "properties": {
+                "help": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "multiple": {
                     "type": "boolean"
+                },
+                "options": {
+                    "description": "Options and Multiple only apply to choice questions.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.QuestionType"
                 }
             }
         },
-        "gormGIS.GeoPoint": {
+        "models.QuestionType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "choice",
+                "url",
+                "file",
+                "number"
+            ],
+            "x-enum-varnames": [
+                "QuestionText",
+                "QuestionChoice",
+                "QuestionURL",
+                "QuestionFile",
+                "QuestionNumber"
+            ]
+        },
+        "models.Reputation": {
             "type": "object",
             "properties": {
-                "lat": {
+                "average": {
                     "type": "number"
                 },
-                "lng": {
-                    "type": "number"
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Application": {
+        "models.Review": {
             "type": "object",
             "properties": {
-                "application_status": {
-                    "$ref": "#/definitions/models.ApplicationStatus"
+                "application_id": {
+                    "type": "string"
                 },
-                "eventRef": {
+                "body": {
                     "type": "string"
                 },
-                "googleResponseID": {
+                "direction": {
+                    "$ref": "#/definitions/models.ReviewDirection"
+                },
+                "event_ref": {
                     "type": "string"
                 },
-                "name": {
+                "moderation": {
+                    "$ref": "#/definitions/models.ModerationStatus"
+                },
+                "moderation_note": {
                     "type": "string"
                 },
-                "performer": {
-                    "$ref": "#/definitions/models.Profile"
+                "published_at": {
+                    "description": "PublishedAt starts at the end of the review window and is pulled forward once the counterpart review arrives.",
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "reviewer_profile_id": {
+                    "type": "string"
+                },
+                "subject_profile_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.ApplicationStatus": {
+        "models.ReviewDirection": {
             "type": "string",
             "enum": [
-                "accepted",
-                "rejected",
-                "pending",
-                "offered",
-                "unknown"
+                "producer_to_performer",
+                "performer_to_producer"
             ],
             "x-enum-varnames": [
-                "StatusAccepted",
-                "StatusRejected",
-                "StatusPending",
-                "StatusOffered",
-                "StatusUnknown"
+                "ProducerReviewsPerformer",
+                "PerformerReviewsProducer"
             ]
         },
-        "models.Event": {
+        "models.ReviewItem": {
             "type": "object",
             "properties": {
-                "application_status": {
-                    "$ref": "#/definitions/models.EventApplicationStatus"
+                "application": {
+                    "$ref": "#/definitions/models.Application"
                 },
-                "applications": {
+                "score": {
+                    "$ref": "#/definitions/models.ApplicationScore"
+                }
+            }
+        },
+        "models.Rubric": {
+            "type": "object",
+            "properties": {
+                "blind": {
+                    "type": "boolean"
+                },
+                "criteria": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Application"
+                        "$ref": "#/definitions/models.Criterion"
                     }
                 },
-                "apply_by_time": {
+                "event_ref": {
                     "type": "string"
                 },
-                "description": {
+                "reviewer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ScoreSummary": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "reviews": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SettlementLine": {
+            "type": "object",
+            "properties": {
+                "application_id": {
                     "type": "string"
                 },
-                "googleForm": {
+                "currency": {
                     "type": "string"
                 },
-                "location": {
-                    "$ref": "#/definitions/gormGIS.GeoPoint"
+                "event_ref": {
+                    "type": "string"
+                },
+                "expected": {
+                    "type": "integer"
+                },
+                "expected_known": {
+                    "type": "boolean"
                 },
                 "name": {
                     "type": "string"
                 },
-                "pay_structure": {
-                    "type": "string"
+                "outstanding": {
+                    "type": "integer"
                 },
-                "producer": {
-                    "$ref": "#/definitions/models.Profile"
+                "paid": {
+                    "type": "integer"
                 },
-                "tags": {
+                "payments": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Tag"
+                        "$ref": "#/definitions/models.Payment"
                     }
                 },
-                "time": {
+                "performer_id": {
                     "type": "string"
-                },
-                "venue": {
-                    "$ref": "#/definitions/models.Profile"
                 }
             }
         },
-        "models.EventApplicationStatus": {
-            "type": "string",
-            "enum": [
-                "draft",
-                "open",
-                "closed",
-                "cancelled",
-                "unknown"
-            ],
-            "x-enum-varnames": [
-                "EventDraft",
-                "EventOpen",
-                "EventClosed",
-                "EventCancelled",
-                "EventUnknown"
-            ]
-        },
-        "models.Profile": {
+        "models.StreamEvent": {
             "type": "object",
             "properties": {
-                "location": {
-                    "$ref": "#/definitions/gormGIS.GeoPoint"
+                "audience": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "name": {
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "time": {
                     "type": "string"
                 },
                 "type": {
-                    "$ref": "#/definitions/models.ProfileType"
+                    "$ref": "#/definitions/models.StreamEventType"
                 }
             }
         },
-        "models.ProfileType": {
+        "models.StreamEventType": {
             "type": "string",
             "enum": [
-                "producer",
-                "performer",
-                "venue"
+                "application.created",
+                "application.status_changed",
+                "message.created",
+                "event.status_changed",
+                "application.promoted",
+                "application.offer_reminder"
             ],
             "x-enum-varnames": [
-                "ProducerType",
-                "PerformerType",
-                "VenueType"
+                "StreamApplicationCreated",
+                "StreamApplicationStatusChanged",
+                "StreamMessageCreated",
+                "StreamEventStatusChanged",
+                "StreamApplicationPromoted",
+                "StreamOfferReminder"
             ]
         },
         "models.Tag": {
@@ -1185,10 +6056,103 @@ const docTemplate = `{
                 }
             }
         },
-        "presenter.ErrorResponse": {
+        "models.TrashItem": {
             "type": "object",
             "properties": {
-                "error": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.TrashKind"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TrashKind": {
+            "type": "string",
+            "enum": [
+                "event",
+                "application",
+                "profile"
+            ],
+            "x-enum-varnames": [
+                "TrashEvent",
+                "TrashApplication",
+                "TrashProfile"
+            ]
+        },
+        "models.WaitlistOrder": {
+            "type": "object",
+            "required": [
+                "application_ids"
+            ],
+            "properties": {
+                "application_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "presenter.ApplicationExport": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "members": {
+                    "description": "Members are the names of an ensemble's active members.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "performer_id": {
+                    "type": "string"
+                },
+                "performer_name": {
+                    "type": "string"
+                },
+                "performer_type": {
+                    "$ref": "#/definitions/models.ProfileType"
+                },
+                "portfolio_links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rating_average": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ApplicationStatus"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
@@ -1201,6 +6165,57 @@ const docTemplate = `{
                     "format": "string"
                 }
             }
+        },
+        "presenter.ModerationRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ModerationStatus"
+                }
+            }
+        },
+        "presenter.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "presenter.ReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "reputation": {
+                    "$ref": "#/definitions/models.Reputation"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Review"
+                    }
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,124 @@
+// Package domain holds the errors repositories and services report to the boundary. Each carries a Kind, which the
+// presenter maps to a status code, and a stable machine-readable Code clients can switch on.
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindBadRequest         Kind = "bad_request"
+	KindValidation         Kind = "validation"
+	KindUnauthorized       Kind = "unauthorized"
+	KindForbidden          Kind = "forbidden"
+	KindNotFound           Kind = "not_found"
+	KindConflict           Kind = "conflict"
+	KindGone               Kind = "gone"
+	KindPreconditionFailed Kind = "precondition_failed"
+	KindUnsupportedMedia   Kind = "unsupported_media_type"
+	KindTooManyRequests    Kind = "too_many_requests"
+	KindInternal           Kind = "internal"
+)
+
+// FieldError is one rejected field of a request, named by its JSON path.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	cause   error
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code string, message string) *Error   { return New(KindBadRequest, code, message) }
+func Unauthorized(code string, message string) *Error { return New(KindUnauthorized, code, message) }
+func Forbidden(code string, message string) *Error    { return New(KindForbidden, code, message) }
+func NotFound(code string, message string) *Error     { return New(KindNotFound, code, message) }
+func Conflict(code string, message string) *Error     { return New(KindConflict, code, message) }
+func Gone(code string, message string) *Error         { return New(KindGone, code, message) }
+
+func Validation(code string, message string, fields ...FieldError) *Error {
+	err := New(KindValidation, code, message)
+	err.Fields = fields
+	return err
+}
+
+func PreconditionFailed(code string, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func TooManyRequests(code string, message string) *Error {
+	return New(KindTooManyRequests, code, message)
+}
+
+var (
+	ErrBadRequest   = BadRequest("bad_request", "the request is malformed")
+	ErrNotFound     = NotFound("not_found", "resource not found")
+	ErrForbidden    = Forbidden("forbidden", "caller may not access this resource")
+	ErrUnauthorized = Unauthorized("unauthorized", "missing or invalid credentials")
+	ErrInvalid      = Validation("invalid_fields", "the request has invalid fields")
+)
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	for i, field := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(field.Field + " " + field.Message)
+	}
+	if e.cause != nil {
+		b.WriteString(": " + e.cause.Error())
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// Is matches any error with the same kind and code, so sentinels still match after Wrap, WithFields or WithMessage.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	out := *e
+	out.cause = err
+	return &out
+}
+
+// WithFields returns a copy of e that names the offending fields.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	out := *e
+	out.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &out
+}
+
+// WithMessage returns a copy of e with a more specific message.
+func (e *Error) WithMessage(message string) *Error {
+	out := *e
+	out.Message = message
+	return &out
+}
+
+// As finds the first domain error in err's chain.
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	ok := errors.As(err, &domainErr)
+	return domainErr, ok
+}
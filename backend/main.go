@@ -2,11 +2,26 @@ package main
 
 import (
 	"backend/boundary/handler"
+	"backend/data/geocoding"
+	"backend/data/migrations"
+	"backend/data/pubsub"
 	"backend/data/repository"
 	"backend/docs"
 	"backend/models"
 	"backend/usecase/agenda"
+	"backend/usecase/audit"
+	"backend/usecase/contracts"
+	"backend/usecase/curation"
+	"backend/usecase/idempotency"
+	"backend/usecase/imports"
+	"backend/usecase/messages"
+	"backend/usecase/organizations"
+	"backend/usecase/reviews"
+	"backend/usecase/settlement"
+	"backend/usecase/stream"
+	"backend/usecase/trash"
 	"backend/usecase/users"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -18,6 +33,8 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"log"
+	"os"
+	"time"
 
 	"backend/boundary/middleware"
 	"unsafe"
@@ -33,6 +50,14 @@ func main() {
 	_ = viper.BindEnv("superUser", "OCALL_SUPERUSER")
 	_ = viper.BindEnv("superPw", "OCALL_SUPERPW")
 	_ = viper.BindEnv("dbUri", "OCALL_DB_URI")
+	_ = viper.BindEnv("pubsub", "OCALL_PUBSUB")
+	_ = viper.BindEnv("trashRetention", "OCALL_TRASH_RETENTION")
+	_ = viper.BindEnv("geocoderUrl", "OCALL_GEOCODER_URL")
+	_ = viper.BindEnv("offerReminder", "OCALL_OFFER_REMINDER")
+	_ = viper.BindEnv("publicRateLimit", "OCALL_PUBLIC_RATE_LIMIT")
+	_ = viper.BindEnv("trustedProxies", "OCALL_TRUSTED_PROXIES")
+	_ = viper.BindEnv("eventUrl", "OCALL_EVENT_URL")
+	_ = viper.BindEnv("publicUrl", "OCALL_PUBLIC_URL")
 	user := viper.GetString("superUser")
 	pw := viper.GetString("superPw")
 	uri := viper.GetString("dbUri")
@@ -47,26 +72,92 @@ func main() {
 		fmt.Print(errors.Wrapf(err, "Unable to connect to db %s", uri).Error())
 		return
 	}
-	err = AutoMigrate(orm)
+	migrator, err := migrations.NewMigrator(orm)
 	if err != nil {
-		fmt.Printf(err.Error())
+		fmt.Print(err.Error())
 		return
 	}
-	if err != nil {
-		fmt.Printf("unable to open with gorm")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), migrator, os.Args[2:]); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
 		return
 	}
+	if err := migrator.Check(context.Background()); err != nil {
+		fmt.Println(errors.Wrap(err, "refusing to serve, run `backend migrate up` or fix the schema by hand").Error())
+		os.Exit(1)
+	}
 	uRepo := repository.NewUserRepo(orm)
+	auRepo := repository.NewAuditRepo(orm)
 	aRepo := repository.NewAgendaRepo(orm)
-	uService := users.NewService(&uRepo)
-	aService := agenda.NewService(&aRepo)
+	cRepo := repository.NewContractRepo(orm)
+	stRepo := repository.NewSettlementRepo(orm)
+	rRepo := repository.NewReviewRepo(orm)
+	cuRepo := repository.NewCurationRepo(orm)
+	trRepo := repository.NewTrashRepo(orm)
+	iRepo := repository.NewIdempotencyRepo(orm)
+	oRepo := repository.NewOrganizationRepo(orm)
+	mRepo := repository.NewMessageRepo(orm)
+	var broker stream.Broker
+	if viper.GetString("pubsub") == "postgres" {
+		if broker, err = pubsub.NewPostgresBroker(orm, uri); err != nil {
+			fmt.Print(errors.Wrap(err, "unable to start postgres pubsub").Error())
+			return
+		}
+	} else {
+		broker = pubsub.NewMemoryBroker()
+	}
+	auService := audit.NewService(&auRepo)
+	uService := users.NewService(&uRepo, &auService)
+	sService := stream.NewService(broker, &uRepo)
+	cService := contracts.NewService(&cRepo, &aRepo, &uRepo)
+	mService := messages.NewService(&mRepo, &aRepo, &uRepo, &sService, &auService)
+	aService := agenda.NewService(&aRepo, &sService, &auService, &mService, &cService)
+	go aService.RunOfferDeadlines(context.Background(), time.Minute, viper.GetDuration("offerReminder"))
+	stService := settlement.NewService(&stRepo, &aRepo)
+	rService := reviews.NewService(&rRepo, &aRepo, &uRepo)
+	cuService := curation.NewService(&cuRepo, &aRepo, &uRepo, &auService)
+	trService := trash.NewService(&trRepo, &auService, viper.GetDuration("trashRetention"))
+	go trService.RunRetention(context.Background(), time.Hour)
+	iService := idempotency.NewService(&iRepo, models.IdempotencyTTL, models.IdempotencyLease)
+	transactor := repository.NewTransactor(orm)
+	var geocoder imports.Geocoder
+	if geocoderURL := viper.GetString("geocoderUrl"); geocoderURL != "" {
+		geocoder = geocoding.NewNominatim(geocoderURL, "ocall-import")
+	}
+	imService := imports.NewService(&aRepo, &uRepo, &transactor, geocoder, &auService)
+	go iService.RunCleanup(context.Background(), time.Hour)
+	oService := organizations.NewService(&oRepo, &uRepo, &auService)
 
-	permissionMiddleWare := middleware.NewPermissionsMiddleware(uService, aService)
+	permissionMiddleWare := middleware.NewPermissionsMiddleware(uService, aService, oService, cuService)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(iService)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(viper.GetInt("publicRateLimit"))
 	router := gin.Default()
+	// Rate limits go by client IP, so X-Forwarded-For is only believed from the proxies listed here; feeds believe
+	// X-Forwarded-Proto from the same ones.
+	if err := router.SetTrustedProxies(viper.GetStringSlice("trustedProxies")); err != nil {
+		fmt.Print(errors.Wrap(err, "invalid OCALL_TRUSTED_PROXIES").Error())
+		return
+	}
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	v1 := router.Group("/api/v1")
-	handler.RegisterUserController(uService, v1, firebaseMiddleware, permissionMiddleWare)
-	handler.RegisterAgendaHanlder(aService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterUserController(uService, v1, firebaseMiddleware, permissionMiddleWare, idempotencyMiddleware)
+	handler.RegisterAgendaHanlder(aService, v1, firebaseMiddleware, permissionMiddleWare, idempotencyMiddleware)
+	handler.RegisterStreamController(sService, v1, firebaseMiddleware)
+	handler.RegisterMessageController(mService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterContractController(cService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterSettlementController(stService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterReviewController(rService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterCurationController(cuService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterAuditController(auService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterTrashController(trService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterImportController(imService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterOrganizationController(oService, v1, firebaseMiddleware, permissionMiddleWare)
+	handler.RegisterPublicController(
+		aService, v1, rateLimitMiddleware,
+		viper.GetString("publicUrl"), viper.GetString("eventUrl"), viper.GetStringSlice("trustedProxies"),
+	)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	if _err := router.Run(); _err != nil {
@@ -83,24 +174,3 @@ func stringToBytes(s string) []byte {
 		}{s, len(s)},
 	))
 }
-
-func AutoMigrate(db *gorm.DB) error {
-	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
-
-	enums := []func(db *gorm.DB) error{
-		models.AutoMigratePermission,
-		models.AutoMigrateProfileType,
-		models.AutoMigrateApplicationStatus,
-		models.AutoMigrateEventApplicationStatus,
-	}
-	for _, f := range enums {
-		if err := f(db); err != nil {
-			return err
-		}
-	}
-	err := db.AutoMigrate(&models.Profile{}, &models.UserID{}, &models.Tag{}, &models.Event{}, &models.Application{})
-	if err != nil {
-		return err
-	}
-	return nil
-}
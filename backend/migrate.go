@@ -0,0 +1,66 @@
+package main
+
+import (
+	"backend/data/migrations"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: backend migrate up | down [steps] | status"
+
+// runMigrate is the migrate subcommand. down reverts one migration unless told how many.
+func runMigrate(ctx context.Context, migrator migrations.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("nothing to apply")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.New(migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\t")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.Modified {
+				applied += " (modified)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t\n", status.Version, status.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/google/uuid"
 	"github.com/nferruzzi/gormGIS"
 	"gorm.io/gorm"
@@ -25,38 +24,33 @@ const (
 	StatusPending  ApplicationStatus = "pending"
 	StatusOffered  ApplicationStatus = "offered"
 	StatusUnknown  ApplicationStatus = "unknown"
+	// StatusWaitlisted applications are next in line, by WaitlistPosition, when an offer is declined.
+	StatusWaitlisted ApplicationStatus = "waitlisted"
+	// StatusDeclined is the performer turning down an offer.
+	StatusDeclined ApplicationStatus = "declined"
+	// StatusExpired is an offer left unanswered past its OfferExpiresAt. It is final.
+	StatusExpired ApplicationStatus = "expired"
 )
 
 func (ApplicationStatus) GormDataType() string   { return "application_status" }
 func (ApplicationStatus) GormDBDataType() string { return "application_status" }
 func (a ApplicationStatus) String() string       { return string(a) }
-func AutoMigrateApplicationStatus(db *gorm.DB) error {
-	if err := AutoMigrateEnumType(
-		"application_status", db, StatusPending, StatusOffered, StatusUnknown, StatusRejected, StatusAccepted,
-	); err != nil {
-		return err
-	}
 
-	return nil
-}
-func (a *ApplicationStatus) UnmarshallJSON(data []byte) error {
+// UnmarshalJSON rejects unknown statuses. An empty string leaves the status unset.
+func (a *ApplicationStatus) UnmarshalJSON(data []byte) error {
 	var s string
-	if err := json.Unmarshal(data, &a); err != nil {
+	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
-	switch s {
-	case "accepted":
-		*a = StatusAccepted
-	case "rejected":
-		*a = StatusRejected
-	case "pending":
-		*a = StatusPending
-	case "offered":
-		*a = StatusOffered
-	case "unknown":
-		*a = StatusUnknown
+	switch status := ApplicationStatus(s); status {
+	case StatusAccepted, StatusRejected, StatusPending, StatusOffered, StatusUnknown, StatusWaitlisted, StatusDeclined,
+		StatusExpired, "":
+		*a = status
 	default:
-		return fmt.Errorf("invalid application status type %s. Allowed: accepted, rejected, pending, offered, unknown", s)
+		return invalidEnum(
+			"application_status", s, StatusAccepted, StatusRejected, StatusPending, StatusOffered, StatusUnknown,
+			StatusWaitlisted, StatusDeclined, StatusExpired,
+		)
 	}
 	return nil
 }
@@ -74,32 +68,18 @@ const (
 func (EventApplicationStatus) GormDataType() string   { return "event_application_status" }
 func (EventApplicationStatus) GormDBDataType() string { return "event_application_status" }
 func (e EventApplicationStatus) String() string       { return string(e) }
-func AutoMigrateEventApplicationStatus(db *gorm.DB) error {
-	if err := AutoMigrateEnumType(
-		"event_application_status", db, EventOpen, EventUnknown, EventClosed, EventCancelled,
-	); err != nil {
-		return err
-	}
-	return nil
-}
-func (e *EventApplicationStatus) UnmarshallJson(data []byte) error {
+
+// UnmarshalJSON rejects unknown statuses. An empty string leaves the status unset.
+func (e *EventApplicationStatus) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
-	switch s {
-	case "draft":
-		*e = EventDraft
-	case "open":
-		*e = EventOpen
-	case "closed":
-		*e = EventClosed
-	case "cancelled":
-		*e = EventCancelled
-	case "unknown":
-		*e = EventUnknown
+	switch status := EventApplicationStatus(s); status {
+	case EventDraft, EventOpen, EventClosed, EventCancelled, EventUnknown, "":
+		*e = status
 	default:
-		return fmt.Errorf("invalid event application status %s. Allowed: draft, open, closed, cancelled, unknown", s)
+		return invalidEnum("application_status", s, EventDraft, EventOpen, EventClosed, EventCancelled, EventUnknown)
 	}
 	return nil
 }
@@ -110,8 +90,23 @@ type Application struct {
 	Status           ApplicationStatus `gorm:"type:application_status;default:'unknown'" json:"application_status,default='unknown'"`
 	Performer        Profile           `gorm:"foreignKey:PerformerID"`
 	PerformerID      uuid.UUID         `json:"-" gorm:"performer_id,type:uuid"`
-	EventRef         uuid.UUID         `gorm:"event_ref;type:uuid"`
+	EventRef         uuid.UUID         `gorm:"event_ref"`
 	GoogleResponseID GoogleResponseID
+	// WaitlistPosition orders the event's waitlisted applications, lowest first. It is only set while waitlisted.
+	WaitlistPosition *int `json:"waitlist_position,omitempty"`
+	// OfferExpiresAt is when an offered performer has to answer by, if the producer or the event's response window
+	// set a deadline. An expired application keeps it.
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	// OfferRemindedAt is when the performer was reminded of the deadline; a new deadline clears it.
+	OfferRemindedAt *time.Time `json:"offer_reminded_at,omitempty"`
+	// FormVersion is the version of the event's form the answers were given to, 0 if the event had none.
+	FormVersion int     `json:"form_version" gorm:"not null;default:0"`
+	Answers     Answers `json:"answers,omitempty" gorm:"type:jsonb;not null;default:'{}'" swaggertype:"object"`
+	Version     int64   `json:"version" gorm:"not null;default:1"`
+	// Members are the people in the performer's act, when the performer is an ensemble.
+	Members []Profile `json:"members,omitempty" gorm:"-"`
+	// Score is only filled in for the event's producer, when the applications are ranked.
+	Score *ScoreSummary `json:"score,omitempty" gorm:"-"`
 }
 
 type Event struct {
@@ -120,14 +115,83 @@ type Event struct {
 	Description  string
 	Tags         []Tag         `gorm:"many2many:event_tags;"`
 	Producer     Profile       `gorm:"foreignKey:ProducerID"`
-	ProducerID   uuid.UUID     `json:"-" gorm:"producer_id;type:uuid"`
+	ProducerID   uuid.UUID     `json:"-" gorm:"producer_id"`
 	Venue        *Profile      `gorm:"foreignKey:VenueID"`
-	VenueID      *uuid.UUID    `json:"-" gorm:"venue_id;type:uuid"`
+	VenueID      *uuid.UUID    `json:"-" gorm:"venue_id"`
 	Applications []Application `gorm:"foreignKey:EventRef"`
 	GoogleForm   GoogleFormID
 	Location     gormGIS.GeoPoint
 	Status       EventApplicationStatus `json:"application_status,default='unknown'" gorm:"type:event_application_status;default:unknown"`
 	Time         time.Time
-	ApplyByTime  *time.Time `json:"apply_by_time,omitempty"`
-	PayStructure string     `json:"pay_structure,omitempty"`
+	ApplyByTime  *time.Time   `json:"apply_by_time,omitempty"`
+	Pay          PayStructure `json:"pay" gorm:"embedded;embeddedPrefix:pay_"`
+	// HidePay leaves the pay out of the public listing; applicants still see it.
+	HidePay bool `json:"hide_pay" gorm:"not null;default:false"`
+	// OfferResponseHours is how long a performer has to answer an offer; 0 leaves offers open-ended.
+	OfferResponseHours int   `json:"offer_response_hours" gorm:"not null;default:0"`
+	Version            int64 `json:"version" gorm:"not null;default:1"`
+}
+
+// OfferDeadline is when an offer made at from has to be answered by, or nil if the event sets no response window.
+func (e Event) OfferDeadline(from time.Time) *time.Time {
+	if e.OfferResponseHours <= 0 {
+		return nil
+	}
+	deadline := from.Add(time.Duration(e.OfferResponseHours) * time.Hour)
+	return &deadline
+}
+
+// WaitlistOrder replaces an event's waitlist with the listed applications, first in line first.
+type WaitlistOrder struct {
+	ApplicationIDs []uuid.UUID `json:"application_ids" binding:"required"`
+}
+
+// BulkStatusChange moves many applications of one event to Status at once. Message, if set, is a text/template
+// rendered with StatusMessageData and sent to every performer whose application actually changed.
+type BulkStatusChange struct {
+	Status       ApplicationStatus `json:"status" binding:"required"`
+	Applications []BulkStatusItem  `json:"applications" binding:"required,min=1,dive"`
+	Message      string            `json:"message,omitempty"`
+	// OfferExpiresAt sets the response deadline of offers, instead of the event's window. It also moves the deadline
+	// of applications that are already offered.
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+}
+
+// BulkStatusItem names one application; a non-zero Version must match the stored one, as with If-Match.
+type BulkStatusItem struct {
+	ID      uuid.UUID `json:"id" binding:"required"`
+	Version int64     `json:"version,omitempty"`
+}
+
+type BulkOutcome string
+
+const (
+	BulkUpdated   BulkOutcome = "updated"
+	BulkUnchanged BulkOutcome = "unchanged"
+	BulkFailed    BulkOutcome = "failed"
+)
+
+// BulkStatusResult reports one item. Version is the application's version after the change; Code and Error say why
+// a failed item was left alone.
+type BulkStatusResult struct {
+	ID      uuid.UUID   `json:"id"`
+	Outcome BulkOutcome `json:"outcome"`
+	Version int64       `json:"version,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type BulkStatusReport struct {
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	Results   []BulkStatusResult `json:"results"`
+}
+
+// StatusMessageData is what bulk status messages are rendered with.
+type StatusMessageData struct {
+	Event          TemplateEvent
+	Performer      TemplateProfile
+	Application    TemplateApplication
+	PreviousStatus ApplicationStatus
 }
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const ActorContextKey string = "actor_context_key"
+
+type ActorType string
+
+const (
+	ActorFirebase  ActorType = "firebase"
+	ActorSuperUser ActorType = "super_user"
+	ActorSystem    ActorType = "system"
+)
+
+func (ActorType) GormDataType() string   { return "actor_type" }
+func (ActorType) GormDBDataType() string { return "actor_type" }
+func (a ActorType) String() string       { return string(a) }
+
+// Actor is whoever caused a change: a firebase UID, the super user, or the system itself.
+type Actor struct {
+	Type ActorType `json:"type"`
+	ID   string    `json:"id,omitempty"`
+}
+
+type actorKey struct{}
+
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext finds the actor set by ContextWithActor or, for gin requests, by the auth middleware.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	if actor, ok := ctx.Value(ActorContextKey).(Actor); ok {
+		return actor
+	}
+	return Actor{Type: ActorSystem}
+}
+
+// UUIDs maps to a postgres uuid[] column.
+type UUIDs []uuid.UUID
+
+func (u UUIDs) Value() (driver.Value, error) {
+	parts := make([]string, len(u))
+	for i, id := range u {
+		parts[i] = id.String()
+	}
+	return "{" + strings.Join(parts, ",") + "}", nil
+}
+
+func (u *UUIDs) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*u = nil
+		return nil
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unable to scan %T into UUIDs", value)
+	}
+	raw = strings.Trim(raw, "{}")
+	if raw == "" {
+		*u = UUIDs{}
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make(UUIDs, len(parts))
+	for i, part := range parts {
+		id, err := uuid.Parse(part)
+		if err != nil {
+			return err
+		}
+		out[i] = id
+	}
+	*u = out
+	return nil
+}
+
+func (UUIDs) GormDataType() string { return "uuid[]" }
+
+// AuditEntry is an append-only record of one mutation. ProfileIDs are the profiles whose admins may read it.
+type AuditEntry struct {
+	ID           uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid"`
+	CreatedAt    time.Time       `json:"created_at" gorm:"index"`
+	ActorType    ActorType       `json:"actor_type" gorm:"type:actor_type"`
+	ActorID      string          `json:"actor_id,omitempty" gorm:"index"`
+	ProfileIDs   UUIDs           `json:"profile_ids" gorm:"type:uuid[]"`
+	Action       string          `json:"action" gorm:"index"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id" gorm:"index"`
+	Before       json.RawMessage `json:"before,omitempty" gorm:"type:jsonb"`
+	After        json.RawMessage `json:"after,omitempty" gorm:"type:jsonb"`
+	Diff         json.RawMessage `json:"diff,omitempty" gorm:"type:jsonb"`
+}
+
+func (a *AuditEntry) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// AuditFilter narrows an audit query. Zero values are ignored.
+type AuditFilter struct {
+	ProfileID  *uuid.UUID
+	ActorID    string
+	ResourceID string
+	Action     string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	Offset     int
+}
+
+type fieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// NewAuditEntry snapshots before and after as JSON and records the top-level fields that differ.
+// Either side may be nil for creates and deletes.
+func NewAuditEntry(
+	actor Actor, action string, resourceType string, resourceID string, before interface{}, after interface{}, profileIDs ...uuid.UUID,
+) (AuditEntry, error) {
+	entry := AuditEntry{
+		ActorType:    actor.Type,
+		ActorID:      actor.ID,
+		ProfileIDs:   dedupe(profileIDs),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	}
+	beforeFields, beforeJSON, err := snapshot(before)
+	if err != nil {
+		return entry, err
+	}
+	afterFields, afterJSON, err := snapshot(after)
+	if err != nil {
+		return entry, err
+	}
+	entry.Before, entry.After = beforeJSON, afterJSON
+
+	diff := map[string]fieldChange{}
+	for key, value := range afterFields {
+		if old, ok := beforeFields[key]; !ok || !reflect.DeepEqual(old, value) {
+			diff[key] = fieldChange{Before: beforeFields[key], After: value}
+		}
+	}
+	for key, old := range beforeFields {
+		if _, ok := afterFields[key]; !ok {
+			diff[key] = fieldChange{Before: old}
+		}
+	}
+	// timestamps move on every write and would drown out the real change
+	delete(diff, "updated_at")
+	if entry.Diff, err = json.Marshal(diff); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+func snapshot(value interface{}) (map[string]interface{}, json.RawMessage, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return map[string]interface{}{}, nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// not an object; diff it as a single value
+		var scalar interface{}
+		_ = json.Unmarshal(data, &scalar)
+		fields = map[string]interface{}{"value": scalar}
+	}
+	return fields, data, nil
+}
+
+func dedupe(ids []uuid.UUID) UUIDs {
+	seen := map[uuid.UUID]struct{}{}
+	out := UUIDs{}
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == uuid.Nil {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
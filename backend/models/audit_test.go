@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	profile := uuid.New()
+	before := map[string]interface{}{"name": "old", "status": "open", "updated_at": "t1", "gone": true}
+	after := map[string]interface{}{"name": "new", "status": "open", "updated_at": "t2", "added": 1}
+	entry, err := NewAuditEntry(Actor{Type: ActorFirebase, ID: "uid"}, "event.update", "event", "1", before, after,
+		profile, uuid.Nil, profile)
+	if err != nil {
+		t.Fatalf("NewAuditEntry() error = %v", err)
+	}
+	if len(entry.ProfileIDs) != 1 || entry.ProfileIDs[0] != profile {
+		t.Errorf("profile IDs = %v, want just %s", entry.ProfileIDs, profile)
+	}
+	var diff map[string]fieldChange
+	if err := json.Unmarshal(entry.Diff, &diff); err != nil {
+		t.Fatalf("unable to decode the diff: %v", err)
+	}
+	for _, key := range []string{"name", "gone", "added"} {
+		if _, ok := diff[key]; !ok {
+			t.Errorf("diff is missing %q", key)
+		}
+	}
+	for _, key := range []string{"status", "updated_at"} {
+		if _, ok := diff[key]; ok {
+			t.Errorf("diff lists unchanged or noisy field %q", key)
+		}
+	}
+
+	created, err := NewAuditEntry(Actor{Type: ActorSystem}, "event.create", "event", "1", (*Event)(nil), "scalar")
+	if err != nil {
+		t.Fatalf("NewAuditEntry() of a create error = %v", err)
+	}
+	if created.Before != nil || string(created.After) != `"scalar"` {
+		t.Errorf("create snapshots = %s, %s, want no before", created.Before, created.After)
+	}
+}
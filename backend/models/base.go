@@ -2,10 +2,8 @@ package models
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"strings"
 	"time"
 )
 
@@ -13,23 +11,34 @@ type enumType interface {
 	String() string
 }
 
-func AutoMigrateEnumType(name string, db *gorm.DB, elements ...enumType) error {
-	enums := make([]string, len(elements))
-	for j, element := range elements {
-		enums[j] = element.String()
-	}
-	if result := db.Exec(fmt.Sprintf("SELECT 1 FROM pg_type WHERE typname = '%s'", name)); result.RowsAffected == 0 {
-		if err := db.Exec(fmt.Sprintf(
-			"CREATE TYPE %s AS ENUM ('%s')",
-			name,
-			strings.Join(enums, "', '"),
-		)).Error; err != nil {
-			return err
-		}
-	} else if result.Error != nil {
-		return result.Error
+// EnumTypes maps every postgres enum type to the values the code writes. The migrations create and extend these
+// types; the startup check refuses to serve when pg_enum disagrees.
+var EnumTypes = map[string][]string{
+	"permission":   enumValues(Admin, Restricted, PermissionUnknown),
+	"profile_type": enumValues(ProducerType, PerformerType, VenueType),
+	"application_status": enumValues(
+		StatusPending, StatusOffered, StatusUnknown, StatusRejected, StatusAccepted, StatusWaitlisted, StatusDeclined,
+		StatusExpired,
+	),
+	"event_application_status": enumValues(EventDraft, EventOpen, EventUnknown, EventClosed, EventCancelled),
+	"pay_type":                 enumValues(PayFlatFee, PayDoorSplit, PayTicketTiers, PayUnpaid, PayUnspecified),
+	"pay_basis":                enumValues(PerPerformer, PerAct),
+	"contract_status":          enumValues(ContractPending, ContractSigned, ContractVoid),
+	"payment_method": enumValues(
+		PaymentCash, PaymentBankTransfer, PaymentCheck, PaymentPaypal, PaymentVenmo, PaymentOther,
+	),
+	"review_direction":  enumValues(ProducerReviewsPerformer, PerformerReviewsProducer),
+	"moderation_status": enumValues(ModerationVisible, ModerationFlagged, ModerationRemoved),
+	"actor_type":        enumValues(ActorFirebase, ActorSuperUser, ActorSystem),
+	"membership_status": enumValues(MembershipInvited, MembershipActive, MembershipDeclined),
+}
+
+func enumValues(elements ...enumType) []string {
+	values := make([]string, len(elements))
+	for i, element := range elements {
+		values[i] = element.String()
 	}
-	return nil
+	return values
 }
 
 type Permission string
@@ -44,14 +53,8 @@ func (p Permission) String() string { return string(p) }
 
 func (Permission) GormDataType() string   { return "permission" }
 func (Permission) GormDBDataType() string { return "permission" }
-func AutoMigratePermission(db *gorm.DB) error {
-	if err := AutoMigrateEnumType("permission", db, Admin, Restricted, PermissionUnknown); err != nil {
-		return err
-	}
-	return nil
-}
 
-func (p *Permission) UnmarshallJSON(data []byte) error {
+func (p *Permission) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
 		return err
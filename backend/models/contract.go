@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"time"
+)
+
+type ContractStatus string
+
+const (
+	ContractPending ContractStatus = "pending"
+	ContractSigned  ContractStatus = "signed"
+	ContractVoid    ContractStatus = "void"
+)
+
+func (ContractStatus) GormDataType() string   { return "contract_status" }
+func (ContractStatus) GormDBDataType() string { return "contract_status" }
+func (c ContractStatus) String() string       { return string(c) }
+
+// ContractTemplate is a text/template rendered with ContractData. The most recent template of a producer is used.
+type ContractTemplate struct {
+	Model
+	ProducerID uuid.UUID `json:"producer_id" gorm:"index"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+}
+
+type Contract struct {
+	Model
+	ApplicationID      uuid.UUID      `json:"application_id" gorm:"index"`
+	EventRef           uuid.UUID      `json:"event_ref"`
+	ProducerID         uuid.UUID      `json:"producer_id"`
+	PerformerID        uuid.UUID      `json:"performer_id"`
+	TemplateID         *uuid.UUID     `json:"template_id,omitempty"`
+	Status             ContractStatus `json:"status" gorm:"type:contract_status;default:pending"`
+	Body               string         `json:"body"`
+	BodyHash           string         `json:"body_hash"`
+	ProducerSignerUID  *string        `json:"producer_signer_uid,omitempty"`
+	ProducerSignedAt   *time.Time     `json:"producer_signed_at,omitempty"`
+	PerformerSignerUID *string        `json:"performer_signer_uid,omitempty"`
+	PerformerSignedAt  *time.Time     `json:"performer_signed_at,omitempty"`
+	// TerminatedAt is when the application of a signed contract stopped being accepted. The contract stays signed, as
+	// what both parties agreed to is not rewritten; one that was not signed by both is voided instead.
+	TerminatedAt *time.Time `json:"terminated_at,omitempty"`
+}
+
+func (c Contract) Signed() bool {
+	return c.ProducerSignedAt != nil || c.PerformerSignedAt != nil
+}
+
+// ContractData is what templates are rendered with. Slot is the performance time.
+type ContractData struct {
+	Event       TemplateEvent
+	Producer    TemplateProfile
+	Performer   TemplateProfile
+	Application TemplateApplication
+	Pay         string
+	Slot        time.Time
+	Date        time.Time
+}
+
+// TemplateProfile is what templates written by producers see of a profile: nothing about the users behind it.
+type TemplateProfile struct {
+	Name     string
+	Type     ProfileType
+	Location *gormGIS.GeoPoint
+}
+
+func NewTemplateProfile(profile Profile) TemplateProfile {
+	return TemplateProfile{Name: profile.Name, Type: profile.ProfileType, Location: profile.Location}
+}
+
+type TemplateEvent struct {
+	Name        string
+	Description string
+	Time        time.Time
+	ApplyByTime *time.Time
+	Location    gormGIS.GeoPoint
+	Venue       *TemplateProfile
+}
+
+func NewTemplateEvent(event Event) TemplateEvent {
+	out := TemplateEvent{
+		Name: event.Name, Description: event.Description, Time: event.Time, ApplyByTime: event.ApplyByTime,
+		Location: event.Location,
+	}
+	if event.Venue != nil {
+		venue := NewTemplateProfile(*event.Venue)
+		out.Venue = &venue
+	}
+	return out
+}
+
+type TemplateApplication struct {
+	ID     uuid.UUID
+	Name   string
+	Status ApplicationStatus
+}
+
+func NewTemplateApplication(application Application) TemplateApplication {
+	return TemplateApplication{ID: application.ID, Name: application.Name, Status: application.Status}
+}
+
+type ContractParty string
+
+const (
+	ProducerParty  ContractParty = "producer"
+	PerformerParty ContractParty = "performer"
+)
+
+func ParseContractParty(s string) (ContractParty, error) {
+	switch party := ContractParty(s); party {
+	case ProducerParty, PerformerParty:
+		return party, nil
+	default:
+		return "", fmt.Errorf("invalid party %q. Allowed: producer, performer", s)
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// ScoreScale is the top of the scale every rubric criterion is scored on, from 0.
+const ScoreScale = 10
+
+// Criterion is one line of a rubric. Weights are relative; they need not add up to anything.
+type Criterion struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Weight      float64 `json:"weight"`
+}
+
+type Criteria []Criterion
+
+func (c Criteria) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *Criteria) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("unable to scan %T into Criteria", value)
+	}
+}
+
+func (Criteria) GormDataType() string { return "jsonb" }
+
+// Rubric is how an event's applications are curated: the criteria reviewers score, the producer members assigned to
+// review, and whether reviewers see who the performers are.
+type Rubric struct {
+	Model
+	EventRef    uuid.UUID `json:"event_ref" gorm:"uniqueIndex"`
+	Criteria    Criteria  `json:"criteria" gorm:"type:jsonb;not null;default:'[]'"`
+	ReviewerIDs UUIDs     `json:"reviewer_ids" gorm:"type:uuid[];not null;default:'{}'"`
+	Blind       bool      `json:"blind" gorm:"not null;default:false"`
+}
+
+// Weighted is the rubric's weighted mean of the scores, on the same 0 to ScoreScale scale.
+func (r Rubric) Weighted(scores CriterionScores) float64 {
+	var total, weights float64
+	for _, criterion := range r.Criteria {
+		total += criterion.Weight * float64(scores[criterion.Name])
+		weights += criterion.Weight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return total / weights
+}
+
+// CriterionScores maps a criterion name to its score.
+type CriterionScores map[string]int
+
+func (s CriterionScores) Value() (driver.Value, error) {
+	if s == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+func (s *CriterionScores) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return fmt.Errorf("unable to scan %T into CriterionScores", value)
+	}
+}
+
+func (CriterionScores) GormDataType() string { return "jsonb" }
+
+// ApplicationScore is one reviewer's scoring of an application. ReviewerID is the reviewer's UserID; Total is the
+// weighted score under the rubric at the time, kept so rankings are a plain average.
+type ApplicationScore struct {
+	Model
+	EventRef      uuid.UUID       `json:"event_ref" gorm:"index"`
+	ApplicationID uuid.UUID       `json:"application_id" gorm:"uniqueIndex:idx_score_application_reviewer"`
+	ReviewerID    uuid.UUID       `json:"reviewer_id" gorm:"uniqueIndex:idx_score_application_reviewer"`
+	Scores        CriterionScores `json:"scores" gorm:"type:jsonb;not null;default:'{}'"`
+	Comment       string          `json:"comment,omitempty"`
+	Total         float64         `json:"total"`
+}
+
+// ScoreSummary aggregates the reviewers' totals for an application. It is only ever shown to the event's producer.
+type ScoreSummary struct {
+	Average float64 `json:"average"`
+	Reviews int     `json:"reviews"`
+}
+
+// ReviewItem is an application as a reviewer sees it in the queue: without anything naming the performer under a blind
+// rubric, and with the reviewer's own score once given.
+type ReviewItem struct {
+	Application Application       `json:"application"`
+	Score       *ApplicationScore `json:"score,omitempty"`
+}
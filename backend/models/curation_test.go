@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestRubricWeighted(t *testing.T) {
+	rubric := Rubric{Criteria: Criteria{{Name: "craft", Weight: 3}, {Name: "fit", Weight: 1}}}
+	tests := []struct {
+		name   string
+		rubric Rubric
+		scores CriterionScores
+		want   float64
+	}{
+		{name: "no criteria", rubric: Rubric{}, scores: CriterionScores{"craft": 10}, want: 0},
+		{name: "all top scores", rubric: rubric, scores: CriterionScores{"craft": 10, "fit": 10}, want: 10},
+		{name: "weights count", rubric: rubric, scores: CriterionScores{"craft": 8, "fit": 4}, want: 7},
+		{name: "missing criteria score 0", rubric: rubric, scores: CriterionScores{"craft": 4}, want: 3},
+		{name: "unknown criteria are ignored", rubric: rubric, scores: CriterionScores{"craft": 4, "other": 10}, want: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rubric.Weighted(test.scores); got != test.want {
+				t.Errorf("Weighted(%v) = %v, want %v", test.scores, got, test.want)
+			}
+		})
+	}
+}
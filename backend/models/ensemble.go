@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ConflictWindow is how close two events can start before a performer cannot be booked for both. Events only have a
+// start time, so this stands in for how long one takes.
+const ConflictWindow = 4 * time.Hour
+
+type MembershipStatus string
+
+const (
+	MembershipInvited  MembershipStatus = "invited"
+	MembershipActive   MembershipStatus = "active"
+	MembershipDeclined MembershipStatus = "declined"
+)
+
+func (MembershipStatus) GormDataType() string   { return "membership_status" }
+func (MembershipStatus) GormDBDataType() string { return "membership_status" }
+func (m MembershipStatus) String() string       { return string(m) }
+
+// UnmarshalJSON rejects unknown membership statuses. An empty string leaves the status unset.
+func (m *MembershipStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch status := MembershipStatus(s); status {
+	case MembershipInvited, MembershipActive, MembershipDeclined, "":
+		*m = status
+	default:
+		return invalidEnum("status", s, MembershipInvited, MembershipActive, MembershipDeclined)
+	}
+	return nil
+}
+
+// EnsembleMember ties a performer to an ensemble, such as a band or a troupe, that applies as one act. The ensemble
+// invites; the membership only counts once the member's own profile has accepted.
+type EnsembleMember struct {
+	Model
+	EnsembleID uuid.UUID        `json:"ensemble_id" gorm:"uniqueIndex:idx_ensemble_member"`
+	Ensemble   *Profile         `json:"ensemble,omitempty" gorm:"foreignKey:EnsembleID"`
+	MemberID   uuid.UUID        `json:"member_id" gorm:"uniqueIndex:idx_ensemble_member;index"`
+	Member     *Profile         `json:"member,omitempty" gorm:"foreignKey:MemberID"`
+	Role       string           `json:"role,omitempty"`
+	Status     MembershipStatus `json:"status" gorm:"type:membership_status;not null;default:'invited'"`
+}
+
+// EnsembleInvitation is what an ensemble sends to invite a performer.
+type EnsembleInvitation struct {
+	MemberID uuid.UUID `json:"member_id" binding:"required"`
+	Role     string    `json:"role,omitempty"`
+}
+
+// InvitationResponse is a member's answer to an ensemble's invitation.
+type InvitationResponse struct {
+	Accept bool `json:"accept"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type QuestionType string
+
+const (
+	QuestionText   QuestionType = "text"
+	QuestionChoice QuestionType = "choice"
+	QuestionURL    QuestionType = "url"
+	// QuestionFile is answered with a link to the file wherever the performer keeps it; files are not uploaded here.
+	QuestionFile   QuestionType = "file"
+	QuestionNumber QuestionType = "number"
+)
+
+// Question is one field of an event's application form. ID is the key its answer is stored under, so it stays the
+// same across versions of the form while the label is reworded.
+type Question struct {
+	ID       string       `json:"id"`
+	Label    string       `json:"label"`
+	Help     string       `json:"help,omitempty"`
+	Type     QuestionType `json:"type"`
+	Required bool         `json:"required"`
+	// Options and Multiple only apply to choice questions.
+	Options  []string `json:"options,omitempty"`
+	Multiple bool     `json:"multiple,omitempty"`
+}
+
+type Questions []Question
+
+func (q Questions) Value() (driver.Value, error) {
+	if q == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(q)
+	return string(data), err
+}
+
+func (q *Questions) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*q = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, q)
+	case string:
+		return json.Unmarshal([]byte(v), q)
+	default:
+		return fmt.Errorf("unable to scan %T into Questions", value)
+	}
+}
+
+func (Questions) GormDataType() string { return "jsonb" }
+
+// ApplicationForm is one version of the questions an event asks its applicants. A version is edited in place until
+// an application answers it; the next edit then starts a new version, so every application can still be read against
+// the exact questions it answered.
+type ApplicationForm struct {
+	Model
+	EventRef  uuid.UUID `json:"event_ref" gorm:"uniqueIndex:idx_form_event_version"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_form_event_version"`
+	Questions Questions `json:"questions" gorm:"type:jsonb;not null;default:'[]'"`
+}
+
+// Answers maps a question ID to its answer: a string for text, url, file and single choice questions, an array of
+// strings for multiple choice and a number for number questions.
+type Answers map[string]json.RawMessage
+
+func (a Answers) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *Answers) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("unable to scan %T into Answers", value)
+	}
+}
+
+func (Answers) GormDataType() string { return "jsonb" }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// IdempotencyTTL is how long a stored response is replayed for its Idempotency-Key.
+const IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyLease is how long a request holds its Idempotency-Key before a retry may take it over, should the request
+// have neither completed nor released it, say because the server died while handling it.
+const IdempotencyLease = time.Minute
+
+// IdempotencyRecord is the first response to a request carrying an Idempotency-Key, stored per caller. Completed is
+// false while the original request is still being handled. CreatedAt identifies the reservation, so a request whose key
+// was taken over can no longer complete or release it.
+type IdempotencyRecord struct {
+	Caller      string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/google/uuid"
+
+// Message is a note between the two sides of an application. AuthorID is the profile it was sent for, the performer
+// or the event's producer.
+type Message struct {
+	Model
+	ApplicationID uuid.UUID `json:"application_id" gorm:"index"`
+	AuthorID      uuid.UUID `json:"author_id"`
+	SenderUID     string    `json:"-"`
+	Body          string    `json:"body"`
+}
+
+// MessageRequest is a message to send. ProfileID names the side the caller writes for, since one user may belong to
+// both the performer and the producer.
+type MessageRequest struct {
+	ProfileID uuid.UUID `json:"profile_id" binding:"required"`
+	Body      string    `json:"body" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Organization owns producer and venue profiles, such as the brands and venues of one company. Its admins act as
+// admins of every profile it owns.
+type Organization struct {
+	Model
+	Name     string    `json:"name" gorm:"not null" binding:"required"`
+	Profiles []Profile `json:"profiles,omitempty" gorm:"foreignKey:OrganizationID"`
+}
+
+// OrganizationAdmin is a firebase user who administers the organization and, through it, its profiles.
+type OrganizationAdmin struct {
+	Model
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"uniqueIndex:idx_organization_admin"`
+	FirebaseId     string    `json:"firebase_id" gorm:"uniqueIndex:idx_organization_admin;index"`
+}
+
+// OrganizationAdminRequest names the firebase user to make an admin of the organization.
+type OrganizationAdminRequest struct {
+	FirebaseId string `json:"firebase_id" binding:"required"`
+}
+
+// OrganizationProfileRequest names a profile for the organization to take over.
+type OrganizationProfileRequest struct {
+	ProfileID uuid.UUID `json:"profile_id" binding:"required"`
+}
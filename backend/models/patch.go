@@ -0,0 +1,129 @@
+package models
+
+import (
+	"backend/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrVersionConflict means the row changed since the caller read it, or no longer matches the If-Match it sent.
+var ErrVersionConflict = domain.PreconditionFailed(
+	"version_conflict", "resource was modified by someone else; fetch it again and retry",
+)
+
+var (
+	ErrInvalidPatch   = domain.BadRequest("invalid_patch", "patch must be a JSON object")
+	ErrImmutableField = domain.Validation("immutable_field", "field cannot be changed")
+	ErrUnknownField   = domain.Validation("unknown_field", "unknown field")
+	ErrInvalidField   = domain.Validation("invalid_field", "invalid field value")
+)
+
+// ApplyMergePatch applies an RFC 7396 JSON merge patch to the struct target points to. Sent fields replace the
+// current ones, null resets a field to its zero value, objects are merged recursively and everything else is left
+// alone. Fields hidden from JSON keep their current value. Changing any of the immutable top-level fields, or sending
+// one the struct does not have, is rejected with one of the ErrXField errors naming it, leaving target untouched.
+func ApplyMergePatch(target interface{}, patch []byte, immutable ...string) error {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return ErrInvalidPatch
+	}
+	value := reflect.ValueOf(target).Elem()
+	known := map[string]struct{}{}
+	jsonFields(value.Type(), known)
+	for key := range changes {
+		if _, ok := known[key]; !ok {
+			return ErrUnknownField.WithFields(domain.FieldError{Field: key, Message: "is not a field of this resource"})
+		}
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	// merging happens in place, so keep a separate copy of the current state to compare against
+	current, merged := map[string]interface{}{}, map[string]interface{}{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return err
+	}
+	_ = json.Unmarshal(data, &merged)
+	mergePatch(merged, changes)
+	for _, field := range immutable {
+		if _, sent := changes[field]; sent && !reflect.DeepEqual(current[field], merged[field]) {
+			return ErrImmutableField.WithFields(domain.FieldError{Field: field, Message: "cannot be changed"})
+		}
+	}
+
+	if data, err = json.Marshal(merged); err != nil {
+		return err
+	}
+	next := reflect.New(value.Type())
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(next.Interface()); err != nil {
+		if domainErr, ok := domain.As(err); ok {
+			return domainErr
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return ErrInvalidField.WithFields(domain.FieldError{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()})
+		}
+		return ErrInvalidField.Wrap(err)
+	}
+	carryHidden(next.Elem(), value)
+	value.Set(next.Elem())
+	return nil
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	current, ok := target.(map[string]interface{})
+	if !ok {
+		current = map[string]interface{}{}
+	}
+	for key, change := range changes {
+		if change == nil {
+			delete(current, key)
+		} else {
+			current[key] = mergePatch(current[key], change)
+		}
+	}
+	return current
+}
+
+// jsonFields collects the top-level JSON names of a struct type, following untagged embedded structs.
+func jsonFields(t reflect.Type, names map[string]struct{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch {
+		case tag == "-" || !field.IsExported():
+		case field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct:
+			jsonFields(field.Type, names)
+		case tag != "":
+			names[tag] = struct{}{}
+		default:
+			names[field.Name] = struct{}{}
+		}
+	}
+}
+
+// carryHidden copies the fields JSON cannot see, such as foreign keys tagged json:"-", from src to dst.
+func carryHidden(dst reflect.Value, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch {
+		case !field.IsExported():
+		case tag == "-":
+			dst.Field(i).Set(src.Field(i))
+		case field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct:
+			carryHidden(dst.Field(i), src.Field(i))
+		}
+	}
+}
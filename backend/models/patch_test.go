@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type patchNested struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type patchTarget struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Count  int         `json:"count"`
+	Nested patchNested `json:"nested"`
+	Hidden string      `json:"-"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	current := patchTarget{ID: "1", Name: "name", Count: 2, Nested: patchNested{A: "a", B: "b"}, Hidden: "hidden"}
+	tests := []struct {
+		name    string
+		patch   string
+		want    patchTarget
+		wantErr error
+	}{
+		{
+			name:  "replaces sent fields",
+			patch: `{"name": "other", "count": 3}`,
+			want:  patchTarget{ID: "1", Name: "other", Count: 3, Nested: patchNested{A: "a", B: "b"}, Hidden: "hidden"},
+		},
+		{
+			name:  "null resets a field",
+			patch: `{"name": null}`,
+			want:  patchTarget{ID: "1", Count: 2, Nested: patchNested{A: "a", B: "b"}, Hidden: "hidden"},
+		},
+		{
+			name:  "merges objects",
+			patch: `{"nested": {"b": "c"}}`,
+			want:  patchTarget{ID: "1", Name: "name", Count: 2, Nested: patchNested{A: "a", B: "c"}, Hidden: "hidden"},
+		},
+		{
+			name:  "immutable field sent unchanged",
+			patch: `{"id": "1", "name": "other"}`,
+			want:  patchTarget{ID: "1", Name: "other", Count: 2, Nested: patchNested{A: "a", B: "b"}, Hidden: "hidden"},
+		},
+		{name: "immutable field changed", patch: `{"id": "2"}`, wantErr: ErrImmutableField},
+		{name: "unknown field", patch: `{"color": "red"}`, wantErr: ErrUnknownField},
+		{name: "hidden field", patch: `{"Hidden": "shown"}`, wantErr: ErrUnknownField},
+		{name: "wrong type", patch: `{"count": "three"}`, wantErr: ErrInvalidField},
+		{name: "not an object", patch: `[1]`, wantErr: ErrInvalidPatch},
+		{name: "null", patch: `null`, wantErr: ErrInvalidPatch},
+		{name: "not JSON", patch: `{`, wantErr: ErrInvalidPatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := current
+			err := ApplyMergePatch(&target, []byte(test.patch), "id")
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("ApplyMergePatch() error = %v, want %v", err, test.wantErr)
+				}
+				if !reflect.DeepEqual(target, current) {
+					t.Errorf("a rejected patch changed the target to %+v", target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(target, test.want) {
+				t.Errorf("ApplyMergePatch() = %+v, want %+v", target, test.want)
+			}
+		})
+	}
+}
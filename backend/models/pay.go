@@ -0,0 +1,297 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PayType string
+
+const (
+	PayFlatFee     PayType = "flat_fee"
+	PayDoorSplit   PayType = "door_split"
+	PayTicketTiers PayType = "ticket_tiers"
+	PayUnpaid      PayType = "unpaid"
+	PayUnspecified PayType = "unspecified"
+)
+
+func (PayType) GormDataType() string   { return "pay_type" }
+func (PayType) GormDBDataType() string { return "pay_type" }
+func (p PayType) String() string       { return string(p) }
+
+type PayBasis string
+
+const (
+	PerPerformer PayBasis = "per_performer"
+	PerAct       PayBasis = "per_act"
+)
+
+func (PayBasis) GormDataType() string   { return "pay_basis" }
+func (PayBasis) GormDBDataType() string { return "pay_basis" }
+func (p PayBasis) String() string       { return string(p) }
+
+// PayTier pays Amount once at least MinTickets tickets have been sold.
+type PayTier struct {
+	MinTickets int   `json:"min_tickets"`
+	Amount     int64 `json:"amount"`
+}
+
+type PayTiers []PayTier
+
+func (t PayTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(t)
+	return string(data), err
+}
+
+func (t *PayTiers) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return fmt.Errorf("unable to scan %T into PayTiers", value)
+	}
+}
+
+func (PayTiers) GormDataType() string { return "jsonb" }
+
+// PayStructure describes how performers are compensated. All amounts are in the minor unit of Currency (cents for USD).
+type PayStructure struct {
+	Type             PayType  `json:"type" gorm:"type:pay_type;default:unspecified"`
+	Basis            PayBasis `json:"basis" gorm:"type:pay_basis;default:per_performer"`
+	Currency         string   `json:"currency,omitempty" gorm:"size:3"`
+	FlatFee          int64    `json:"flat_fee,omitempty"`
+	DoorSplitPercent float64  `json:"door_split_percent,omitempty"`
+	Tiers            PayTiers `json:"tiers,omitempty" gorm:"type:jsonb"`
+	Notes            string   `json:"notes,omitempty"`
+	// MinAmount is the guaranteed minimum, kept in its own column so searches can filter on it.
+	MinAmount int64 `json:"min_amount" gorm:"index"`
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func (p PayStructure) Validate() error {
+	switch p.Basis {
+	case PerPerformer, PerAct, "":
+	default:
+		return fmt.Errorf("invalid pay basis %s. Allowed: per_performer, per_act", p.Basis)
+	}
+	switch p.Type {
+	case PayUnpaid, PayUnspecified, "":
+		return nil
+	case PayFlatFee:
+		if p.FlatFee <= 0 {
+			return errors.New("flat_fee must be positive")
+		}
+	case PayDoorSplit:
+		if p.DoorSplitPercent <= 0 || p.DoorSplitPercent > 100 {
+			return errors.New("door_split_percent must be within (0, 100]")
+		}
+	case PayTicketTiers:
+		if len(p.Tiers) == 0 {
+			return errors.New("ticket_tiers requires at least one tier")
+		}
+		for _, tier := range p.Tiers {
+			if tier.MinTickets < 0 || tier.Amount < 0 {
+				return errors.New("tiers must not be negative")
+			}
+		}
+	default:
+		return fmt.Errorf("invalid pay type %s. Allowed: flat_fee, door_split, ticket_tiers, unpaid, unspecified", p.Type)
+	}
+	if !currencyPattern.MatchString(p.Currency) {
+		return fmt.Errorf("invalid currency %q, expected an ISO 4217 code", p.Currency)
+	}
+	return nil
+}
+
+// Normalize fills defaults, orders the tiers and recomputes MinAmount.
+func (p *PayStructure) Normalize() {
+	if p.Type == "" {
+		p.Type = PayUnspecified
+	}
+	if p.Basis == "" {
+		p.Basis = PerPerformer
+	}
+	p.Currency = strings.ToUpper(p.Currency)
+	sort.Slice(p.Tiers, func(i, j int) bool { return p.Tiers[i].MinTickets < p.Tiers[j].MinTickets })
+	p.MinAmount = p.minimumGuaranteed()
+}
+
+func (p PayStructure) minimumGuaranteed() int64 {
+	switch p.Type {
+	case PayFlatFee:
+		return p.FlatFee
+	case PayTicketTiers:
+		if len(p.Tiers) > 0 && p.Tiers[0].MinTickets == 0 {
+			return p.Tiers[0].Amount
+		}
+	}
+	return 0
+}
+
+// Describe renders the pay structure as a sentence for contracts and feeds.
+func (p PayStructure) Describe() string {
+	basis := strings.ReplaceAll(p.Basis.String(), "_", " ")
+	var text string
+	switch p.Type {
+	case PayFlatFee:
+		text = fmt.Sprintf("Flat fee of %s %s", formatMinorUnits(p.FlatFee), p.Currency)
+	case PayDoorSplit:
+		text = fmt.Sprintf("%s%% of door revenue", strconv.FormatFloat(p.DoorSplitPercent, 'f', -1, 64))
+	case PayTicketTiers:
+		tiers := make([]string, len(p.Tiers))
+		for i, tier := range p.Tiers {
+			tiers[i] = fmt.Sprintf("%s %s from %d tickets", formatMinorUnits(tier.Amount), p.Currency, tier.MinTickets)
+		}
+		text = "Ticket sales tiers: " + strings.Join(tiers, "; ")
+	case PayUnpaid:
+		return "Unpaid"
+	default:
+		if p.Notes != "" {
+			return p.Notes
+		}
+		return "Not specified"
+	}
+	if basis != "" {
+		text += " " + basis
+	}
+	return text
+}
+
+func formatMinorUnits(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// Shares is how many shares of the pay an act with the given number of active members is owed: one per performer
+// under a per_performer basis, where a solo act counts as one, and one per act otherwise.
+func (p PayStructure) Shares(members int) int {
+	if p.Basis == PerAct || members < 1 {
+		return 1
+	}
+	return members
+}
+
+// ExpectedPayout computes what an accepted act holding shares of the pay is owed given the event's actual revenue.
+// Flat fees and ticket tiers are paid once per share, and the door split pool is divided between the totalShares held
+// by every accepted act. ok is false when the amount cannot be known yet, e.g. before the door revenue or ticket count
+// has been recorded, or when the pay structure was never specified.
+func (p PayStructure) ExpectedPayout(doorRevenue *int64, ticketsSold *int, shares int, totalShares int) (amount int64, ok bool) {
+	switch p.Type {
+	case PayUnpaid:
+		return 0, true
+	case PayFlatFee:
+		return p.FlatFee * int64(shares), true
+	case PayDoorSplit:
+		if doorRevenue == nil || totalShares == 0 {
+			return 0, false
+		}
+		pool := math.Round(float64(*doorRevenue) * p.DoorSplitPercent / 100)
+		return int64(pool) / int64(totalShares) * int64(shares), true
+	case PayTicketTiers:
+		if ticketsSold == nil {
+			return p.MinAmount * int64(shares), false
+		}
+		for i := len(p.Tiers) - 1; i >= 0; i-- {
+			if *ticketsSold >= p.Tiers[i].MinTickets {
+				return p.Tiers[i].Amount * int64(shares), true
+			}
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+var legacyAmountPattern = regexp.MustCompile(`\$\s*([0-9]+(?:\.[0-9]{1,2})?)`)
+var legacyPercentPattern = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*%`)
+
+// ParseLegacyPayStructure makes a best effort at structuring the old free-text pay_structure column.
+// The original text is always kept in Notes so nothing is lost when the guess is wrong. Text naming both a dollar
+// amount and a percentage, say a guarantee plus a door split, fits no single pay type and is left unspecified.
+func ParseLegacyPayStructure(s string) PayStructure {
+	pay := PayStructure{Type: PayUnspecified, Notes: s}
+	lower := strings.ToLower(s)
+	switch {
+	case strings.TrimSpace(lower) == "":
+	case strings.Contains(lower, "unpaid"), strings.Contains(lower, "exposure"), strings.Contains(lower, "volunteer"):
+		pay.Type = PayUnpaid
+	case legacyPercentPattern.MatchString(lower) && legacyAmountPattern.MatchString(lower):
+	case legacyPercentPattern.MatchString(lower):
+		if percent, err := strconv.ParseFloat(legacyPercentPattern.FindStringSubmatch(lower)[1], 64); err == nil {
+			pay.Type = PayDoorSplit
+			pay.DoorSplitPercent = percent
+			pay.Currency = "USD"
+		}
+	case legacyAmountPattern.MatchString(lower):
+		if amount, err := strconv.ParseFloat(legacyAmountPattern.FindStringSubmatch(lower)[1], 64); err == nil {
+			pay.Type = PayFlatFee
+			pay.FlatFee = int64(math.Round(amount * 100))
+			pay.Currency = "USD"
+		}
+	}
+	if strings.Contains(lower, "per act") || strings.Contains(lower, "per group") {
+		pay.Basis = PerAct
+	}
+	pay.Normalize()
+	return pay
+}
+
+// MigrateLegacyPayStructure moves the free-text events.pay_structure column into the structured pay_* columns and drops it.
+func MigrateLegacyPayStructure(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&Event{}, "pay_structure") {
+		return nil
+	}
+	type legacyRow struct {
+		ID           string
+		PayStructure string
+	}
+	var rows []legacyRow
+	if err := db.Table("events").Select("id, pay_structure").
+		Where("pay_structure IS NOT NULL AND pay_structure <> ''").Scan(&rows).Error; err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			pay := ParseLegacyPayStructure(row.PayStructure)
+			// by table rather than model, so events in the trash are converted too
+			if err := tx.Table("events").Where("id = ?", row.ID).Updates(map[string]interface{}{
+				"pay_type":               pay.Type,
+				"pay_basis":              pay.Basis,
+				"pay_currency":           pay.Currency,
+				"pay_flat_fee":           pay.FlatFee,
+				"pay_door_split_percent": pay.DoorSplitPercent,
+				"pay_notes":              pay.Notes,
+				"pay_min_amount":         pay.MinAmount,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&Event{}, "pay_structure")
+	})
+}
+
+// MinimumPay restricts an event search to offers guaranteeing at least Amount in Currency.
+type MinimumPay struct {
+	Amount   int64
+	Currency string
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLegacyPayStructure(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want PayStructure
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: PayStructure{Type: PayUnspecified, Basis: PerPerformer},
+		},
+		{
+			name: "flat fee",
+			in:   "$150 per performer",
+			want: PayStructure{
+				Type: PayFlatFee, Basis: PerPerformer, Currency: "USD", FlatFee: 15000, MinAmount: 15000,
+				Notes: "$150 per performer",
+			},
+		},
+		{
+			name: "flat fee with cents per act",
+			in:   "$ 99.5 per act",
+			want: PayStructure{
+				Type: PayFlatFee, Basis: PerAct, Currency: "USD", FlatFee: 9950, MinAmount: 9950, Notes: "$ 99.5 per act",
+			},
+		},
+		{
+			name: "door split",
+			in:   "70% of the door",
+			want: PayStructure{
+				Type: PayDoorSplit, Basis: PerPerformer, Currency: "USD", DoorSplitPercent: 70, Notes: "70% of the door",
+			},
+		},
+		{
+			name: "unpaid",
+			in:   "Unpaid, exposure only",
+			want: PayStructure{Type: PayUnpaid, Basis: PerPerformer, Notes: "Unpaid, exposure only"},
+		},
+		{
+			name: "guarantee and door split fit no single type",
+			in:   "$100 guarantee vs 80% door per group",
+			want: PayStructure{Type: PayUnspecified, Basis: PerAct, Notes: "$100 guarantee vs 80% door per group"},
+		},
+		{
+			name: "anything else keeps the text",
+			in:   "drinks and a meal",
+			want: PayStructure{Type: PayUnspecified, Basis: PerPerformer, Notes: "drinks and a meal"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseLegacyPayStructure(test.in); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseLegacyPayStructure(%q) = %+v, want %+v", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpectedPayout(t *testing.T) {
+	revenue, tickets, fewTickets := int64(100000), 120, 10
+	tiers := PayTiers{{MinTickets: 0, Amount: 5000}, {MinTickets: 50, Amount: 10000}, {MinTickets: 100, Amount: 20000}}
+	tests := []struct {
+		name        string
+		pay         PayStructure
+		doorRevenue *int64
+		ticketsSold *int
+		shares      int
+		totalShares int
+		want        int64
+		wantOK      bool
+	}{
+		{name: "unpaid", pay: PayStructure{Type: PayUnpaid}, shares: 1, totalShares: 1, want: 0, wantOK: true},
+		{name: "unspecified", pay: PayStructure{Type: PayUnspecified}, shares: 1, totalShares: 1, want: 0, wantOK: false},
+		{
+			name: "flat fee per share", pay: PayStructure{Type: PayFlatFee, FlatFee: 15000},
+			shares: 3, totalShares: 4, want: 45000, wantOK: true,
+		},
+		{
+			name: "door split before revenue", pay: PayStructure{Type: PayDoorSplit, DoorSplitPercent: 70},
+			shares: 1, totalShares: 2, want: 0, wantOK: false,
+		},
+		{
+			name: "door split divided between shares", pay: PayStructure{Type: PayDoorSplit, DoorSplitPercent: 70},
+			doorRevenue: &revenue, shares: 3, totalShares: 4, want: 52500, wantOK: true,
+		},
+		{
+			name: "door split without accepted acts", pay: PayStructure{Type: PayDoorSplit, DoorSplitPercent: 70},
+			doorRevenue: &revenue, shares: 1, totalShares: 0, want: 0, wantOK: false,
+		},
+		{
+			name:   "tiers before tickets are counted pay the minimum",
+			pay:    PayStructure{Type: PayTicketTiers, Tiers: tiers, MinAmount: 5000},
+			shares: 2, totalShares: 2, want: 10000, wantOK: false,
+		},
+		{
+			name: "highest tier reached", pay: PayStructure{Type: PayTicketTiers, Tiers: tiers, MinAmount: 5000},
+			ticketsSold: &tickets, shares: 2, totalShares: 2, want: 40000, wantOK: true,
+		},
+		{
+			name: "lowest tier", pay: PayStructure{Type: PayTicketTiers, Tiers: tiers, MinAmount: 5000},
+			ticketsSold: &fewTickets, shares: 1, totalShares: 1, want: 5000, wantOK: true,
+		},
+		{
+			name: "no tier reached", pay: PayStructure{Type: PayTicketTiers, Tiers: PayTiers{{MinTickets: 50, Amount: 10000}}},
+			ticketsSold: &fewTickets, shares: 1, totalShares: 1, want: 0, wantOK: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.pay.ExpectedPayout(test.doorRevenue, test.ticketsSold, test.shares, test.totalShares)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("ExpectedPayout() = %d, %v, want %d, %v", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestShares(t *testing.T) {
+	tests := []struct {
+		basis   PayBasis
+		members int
+		want    int
+	}{
+		{basis: PerPerformer, members: 0, want: 1},
+		{basis: PerPerformer, members: 1, want: 1},
+		{basis: PerPerformer, members: 4, want: 4},
+		{basis: PerAct, members: 4, want: 1},
+	}
+	for _, test := range tests {
+		if got := (PayStructure{Basis: test.basis}).Shares(test.members); got != test.want {
+			t.Errorf("Shares(%d) under %s = %d, want %d", test.members, test.basis, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"time"
+)
+
+// MaxPublicEvents bounds one page of the public listing.
+const MaxPublicEvents = 100
+
+// Published tells whether the event is listed publicly: drafts, cancelled events and events of unknown status are
+// not.
+func (e Event) Published() bool {
+	return e.Status == EventOpen || e.Status == EventClosed
+}
+
+// PublicEventFilter narrows the public listing to events starting in [From, To), if To is set, of one producer,
+// venue or tag. AcceptingApplications keeps only open events whose apply-by deadline has not passed at From.
+type PublicEventFilter struct {
+	From                  time.Time
+	To                    *time.Time
+	ProducerID            *uuid.UUID
+	VenueID               *uuid.UUID
+	Tag                   string
+	AcceptingApplications bool
+	Limit                 int
+}
+
+// Feed is a published list of calls for performers: a producer's or venue's upcoming open events, or those with a
+// tag. Updated is when the feed last changed, for readers that poll it.
+type Feed struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Updated     time.Time     `json:"updated"`
+	Events      []PublicEvent `json:"events"`
+}
+
+// PublicProfile is what the public sees of a producer or venue.
+type PublicProfile struct {
+	ID   uuid.UUID   `json:"id"`
+	Name string      `json:"name"`
+	Type ProfileType `json:"type"`
+}
+
+// PublicEvent is a published event without anything internal: its applications, its Google form, its offer settings
+// and, if the producer chose to hide it, its pay.
+type PublicEvent struct {
+	ID                uuid.UUID              `json:"id"`
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description"`
+	Tags              []string               `json:"tags"`
+	Producer          PublicProfile          `json:"producer"`
+	Venue             *PublicProfile         `json:"venue,omitempty"`
+	Location          gormGIS.GeoPoint       `json:"location"`
+	ApplicationStatus EventApplicationStatus `json:"application_status"`
+	Time              time.Time              `json:"time"`
+	ApplyByTime       *time.Time             `json:"apply_by_time,omitempty"`
+	Pay               *PayStructure          `json:"pay,omitempty"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+func publicProfile(profile Profile) PublicProfile {
+	return PublicProfile{ID: profile.ID, Name: profile.Name, Type: profile.ProfileType}
+}
+
+// NewPublicEvent redacts the event, which has to be loaded with its tags, producer and venue.
+func NewPublicEvent(event Event) PublicEvent {
+	out := PublicEvent{
+		ID:                event.ID,
+		Name:              event.Name,
+		Description:       event.Description,
+		Tags:              make([]string, len(event.Tags)),
+		Producer:          publicProfile(event.Producer),
+		Location:          event.Location,
+		ApplicationStatus: event.Status,
+		Time:              event.Time,
+		ApplyByTime:       event.ApplyByTime,
+		UpdatedAt:         event.UpdatedAt,
+	}
+	for i, tag := range event.Tags {
+		out.Tags[i] = tag.Name
+	}
+	if event.Venue != nil {
+		venue := publicProfile(*event.Venue)
+		out.Venue = &venue
+	}
+	if !event.HidePay {
+		pay := event.Pay
+		out.Pay = &pay
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ReviewWindow is how long after an event both sides may review each other. Reviews are published when both sides
+// have submitted, or when the window closes, whichever comes first.
+const ReviewWindow = 14 * 24 * time.Hour
+
+type ReviewDirection string
+
+const (
+	ProducerReviewsPerformer ReviewDirection = "producer_to_performer"
+	PerformerReviewsProducer ReviewDirection = "performer_to_producer"
+)
+
+func (ReviewDirection) GormDataType() string   { return "review_direction" }
+func (ReviewDirection) GormDBDataType() string { return "review_direction" }
+func (r ReviewDirection) String() string       { return string(r) }
+
+type ModerationStatus string
+
+const (
+	ModerationVisible ModerationStatus = "visible"
+	ModerationFlagged ModerationStatus = "flagged"
+	ModerationRemoved ModerationStatus = "removed"
+)
+
+func (ModerationStatus) GormDataType() string   { return "moderation_status" }
+func (ModerationStatus) GormDBDataType() string { return "moderation_status" }
+func (m ModerationStatus) String() string       { return string(m) }
+
+func ParseModerationStatus(s string) (ModerationStatus, error) {
+	switch status := ModerationStatus(s); status {
+	case ModerationVisible, ModerationFlagged, ModerationRemoved:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid moderation status %s. Allowed: visible, flagged, removed", s)
+	}
+}
+
+type Review struct {
+	Model
+	EventRef          uuid.UUID        `json:"event_ref" gorm:"index"`
+	ApplicationID     uuid.UUID        `json:"application_id" gorm:"uniqueIndex:idx_review_application_direction"`
+	Direction         ReviewDirection  `json:"direction" gorm:"type:review_direction;uniqueIndex:idx_review_application_direction"`
+	ReviewerProfileID uuid.UUID        `json:"reviewer_profile_id"`
+	SubjectProfileID  uuid.UUID        `json:"subject_profile_id" gorm:"index"`
+	Rating            int              `json:"rating"`
+	Body              string           `json:"body"`
+	SubmittedBy       string           `json:"-"`
+	Moderation        ModerationStatus `json:"moderation" gorm:"type:moderation_status;default:visible"`
+	ModerationNote    string           `json:"moderation_note,omitempty"`
+	// PublishedAt starts at the end of the review window and is pulled forward once the counterpart review arrives.
+	PublishedAt time.Time `json:"published_at" gorm:"index"`
+}
+
+// Reputation aggregates the published, non-removed reviews about a profile.
+type Reputation struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
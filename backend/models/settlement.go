@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentCash         PaymentMethod = "cash"
+	PaymentBankTransfer PaymentMethod = "bank_transfer"
+	PaymentCheck        PaymentMethod = "check"
+	PaymentPaypal       PaymentMethod = "paypal"
+	PaymentVenmo        PaymentMethod = "venmo"
+	PaymentOther        PaymentMethod = "other"
+)
+
+func (PaymentMethod) GormDataType() string   { return "payment_method" }
+func (PaymentMethod) GormDBDataType() string { return "payment_method" }
+func (p PaymentMethod) String() string       { return string(p) }
+
+func ParsePaymentMethod(s string) (PaymentMethod, error) {
+	switch method := PaymentMethod(strings.ToLower(s)); method {
+	case PaymentCash, PaymentBankTransfer, PaymentCheck, PaymentPaypal, PaymentVenmo, PaymentOther:
+		return method, nil
+	default:
+		return "", fmt.Errorf("invalid payment method %s. Allowed: cash, bank_transfer, check, paypal, venmo, other", s)
+	}
+}
+
+// EventRevenue holds the actual takings of an event, needed to settle door splits and ticket tiers.
+type EventRevenue struct {
+	Model
+	EventRef    uuid.UUID `json:"event_ref" gorm:"uniqueIndex"`
+	DoorRevenue *int64    `json:"door_revenue,omitempty"`
+	TicketsSold *int      `json:"tickets_sold,omitempty"`
+	Currency    string    `json:"currency" gorm:"size:3"`
+	Notes       string    `json:"notes,omitempty"`
+}
+
+// Payment is an entry in the settlement ledger. Corrections are recorded as new payments with a negative amount.
+type Payment struct {
+	Model
+	ApplicationID uuid.UUID     `json:"application_id" gorm:"index"`
+	EventRef      uuid.UUID     `json:"event_ref" gorm:"index"`
+	PerformerID   uuid.UUID     `json:"performer_id" gorm:"index"`
+	Amount        int64         `json:"amount"`
+	Currency      string        `json:"currency" gorm:"size:3"`
+	Method        PaymentMethod `json:"method" gorm:"type:payment_method"`
+	Reference     string        `json:"reference,omitempty"`
+	PaidAt        time.Time     `json:"paid_at"`
+	RecordedBy    string        `json:"recorded_by,omitempty"`
+}
+
+// SettlementLine is the computed position of one accepted application, or of one no longer accepted that was paid.
+type SettlementLine struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	EventRef      uuid.UUID `json:"event_ref"`
+	PerformerID   uuid.UUID `json:"performer_id"`
+	Name          string    `json:"name"`
+	Currency      string    `json:"currency"`
+	Expected      int64     `json:"expected"`
+	ExpectedKnown bool      `json:"expected_known"`
+	Paid          int64     `json:"paid"`
+	Outstanding   int64     `json:"outstanding"`
+	Payments      []Payment `json:"payments"`
+}
+
+type CurrencyTotal struct {
+	Currency    string `json:"currency"`
+	Expected    int64  `json:"expected"`
+	Paid        int64  `json:"paid"`
+	Outstanding int64  `json:"outstanding"`
+}
+
+type EventSettlement struct {
+	EventRef uuid.UUID        `json:"event_ref"`
+	Pay      PayStructure     `json:"pay"`
+	Revenue  *EventRevenue    `json:"revenue,omitempty"`
+	Lines    []SettlementLine `json:"lines"`
+	Totals   []CurrencyTotal  `json:"totals"`
+}
+
+type PerformerBalance struct {
+	PerformerID uuid.UUID        `json:"performer_id"`
+	Lines       []SettlementLine `json:"lines"`
+	Totals      []CurrencyTotal  `json:"totals"`
+}
+
+// SumByCurrency totals settlement lines, one entry per currency in order of first appearance.
+func SumByCurrency(lines []SettlementLine) []CurrencyTotal {
+	var totals []CurrencyTotal
+	index := map[string]int{}
+	for _, line := range lines {
+		i, ok := index[line.Currency]
+		if !ok {
+			i = len(totals)
+			index[line.Currency] = i
+			totals = append(totals, CurrencyTotal{Currency: line.Currency})
+		}
+		totals[i].Expected += line.Expected
+		totals[i].Paid += line.Paid
+		totals[i].Outstanding += line.Outstanding
+	}
+	return totals
+}
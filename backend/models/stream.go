@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type StreamEventType string
+
+const (
+	StreamApplicationCreated       StreamEventType = "application.created"
+	StreamApplicationStatusChanged StreamEventType = "application.status_changed"
+	StreamMessageCreated           StreamEventType = "message.created"
+	StreamEventStatusChanged       StreamEventType = "event.status_changed"
+	// StreamApplicationPromoted tells a waitlisted performer that a declined or expired offer has passed to them.
+	StreamApplicationPromoted StreamEventType = "application.promoted"
+	// StreamOfferReminder tells an offered performer that the offer's deadline is close.
+	StreamOfferReminder StreamEventType = "application.offer_reminder"
+)
+
+func (s StreamEventType) String() string { return string(s) }
+
+// StreamEvent is a single real-time notification. Audience holds the profiles whose members are allowed to receive it.
+type StreamEvent struct {
+	Type       StreamEventType `json:"type"`
+	ResourceID uuid.UUID       `json:"resource_id"`
+	Audience   []uuid.UUID     `json:"audience,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Time       time.Time       `json:"time"`
+}
+
+func NewStreamEvent(eventType StreamEventType, resourceID uuid.UUID, payload interface{}, audience ...uuid.UUID) (StreamEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return StreamEvent{}, err
+	}
+	return StreamEvent{
+		Type:       eventType,
+		ResourceID: resourceID,
+		Audience:   audience,
+		Payload:    data,
+		Time:       time.Now().UTC(),
+	}, nil
+}
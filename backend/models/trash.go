@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TrashRetention is how long soft-deleted rows can be restored before the retention job purges them.
+const TrashRetention = 30 * 24 * time.Hour
+
+type TrashKind string
+
+const (
+	TrashEvent       TrashKind = "event"
+	TrashApplication TrashKind = "application"
+	TrashProfile     TrashKind = "profile"
+)
+
+func (t TrashKind) String() string { return string(t) }
+
+func ParseTrashKind(s string) (TrashKind, error) {
+	switch kind := TrashKind(s); kind {
+	case TrashEvent, TrashApplication, TrashProfile:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("invalid trash kind %s. Allowed: event, application, profile", s)
+	}
+}
+
+// TrashItem is a soft-deleted event, application or profile. ProfileIDs are the profiles that own it.
+type TrashItem struct {
+	Kind       TrashKind   `json:"kind"`
+	ID         uuid.UUID   `json:"id"`
+	Name       string      `json:"name"`
+	ProfileIDs []uuid.UUID `json:"-"`
+	DeletedAt  time.Time   `json:"deleted_at"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
@@ -1,11 +1,11 @@
 package models
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/nferruzzi/gormGIS"
-	"gorm.io/gorm"
 	"strings"
 )
 
@@ -22,27 +22,18 @@ const (
 func (ProfileType) GormDataType() string   { return "profile_type" }
 func (ProfileType) GormDBDataType() string { return "profile_type" }
 func (p ProfileType) String() string       { return string(p) }
-func AutoMigrateProfileType(db *gorm.DB) error {
-	if err := AutoMigrateEnumType("profile_type", db, ProducerType, PerformerType, VenueType); err != nil {
-		return err
-	}
 
-	return nil
-}
-func (t *ProfileType) UnmarshallJSON(data []byte) error {
+// UnmarshalJSON rejects unknown profile types. An empty string leaves the type unset.
+func (t *ProfileType) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
-	switch s {
-	case "producer":
-		*t = ProducerType
-	case "performer":
-		*t = PerformerType
-	case "venue":
-		*t = VenueType
+	switch profileType := ProfileType(s); profileType {
+	case ProducerType, PerformerType, VenueType, "":
+		*t = profileType
 	default:
-		return fmt.Errorf("unrecognized profile type %s. Allowed: producer, performer, venue", s)
+		return invalidEnum("type", s, ProducerType, PerformerType, VenueType)
 	}
 	return nil
 }
@@ -51,17 +42,51 @@ type UserID struct {
 	Model
 	FirebaseId  string
 	Permissions Permission `gorm:"type:permission;default:unknown"`
-	ProfileId   uuid.UUID  `json:"-" gorm:"type:uuid"`
+	ProfileId   uuid.UUID  `json:"-"`
 }
 
 type Profile struct {
 	Model
-	Name        string `json:"name,nonempty" gorm:"notnull"`
-	ProfileType `json:"type" gorm:"type:profile_type;notnull"`
+	Name        string      `json:"name" gorm:"notnull"`
+	ProfileType ProfileType `json:"type" gorm:"type:profile_type;notnull"`
 	Location    *gormGIS.GeoPoint
-	UserIDs     []UserID `json:"-" gorm:"foreignKey:ProfileId"`
+	UserIDs     []UserID    `json:"-" gorm:"foreignKey:ProfileId"`
+	Reputation  *Reputation `json:"reputation,omitempty" gorm:"-"`
+	Version     int64       `json:"version" gorm:"not null;default:1"`
+	// OrganizationID is the organization that owns the profile, if any; its admins act as admins of the profile.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" gorm:"index"`
+	// Ensemble marks a performer profile that stands for a group act; its members are in EnsembleMember.
+	Ensemble bool `json:"ensemble" gorm:"not null;default:false"`
+	// PortfolioLinks point reviewers at recordings, videos or a website.
+	PortfolioLinks PortfolioLinks `json:"portfolio_links,omitempty" gorm:"type:jsonb;not null;default:'[]'"`
 }
 
+type PortfolioLinks []string
+
+func (l PortfolioLinks) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	return string(data), err
+}
+
+func (l *PortfolioLinks) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unable to scan %T into PortfolioLinks", value)
+	}
+}
+
+func (PortfolioLinks) GormDataType() string { return "jsonb" }
+
 func ParseProfile(s string) (ProfileType, bool) {
 	converter := map[string]ProfileType{"producer": ProducerType, "performer": PerformerType, "venue": VenueType}
 	_type, ok := converter[strings.ToLower(s)]
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"backend/domain"
+	"fmt"
+	"strings"
+)
+
+// Validator accumulates field errors; Err returns nil when there were none.
+type Validator struct {
+	errors []domain.FieldError
+}
+
+func (v *Validator) Add(field string, format string, args ...interface{}) {
+	v.errors = append(v.errors, domain.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check adds the error unless ok holds.
+func (v *Validator) Check(ok bool, field string, format string, args ...interface{}) {
+	if !ok {
+		v.Add(field, format, args...)
+	}
+}
+
+func (v *Validator) Required(field string, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return domain.ErrInvalid.WithFields(v.errors...)
+}
+
+// invalidEnum is what the enum UnmarshalJSON methods return, so bad values surface as field errors while binding.
+func invalidEnum(field string, value string, allowed ...fmt.Stringer) error {
+	names := make([]string, len(allowed))
+	for i, element := range allowed {
+		names[i] = element.String()
+	}
+	return domain.ErrInvalid.WithFields(domain.FieldError{
+		Field:   field,
+		Message: fmt.Sprintf("invalid value %q. Allowed: %s", value, strings.Join(names, ", ")),
+	})
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"backend/domain"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	var v Validator
+	v.Required("name", "Act")
+	v.Check(true, "time", "is required")
+	if err := v.Err(); err != nil {
+		t.Fatalf("Err() with every check passing = %v, want nil", err)
+	}
+	v.Required("name", " \t")
+	v.Check(false, "pay", "must be at least %d", 10)
+	domainErr, ok := domain.As(v.Err())
+	if !ok || !errors.Is(domainErr, domain.ErrInvalid) {
+		t.Fatalf("Err() = %v, want %v", v.Err(), domain.ErrInvalid)
+	}
+	want := []domain.FieldError{{Field: "name", Message: "is required"}, {Field: "pay", Message: "must be at least 10"}}
+	if len(domainErr.Fields) != len(want) || domainErr.Fields[0] != want[0] || domainErr.Fields[1] != want[1] {
+		t.Errorf("fields = %v, want %v", domainErr.Fields, want)
+	}
+}
+
+func TestEnumsRejectUnknownValuesAsFieldErrors(t *testing.T) {
+	tests := []struct {
+		field  string
+		target interface{}
+	}{
+		{field: "type", target: new(ProfileType)},
+		{field: "application_status", target: new(ApplicationStatus)},
+		{field: "application_status", target: new(EventApplicationStatus)},
+		{field: "status", target: new(MembershipStatus)},
+	}
+	for _, test := range tests {
+		err := json.Unmarshal([]byte(`"bogus"`), test.target)
+		domainErr, ok := domain.As(err)
+		if !ok || !errors.Is(err, domain.ErrInvalid) || len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != test.field {
+			t.Errorf("unmarshalling a bogus %T error = %v, want a field error on %s", test.target, err, test.field)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"log"
+	"text/template"
+	"time"
+)
+
+// MaxBulkStatusItems bounds one bulk status change, so its transaction stays short.
+const MaxBulkStatusItems = 500
+
+var ErrNotInEvent = domain.NotFound("application_not_in_event", "the application does not belong to this event")
+
+type statusChange struct {
+	previous models.Application
+	current  models.Application
+}
+
+func validateBulkStatusChange(change models.BulkStatusChange) (*template.Template, error) {
+	var v models.Validator
+	switch change.Status {
+	case models.StatusAccepted, models.StatusRejected, models.StatusPending, models.StatusOffered, models.StatusUnknown,
+		models.StatusWaitlisted:
+	default:
+		v.Add("status", "invalid value %q. Allowed: accepted, rejected, pending, offered, unknown, waitlisted", change.Status)
+	}
+	v.Check(len(change.Applications) > 0, "applications", "must not be empty")
+	v.Check(len(change.Applications) <= MaxBulkStatusItems, "applications", "must have at most %d items", MaxBulkStatusItems)
+	seen := map[uuid.UUID]bool{}
+	for i, item := range change.Applications {
+		v.Check(!seen[item.ID], fmt.Sprintf("applications[%d].id", i), "appears more than once")
+		seen[item.ID] = true
+	}
+	if change.OfferExpiresAt != nil {
+		v.Check(change.Status == models.StatusOffered, "offer_expires_at", "only applies to offers")
+		v.Check(change.OfferExpiresAt.After(time.Now()), "offer_expires_at", "must be in the future")
+	}
+	var message *template.Template
+	if change.Message != "" {
+		var err error
+		message, err = template.New("message").Option("missingkey=error").Parse(change.Message)
+		if err == nil {
+			// A dry run on empty data catches references to fields StatusMessageData does not have.
+			err = message.Execute(io.Discard, models.StatusMessageData{})
+		}
+		if err != nil {
+			v.Add("message", "invalid template: %v", err)
+		}
+	}
+	return message, v.Err()
+}
+
+// ChangeApplicationStatuses moves the listed applications of the event to one status in a single transaction. Each
+// item goes through the same checks as a single update and fails on its own, under a savepoint, without holding back
+// the rest. Stream events, status listeners and the optional message are fanned out once the transaction has
+// committed, in the background.
+func (s *Service) ChangeApplicationStatuses(
+	ctx context.Context, eventID uuid.UUID, change models.BulkStatusChange,
+) (models.BulkStatusReport, error) {
+	message, err := validateBulkStatusChange(change)
+	if err != nil {
+		return models.BulkStatusReport{}, err
+	}
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return models.BulkStatusReport{}, errors.Wrap(err, "db error")
+	}
+
+	var report models.BulkStatusReport
+	var changes []statusChange
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		report, changes = models.BulkStatusReport{Results: make([]models.BulkStatusResult, 0, len(change.Applications))}, nil
+		applications, err := s.repo.GetApplicationsByEvent(ctx, eventID)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		byID := make(map[uuid.UUID]models.Application, len(applications))
+		for _, application := range applications {
+			byID[application.ID] = application
+		}
+		for _, item := range change.Applications {
+			result := models.BulkStatusResult{ID: item.ID}
+			var updated statusChange
+			err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+				updated, err = s.changeStatus(ctx, event, byID, item, change)
+				return err
+			})
+			e, isDomain := domain.As(err)
+			switch {
+			case err != nil && (!isDomain || e.Kind == domain.KindInternal):
+				return err
+			case err != nil:
+				result.Outcome, result.Code, result.Error = models.BulkFailed, e.Code, e.Message
+				report.Failed++
+			case updated.previous.Version == updated.current.Version:
+				result.Outcome, result.Version = models.BulkUnchanged, updated.current.Version
+				report.Unchanged++
+			default:
+				result.Outcome, result.Version = models.BulkUpdated, updated.current.Version
+				report.Updated++
+				changes = append(changes, updated)
+			}
+			report.Results = append(report.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.BulkStatusReport{}, err
+	}
+
+	// The request's context ends with the response; the fan-out keeps only who made the change.
+	background := models.ContextWithActor(context.Background(), models.ActorFromContext(ctx))
+	go s.fanOutStatusChanges(background, event, changes, message)
+	return report, nil
+}
+
+// changeStatus is UpdateApplication for one bulk item, minus what fanOutStatusChanges does afterwards. An offer keeps
+// its status but takes the change's deadline, if it sets one.
+func (s *Service) changeStatus(
+	ctx context.Context, event models.Event, byID map[uuid.UUID]models.Application, item models.BulkStatusItem,
+	change models.BulkStatusChange,
+) (statusChange, error) {
+	previous, ok := byID[item.ID]
+	if !ok {
+		return statusChange{}, ErrNotInEvent
+	}
+	if item.Version != 0 && item.Version != previous.Version {
+		return statusChange{}, models.ErrVersionConflict
+	}
+	if previous.Status == change.Status &&
+		(change.OfferExpiresAt == nil || sameTime(previous.OfferExpiresAt, change.OfferExpiresAt)) {
+		return statusChange{previous: previous, current: previous}, nil
+	}
+	if err := checkStatusMove(PartyProducer, previous.Status, change.Status); err != nil {
+		return statusChange{}, err
+	}
+	// The performer was only loaded to render messages; it is left out of the write.
+	application := previous
+	application.Status, application.Performer = change.Status, models.Profile{}
+	if change.OfferExpiresAt != nil {
+		application.OfferExpiresAt = change.OfferExpiresAt
+	}
+	if err := validateApplication(application); err != nil {
+		return statusChange{}, err
+	}
+	if err := s.settleStatus(ctx, event, previous, &application); err != nil {
+		return statusChange{}, err
+	}
+	out, err := s.repo.UpdateApplication(ctx, application)
+	if err != nil {
+		return statusChange{}, errors.Wrap(err, "db error")
+	}
+	out.Performer = previous.Performer
+	if err := s.audit(ctx, "application.update", "application", out.ID.String(), previous, out, out.PerformerID, event.ProducerID); err != nil {
+		return statusChange{}, err
+	}
+	return statusChange{previous: previous, current: out}, nil
+}
+
+func (s *Service) fanOutStatusChanges(
+	ctx context.Context, event models.Event, changes []statusChange, message *template.Template,
+) {
+	var sender string
+	if actor := models.ActorFromContext(ctx); actor.Type == models.ActorFirebase {
+		sender = actor.ID
+	}
+	for _, change := range changes {
+		if change.previous.Status != change.current.Status {
+			s.statusChanged(ctx, change.previous, change.current)
+		}
+		if message == nil {
+			continue
+		}
+		var body bytes.Buffer
+		if err := message.Execute(&body, models.StatusMessageData{
+			Event:          models.NewTemplateEvent(event),
+			Performer:      models.NewTemplateProfile(change.current.Performer),
+			Application:    models.NewTemplateApplication(change.current),
+			PreviousStatus: change.previous.Status,
+		}); err != nil {
+			log.Printf("unable to render the status message for application %s: %v", change.current.ID, err)
+			continue
+		}
+		if _, err := s.messenger.Post(ctx, event, change.current, models.Message{
+			ApplicationID: change.current.ID, AuthorID: event.ProducerID, SenderUID: sender, Body: body.String(),
+		}); err != nil {
+			log.Printf("unable to send the status message for application %s: %v", change.current.ID, err)
+		}
+	}
+}
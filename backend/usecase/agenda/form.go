@@ -0,0 +1,189 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxQuestions    = 50
+	MaxAnswerLength = 5000
+)
+
+var (
+	ErrNoForm     = domain.NotFound("form_not_found", "the event has no such application form")
+	ErrFormLocked = domain.Conflict("form_locked", "the application form can only be edited while the event is a draft")
+)
+
+var questionIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+func validateQuestions(questions models.Questions) error {
+	var v models.Validator
+	v.Check(len(questions) <= MaxQuestions, "questions", "must have at most %d questions", MaxQuestions)
+	seen := map[string]bool{}
+	for i, question := range questions {
+		field := fmt.Sprintf("questions[%d]", i)
+		v.Check(questionIDPattern.MatchString(question.ID), field+".id",
+			"must be lowercase letters, digits and underscores, starting with a letter, at most 40 long")
+		v.Check(!seen[question.ID], field+".id", "appears more than once")
+		seen[question.ID] = true
+		v.Required(field+".label", question.Label)
+		switch question.Type {
+		case models.QuestionText, models.QuestionChoice, models.QuestionURL, models.QuestionFile, models.QuestionNumber:
+		default:
+			v.Add(field+".type", "invalid value %q. Allowed: text, choice, url, file, number", question.Type)
+		}
+		if question.Type != models.QuestionChoice {
+			v.Check(len(question.Options) == 0, field+".options", "only apply to choice questions")
+			v.Check(!question.Multiple, field+".multiple", "only applies to choice questions")
+			continue
+		}
+		v.Check(len(question.Options) > 0, field+".options", "must not be empty")
+		options := map[string]bool{}
+		for j, option := range question.Options {
+			v.Required(fmt.Sprintf("%s.options[%d]", field, j), option)
+			v.Check(!options[option], fmt.Sprintf("%s.options[%d]", field, j), "appears more than once")
+			options[option] = true
+		}
+	}
+	return v.Err()
+}
+
+func validateLink(v *models.Validator, field string, link string) {
+	u, err := url.Parse(link)
+	v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", field, "must be an http or https URL")
+}
+
+func contains(options []string, s string) bool {
+	for _, option := range options {
+		if option == s {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAnswers checks the answers against the form, naming each problem answers.<question id>.
+func validateAnswers(form models.ApplicationForm, answers models.Answers) error {
+	var v models.Validator
+	questions := make(map[string]bool, len(form.Questions))
+	for _, question := range form.Questions {
+		questions[question.ID] = true
+		field := "answers." + question.ID
+		raw, ok := answers[question.ID]
+		if !ok || string(raw) == "null" {
+			v.Check(!question.Required, field, "is required")
+			continue
+		}
+		switch question.Type {
+		case models.QuestionNumber:
+			var n float64
+			v.Check(json.Unmarshal(raw, &n) == nil, field, "must be a number")
+		case models.QuestionChoice:
+			var choices []string
+			if question.Multiple {
+				if json.Unmarshal(raw, &choices) != nil {
+					v.Add(field, "must be an array of options")
+					continue
+				}
+				v.Check(!question.Required || len(choices) > 0, field, "is required")
+			} else {
+				var choice string
+				if json.Unmarshal(raw, &choice) != nil {
+					v.Add(field, "must be one of the options")
+					continue
+				}
+				choices = []string{choice}
+			}
+			picked := map[string]bool{}
+			for _, choice := range choices {
+				v.Check(contains(question.Options, choice), field, "%q is not one of the options", choice)
+				v.Check(!picked[choice], field, "%q is picked more than once", choice)
+				picked[choice] = true
+			}
+		default:
+			var s string
+			if json.Unmarshal(raw, &s) != nil {
+				v.Add(field, "must be a string")
+				continue
+			}
+			if strings.TrimSpace(s) == "" {
+				v.Check(!question.Required, field, "is required")
+				continue
+			}
+			v.Check(utf8.RuneCountInString(s) <= MaxAnswerLength, field, "must be at most %d characters", MaxAnswerLength)
+			if question.Type == models.QuestionURL || question.Type == models.QuestionFile {
+				validateLink(&v, field, s)
+			}
+		}
+	}
+	for id := range answers {
+		v.Check(questions[id], "answers."+id, "is not a question of this form")
+	}
+	return v.Err()
+}
+
+// SetForm replaces the questions of the event's application form, which only a draft event allows. The current
+// version is edited in place while no application has answered it; otherwise the questions become a new version and
+// the applications already made keep theirs.
+func (s *Service) SetForm(ctx context.Context, eventID uuid.UUID, questions models.Questions) (models.ApplicationForm, error) {
+	if err := validateQuestions(questions); err != nil {
+		return models.ApplicationForm{}, err
+	}
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return models.ApplicationForm{}, errors.Wrap(err, "db error")
+	}
+	if event.Status != models.EventDraft {
+		return models.ApplicationForm{}, ErrFormLocked
+	}
+	var previous, out models.ApplicationForm
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		previous, err = s.repo.GetForm(ctx, eventID, 0)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		form := previous
+		if form.ID == uuid.Nil {
+			form = models.ApplicationForm{EventRef: eventID, Version: 1}
+		} else if answered, err := s.repo.CountApplicationsByForm(ctx, eventID, form.Version); err != nil {
+			return errors.Wrap(err, "db error")
+		} else if answered > 0 {
+			form = models.ApplicationForm{EventRef: eventID, Version: previous.Version + 1}
+		}
+		form.Questions = questions
+		if out, err = s.repo.SaveForm(ctx, form); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		var before interface{}
+		if previous.ID != uuid.Nil {
+			before = previous
+		}
+		return s.audit(ctx, "event.form", "event", eventID.String(), before, out, event.ProducerID)
+	})
+	if err != nil {
+		return models.ApplicationForm{}, err
+	}
+	return out, nil
+}
+
+// GetForm returns the given version of the event's application form, or the current one for version 0.
+func (s *Service) GetForm(ctx context.Context, eventID uuid.UUID, version int) (models.ApplicationForm, error) {
+	form, err := s.repo.GetForm(ctx, eventID, version)
+	if err != nil {
+		return form, errors.Wrap(err, "db error")
+	}
+	if form.ID == uuid.Nil {
+		return form, ErrNoForm
+	}
+	return form, nil
+}
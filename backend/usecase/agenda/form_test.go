@@ -0,0 +1,92 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// invalidFields lists the fields err names, sorted, or nil if err is nil.
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrInvalid)
+	}
+	var fields []string
+	for _, field := range domainErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestValidateAnswers(t *testing.T) {
+	form := models.ApplicationForm{Questions: models.Questions{
+		{ID: "bio", Label: "Bio", Type: models.QuestionText, Required: true},
+		{ID: "act", Label: "Act", Type: models.QuestionChoice, Options: []string{"music", "comedy"}},
+		{ID: "gear", Label: "Gear", Type: models.QuestionChoice, Options: []string{"amp", "mic"}, Multiple: true},
+		{ID: "minutes", Label: "Minutes", Type: models.QuestionNumber},
+		{ID: "demo", Label: "Demo", Type: models.QuestionURL},
+		{ID: "rider", Label: "Rider", Type: models.QuestionFile},
+	}}
+	tests := []struct {
+		name    string
+		answers string
+		want    []string
+	}{
+		{name: "only the required answer", answers: `{"bio": "We play"}`},
+		{
+			name: "every answer",
+			answers: `{"bio": "We play", "act": "music", "gear": ["amp", "mic"], "minutes": 20,
+				"demo": "https://example.com/demo", "rider": "http://example.com/rider.pdf"}`,
+		},
+		{name: "null optional answers", answers: `{"bio": "We play", "act": null, "minutes": null}`},
+		{name: "no answers", answers: `{}`, want: []string{"answers.bio"}},
+		{name: "null required answer", answers: `{"bio": null}`, want: []string{"answers.bio"}},
+		{name: "blank required answer", answers: `{"bio": "  "}`, want: []string{"answers.bio"}},
+		{name: "text that is not a string", answers: `{"bio": 3}`, want: []string{"answers.bio"}},
+		{name: "too long", answers: `{"bio": "` + strings.Repeat("é", MaxAnswerLength+1) + `"}`, want: []string{"answers.bio"}},
+		{name: "unknown option", answers: `{"bio": "x", "act": "magic"}`, want: []string{"answers.act"}},
+		{name: "single choice given a list", answers: `{"bio": "x", "act": ["music"]}`, want: []string{"answers.act"}},
+		{name: "multiple choice given a string", answers: `{"bio": "x", "gear": "amp"}`, want: []string{"answers.gear"}},
+		{name: "option picked twice", answers: `{"bio": "x", "gear": ["amp", "amp"]}`, want: []string{"answers.gear"}},
+		{name: "number as a string", answers: `{"bio": "x", "minutes": "20"}`, want: []string{"answers.minutes"}},
+		{name: "link without a scheme", answers: `{"bio": "x", "demo": "example.com"}`, want: []string{"answers.demo"}},
+		{name: "link to a local file", answers: `{"bio": "x", "rider": "file:///rider.pdf"}`, want: []string{"answers.rider"}},
+		{
+			name:    "unknown questions",
+			answers: `{"bio": "x", "color": "red", "age": 3}`,
+			want:    []string{"answers.age", "answers.color"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var answers models.Answers
+			if err := json.Unmarshal([]byte(test.answers), &answers); err != nil {
+				t.Fatalf("bad test answers: %v", err)
+			}
+			if got := invalidFields(t, validateAnswers(form, answers)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("validateAnswers() fields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateAnswersRequiredMultipleChoice(t *testing.T) {
+	form := models.ApplicationForm{Questions: models.Questions{
+		{ID: "gear", Label: "Gear", Type: models.QuestionChoice, Options: []string{"amp"}, Multiple: true, Required: true},
+	}}
+	got := invalidFields(t, validateAnswers(form, models.Answers{"gear": json.RawMessage(`[]`)}))
+	if want := []string{"answers.gear"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("validateAnswers() fields = %v, want %v", got, want)
+	}
+}
@@ -9,21 +9,77 @@ import (
 )
 
 type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	// Nested calls are savepoints: an error from the inner fn rolls back only what it did.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
 	CreateEvent(ctx context.Context, event models.Event) (uuid.UUID, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	// UpdateEvent and UpdateApplication only write if the row is still at the Version passed in, and return it bumped;
+	// otherwise they fail with models.ErrVersionConflict. The deletes check version the same way unless it is 0.
 	UpdateEvent(ctx context.Context, event models.Event) (models.Event, error)
-	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	DeleteEvent(ctx context.Context, id uuid.UUID, version int64) error
 
 	CreateApplication(ctx context.Context, application models.Application) (uuid.UUID, error)
 	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
 	UpdateApplication(ctx context.Context, application models.Application) (models.Application, error)
-	DeleteApplication(ctx context.Context, id uuid.UUID) error
+	DeleteApplication(ctx context.Context, id uuid.UUID, version int64) error
 
 	GetEventsByProducer(ctx context.Context, producerID uuid.UUID) ([]models.Event, error)
+	// GetPublicEvent returns the zero event unless the event is published. Both load the tags, producer and venue;
+	// GetPublicEvents lists soonest first.
+	GetPublicEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetPublicEvents(ctx context.Context, filter models.PublicEventFilter) ([]models.Event, error)
+	// GetProfile is the producer or venue a feed is for.
+	GetProfile(ctx context.Context, id uuid.UUID) (models.Profile, error)
 	GetApplicationsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Application, error)
+	// EachApplicationByEvent streams the applications to fn instead of loading them all. An empty status matches any.
+	EachApplicationByEvent(
+		ctx context.Context, eventID uuid.UUID, status models.ApplicationStatus, fn func(models.Application) error,
+	) error
+	GetScoreSummaries(ctx context.Context, eventID uuid.UUID) (map[uuid.UUID]models.ScoreSummary, error)
+	// GetForm returns the zero form when the event has no such version; version 0 asks for the latest.
+	GetForm(ctx context.Context, eventID uuid.UUID, version int) (models.ApplicationForm, error)
+	SaveForm(ctx context.Context, form models.ApplicationForm) (models.ApplicationForm, error)
+	CountApplicationsByForm(ctx context.Context, eventID uuid.UUID, version int) (int64, error)
+	// GetWaitlist and NextWaitlisted order the waitlist by position, then by submission.
+	GetWaitlist(ctx context.Context, eventID uuid.UUID) ([]models.Application, error)
+	NextWaitlisted(ctx context.Context, eventID uuid.UUID, skip ...uuid.UUID) (models.Application, error)
+	NextWaitlistPosition(ctx context.Context, eventID uuid.UUID) (int, error)
+	// GetCommitments finds the offered and accepted applications around a time that involve the performer, counting
+	// ensemble memberships both ways.
+	GetCommitments(ctx context.Context, performerID uuid.UUID, except uuid.UUID, from time.Time, to time.Time) ([]models.Application, error)
+	GetExpiredOffers(ctx context.Context, now time.Time) ([]models.Application, error)
+	GetOffersToRemind(ctx context.Context, now time.Time, until time.Time) ([]models.Application, error)
+	MarkOfferReminded(ctx context.Context, application models.Application, at time.Time) (bool, error)
 	GetApplicationsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Application, error)
-	GetAllEvents(ctx context.Context, startTime time.Time, endTime time.Time, centerPoint gormGIS.GeoPoint, distanceKM float64) ([]models.Event, error)
+	GetAllEvents(ctx context.Context, startTime time.Time, endTime time.Time, centerPoint gormGIS.GeoPoint, distanceKM float64, minPay *models.MinimumPay) ([]models.Event, error)
+	ListEvents(ctx context.Context) ([]models.Event, error)
 
 	CreateTag(ctx context.Context, tag models.Tag) (uint, error)
 	DeleteTag(ctx context.Context, tag models.Tag) error
+	GetTag(ctx context.Context, id uint) (models.Tag, error)
+	GetTagByName(ctx context.Context, name string) (models.Tag, error)
+	ListTags(ctx context.Context) ([]models.Tag, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event models.StreamEvent) error
+}
+
+// Messenger stores a message the service has authorized itself and tells both sides of the application.
+type Messenger interface {
+	Post(ctx context.Context, event models.Event, application models.Application, message models.Message) (models.Message, error)
+}
+
+// ApplicationStatusListener is told about every application status change once it has been persisted.
+type ApplicationStatusListener interface {
+	ApplicationStatusChanged(ctx context.Context, previous models.Application, current models.Application) error
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
 }
@@ -0,0 +1,106 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+)
+
+// DefaultOfferReminder is how long before its deadline an offered performer is reminded, unless configured.
+const DefaultOfferReminder = 24 * time.Hour
+
+var (
+	ErrOfferExpired = domain.Conflict("offer_expired", "the offer has expired and the application can no longer change")
+	ErrNotExpired   = domain.Conflict("offer_not_expired", "only an offer past its deadline can expire")
+)
+
+// ExpireOffers moves every offer past its deadline to expired, which passes the place on to the waitlist like a
+// decline, and returns how many it expired. Offers that fail, or that changed in the meantime, are logged and retried
+// on the next run.
+func (s *Service) ExpireOffers(ctx context.Context) (int, error) {
+	ctx = models.ContextWithActor(ctx, models.Actor{Type: models.ActorSystem})
+	offers, err := s.repo.GetExpiredOffers(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, errors.Wrap(err, "db error")
+	}
+	expired := 0
+	for _, previous := range offers {
+		application := previous
+		application.Status = models.StatusExpired
+		var out models.Application
+		err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+			if err = s.settleStatus(ctx, models.Event{}, previous, &application); err != nil {
+				return err
+			}
+			if out, err = s.repo.UpdateApplication(ctx, application); err != nil {
+				return errors.Wrap(err, "db error")
+			}
+			return s.audit(ctx, "application.expire", "application", out.ID.String(), previous, out, s.applicationAudience(ctx, out)...)
+		})
+		if err != nil {
+			log.Printf("unable to expire the offer of application %s: %v", previous.ID, err)
+			continue
+		}
+		s.statusChanged(ctx, previous, out)
+		expired++
+	}
+	return expired, nil
+}
+
+// RemindOffers tells the performers whose offers expire within the next window that they have yet to answer, once per
+// deadline, and returns how many it reminded.
+func (s *Service) RemindOffers(ctx context.Context, window time.Duration) (int, error) {
+	now := time.Now().UTC()
+	offers, err := s.repo.GetOffersToRemind(ctx, now, now.Add(window))
+	if err != nil {
+		return 0, errors.Wrap(err, "db error")
+	}
+	reminded := 0
+	for _, application := range offers {
+		// Marking first means a crash can lose a reminder but never send one twice.
+		marked, err := s.repo.MarkOfferReminded(ctx, application, now)
+		if err != nil {
+			log.Printf("unable to remind application %s of its offer: %v", application.ID, err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+		s.publish(ctx, models.StreamOfferReminder, application.ID,
+			map[string]interface{}{
+				"id": application.ID, "event_ref": application.EventRef, "offer_expires_at": application.OfferExpiresAt,
+			},
+			application.PerformerID,
+		)
+		reminded++
+	}
+	return reminded, nil
+}
+
+// RunOfferDeadlines expires stale offers and sends reminders every interval until ctx is done. Performers are
+// reminded the given duration before their deadline, DefaultOfferReminder if it is not positive.
+func (s *Service) RunOfferDeadlines(ctx context.Context, interval time.Duration, reminder time.Duration) {
+	if reminder <= 0 {
+		reminder = DefaultOfferReminder
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if expired, err := s.ExpireOffers(ctx); err != nil {
+			log.Printf("offer expiry failed: %v", err)
+		} else if expired > 0 {
+			log.Printf("offer expiry expired %d offers", expired)
+		}
+		if _, err := s.RemindOffers(ctx, reminder); err != nil {
+			log.Printf("offer reminders failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package agenda
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func (r *fakeRepo) GetExpiredOffers(_ context.Context, now time.Time) ([]models.Application, error) {
+	var offers []models.Application
+	for _, application := range r.applications {
+		if application.Status == models.StatusOffered && application.OfferExpiresAt != nil && !application.OfferExpiresAt.After(now) {
+			offers = append(offers, application)
+		}
+	}
+	return offers, nil
+}
+
+func (r *fakeRepo) GetOffersToRemind(_ context.Context, now time.Time, until time.Time) ([]models.Application, error) {
+	var offers []models.Application
+	for _, application := range r.applications {
+		if application.Status == models.StatusOffered && application.OfferRemindedAt == nil && application.OfferExpiresAt != nil &&
+			application.OfferExpiresAt.After(now) && !application.OfferExpiresAt.After(until) {
+			offers = append(offers, application)
+		}
+	}
+	return offers, nil
+}
+
+func (r *fakeRepo) MarkOfferReminded(_ context.Context, application models.Application, at time.Time) (bool, error) {
+	stored := r.applications[application.ID]
+	if stored.Version != application.Version || stored.OfferRemindedAt != nil {
+		return false, nil
+	}
+	stored.OfferRemindedAt = &at
+	r.applications[application.ID] = stored
+	return true, nil
+}
+
+// offer moves the application to offered with the given deadline, relative to now.
+func offer(repo *fakeRepo, application models.Application, in time.Duration) models.Application {
+	deadline := time.Now().UTC().Add(in)
+	application.Status, application.OfferExpiresAt = models.StatusOffered, &deadline
+	repo.applications[application.ID] = application
+	return application
+}
+
+func TestExpireOffers(t *testing.T) {
+	repo, stale, waitlist := newWaitlist(models.EventOpen, 1)
+	stale = offer(repo, stale, -time.Minute)
+	pending := offer(repo, models.Application{
+		Model: models.Model{ID: uuid.New()}, EventRef: repo.event.ID, PerformerID: uuid.New(), Version: 1,
+	}, time.Hour)
+	service := NewService(repo, &fakePublisher{}, fakeAuditor{}, nil)
+
+	expired, err := service.ExpireOffers(context.Background())
+	if err != nil {
+		t.Fatalf("ExpireOffers() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("ExpireOffers() = %d, want 1", expired)
+	}
+	if stored := repo.applications[stale.ID]; stored.Status != models.StatusExpired || stored.OfferExpiresAt == nil {
+		t.Errorf("the stale offer is %s with deadline %v, want expired keeping its deadline", stored.Status, stored.OfferExpiresAt)
+	}
+	if stored := repo.applications[pending.ID]; stored.Status != models.StatusOffered {
+		t.Errorf("an offer inside its deadline is now %s", stored.Status)
+	}
+	if stored := repo.applications[waitlist[0].ID]; stored.Status != models.StatusOffered {
+		t.Errorf("the place of the expired offer did not pass to the waitlist: %s", stored.Status)
+	}
+
+	again := repo.applications[stale.ID]
+	again.Status = models.StatusAccepted
+	if _, err := service.UpdateApplication(context.Background(), PartyProducer, again); !errors.Is(err, ErrOfferExpired) {
+		t.Errorf("accepting an expired offer error = %v, want %v", err, ErrOfferExpired)
+	}
+}
+
+func TestRemindOffers(t *testing.T) {
+	repo, soon, _ := newWaitlist(models.EventOpen, 0)
+	soon = offer(repo, soon, time.Hour)
+	later := offer(repo, models.Application{
+		Model: models.Model{ID: uuid.New()}, EventRef: repo.event.ID, PerformerID: uuid.New(), Version: 1,
+	}, 3*DefaultOfferReminder)
+	publisher := &fakePublisher{}
+	service := NewService(repo, publisher, fakeAuditor{}, nil)
+
+	for run, want := range []int{1, 0} {
+		reminded, err := service.RemindOffers(context.Background(), DefaultOfferReminder)
+		if err != nil {
+			t.Fatalf("RemindOffers() error = %v", err)
+		}
+		if reminded != want {
+			t.Errorf("run %d reminded %d performers, want %d", run+1, reminded, want)
+		}
+	}
+	if repo.applications[soon.ID].OfferRemindedAt == nil || repo.applications[later.ID].OfferRemindedAt != nil {
+		t.Errorf("reminded the wrong offers")
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != models.StreamOfferReminder ||
+		len(publisher.events[0].Audience) != 1 || publisher.events[0].Audience[0] != soon.PerformerID {
+		t.Errorf("published %+v, want one reminder to the performer", publisher.events)
+	}
+
+	// A new deadline earns a new reminder.
+	moved := repo.applications[soon.ID]
+	deadline := time.Now().UTC().Add(2 * time.Hour)
+	moved.OfferExpiresAt = &deadline
+	if _, err := service.UpdateApplication(context.Background(), PartyProducer, moved); err != nil {
+		t.Fatalf("UpdateApplication() error = %v", err)
+	}
+	if repo.applications[soon.ID].OfferRemindedAt != nil {
+		t.Errorf("moving the deadline kept the old reminder")
+	}
+}
+
+func TestSettleStatusOfferDeadline(t *testing.T) {
+	repo, _, _ := newWaitlist(models.EventOpen, 0)
+	service := NewService(repo, &fakePublisher{}, fakeAuditor{}, nil)
+	future := time.Now().UTC().Add(time.Hour)
+	past := time.Now().UTC().Add(-time.Hour)
+	tests := []struct {
+		name     string
+		previous models.Application
+		status   models.ApplicationStatus
+		deadline *time.Time
+		want     func(*models.Application) bool
+		wantErr  error
+	}{
+		{
+			name: "offer gets the event's window", previous: models.Application{Status: models.StatusPending},
+			status: models.StatusOffered,
+			want: func(a *models.Application) bool {
+				return a.OfferExpiresAt != nil && a.OfferExpiresAt.Sub(time.Now()) > 23*time.Hour
+			},
+		},
+		{
+			name: "explicit deadline wins", previous: models.Application{Status: models.StatusPending},
+			status: models.StatusOffered, deadline: &future,
+			want: func(a *models.Application) bool { return a.OfferExpiresAt.Equal(future) },
+		},
+		{
+			name: "accepting clears the deadline", previous: models.Application{Status: models.StatusOffered, OfferExpiresAt: &future},
+			status: models.StatusAccepted, deadline: &future,
+			want: func(a *models.Application) bool { return a.OfferExpiresAt == nil },
+		},
+		{
+			name: "expiring early", previous: models.Application{Status: models.StatusOffered, OfferExpiresAt: &future},
+			status: models.StatusExpired, wantErr: ErrNotExpired,
+		},
+		{
+			name: "expiring an open-ended offer", previous: models.Application{Status: models.StatusOffered},
+			status: models.StatusExpired, wantErr: ErrNotExpired,
+		},
+		{
+			name: "expiring past the deadline", previous: models.Application{Status: models.StatusOffered, OfferExpiresAt: &past},
+			status: models.StatusExpired, deadline: &past,
+			want: func(a *models.Application) bool { return a.OfferExpiresAt.Equal(past) },
+		},
+		{
+			name: "leaving expired", previous: models.Application{Status: models.StatusExpired, OfferExpiresAt: &past},
+			status: models.StatusPending, wantErr: ErrOfferExpired,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			application := test.previous
+			application.Status, application.OfferExpiresAt = test.status, test.deadline
+			err := service.settleStatus(context.Background(), repo.event, test.previous, &application)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("settleStatus() error = %v, want %v", err, test.wantErr)
+			}
+			if test.want != nil && !test.want(&application) {
+				t.Errorf("settleStatus() left the deadline at %v", application.OfferExpiresAt)
+			}
+		})
+	}
+}
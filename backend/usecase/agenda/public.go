@@ -0,0 +1,109 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// DefaultPublicEvents is the page size of the public listing when none is asked for.
+const DefaultPublicEvents = 50
+
+var ErrNotPublished = domain.NotFound("event_not_published", "no published event has this ID")
+
+// GetPublicEvent returns the published event, redacted for the public. Drafts and cancelled events look the same as
+// events that do not exist.
+func (s *Service) GetPublicEvent(ctx context.Context, id uuid.UUID) (models.PublicEvent, error) {
+	event, err := s.repo.GetPublicEvent(ctx, id)
+	if err != nil {
+		return models.PublicEvent{}, errors.Wrap(err, "db error")
+	}
+	if event.ID == uuid.Nil {
+		return models.PublicEvent{}, ErrNotPublished
+	}
+	return models.NewPublicEvent(event), nil
+}
+
+// GetPublicEvents lists the published events the filter matches, soonest first and redacted for the public.
+func (s *Service) GetPublicEvents(ctx context.Context, filter models.PublicEventFilter) ([]models.PublicEvent, error) {
+	var v models.Validator
+	v.Check(filter.To == nil || filter.To.After(filter.From), "to", "must be after from")
+	v.Check(filter.Limit >= 0 && filter.Limit <= models.MaxPublicEvents, "limit", "must be between 1 and %d",
+		models.MaxPublicEvents)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPublicEvents
+	}
+	events, err := s.repo.GetPublicEvents(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	out := make([]models.PublicEvent, len(events))
+	for i, event := range events {
+		out[i] = models.NewPublicEvent(event)
+	}
+	return out, nil
+}
+
+var ErrNoFeed = domain.NotFound("feed_not_found", "no producer or venue has this feed")
+
+// feed lists the upcoming events still taking applications that the filter matches, as many as one public page holds.
+func (s *Service) feed(
+	ctx context.Context, title string, description string, updated time.Time, filter models.PublicEventFilter,
+) (models.Feed, error) {
+	filter.From, filter.AcceptingApplications, filter.Limit = time.Now().UTC(), true, models.MaxPublicEvents
+	events, err := s.GetPublicEvents(ctx, filter)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	for _, event := range events {
+		if event.UpdatedAt.After(updated) {
+			updated = event.UpdatedAt
+		}
+	}
+	return models.Feed{Title: title, Description: description, Updated: updated, Events: events}, nil
+}
+
+// profileFeed is the feed of a producer's events or of the events at a venue; id has to be a profile of that type.
+func (s *Service) profileFeed(ctx context.Context, id uuid.UUID, profileType models.ProfileType) (models.Feed, error) {
+	profile, err := s.repo.GetProfile(ctx, id)
+	if err != nil {
+		return models.Feed{}, errors.Wrap(err, "db error")
+	}
+	if profile.ProfileType != profileType {
+		return models.Feed{}, ErrNoFeed
+	}
+	filter := models.PublicEventFilter{ProducerID: &profile.ID}
+	description := "Upcoming events by " + profile.Name + " that are open for applications."
+	if profileType == models.VenueType {
+		filter = models.PublicEventFilter{VenueID: &profile.ID}
+		description = "Upcoming events at " + profile.Name + " that are open for applications."
+	}
+	return s.feed(ctx, profile.Name+": calls for performers", description, profile.UpdatedAt, filter)
+}
+
+// GetProducerFeed lists the producer's upcoming events that are still taking applications.
+func (s *Service) GetProducerFeed(ctx context.Context, producerID uuid.UUID) (models.Feed, error) {
+	return s.profileFeed(ctx, producerID, models.ProducerType)
+}
+
+// GetVenueFeed lists the upcoming events at the venue that are still taking applications.
+func (s *Service) GetVenueFeed(ctx context.Context, venueID uuid.UUID) (models.Feed, error) {
+	return s.profileFeed(ctx, venueID, models.VenueType)
+}
+
+// GetTagFeed lists the upcoming events with the tag that are still taking applications.
+func (s *Service) GetTagFeed(ctx context.Context, name string) (models.Feed, error) {
+	tag, err := s.repo.GetTagByName(ctx, name)
+	if err != nil {
+		return models.Feed{}, errors.Wrap(err, "db error")
+	}
+	return s.feed(ctx, "Calls for performers: "+tag.Name,
+		"Upcoming events tagged "+tag.Name+" that are open for applications.", tag.UpdatedAt,
+		models.PublicEventFilter{Tag: tag.Name})
+}
@@ -6,21 +6,89 @@ import (
 	"github.com/google/uuid"
 	"github.com/nferruzzi/gormGIS"
 	"github.com/pkg/errors"
+	"log"
+	"sort"
 	"time"
 )
 
+// Fields a merge patch may not change. Ownership moves through neither the nested producer nor the applications.
+var (
+	eventImmutable       = []string{"id", "created_at", "updated_at", "deleted_at", "version", "Producer", "Applications"}
+	applicationImmutable = []string{"id", "created_at", "updated_at", "deleted_at", "version", "EventRef", "Performer", "form_version", "answers",
+		"waitlist_position", "offer_expires_at", "offer_reminded_at",
+	}
+)
+
 type Service struct {
-	repo Repository
+	repo      Repository
+	publisher Publisher
+	auditor   Auditor
+	messenger Messenger
+	listeners []ApplicationStatusListener
+}
+
+func NewService(
+	repository Repository, publisher Publisher, auditor Auditor, messenger Messenger, listeners ...ApplicationStatusListener,
+) Service {
+	return Service{repo: repository, publisher: publisher, auditor: auditor, messenger: messenger, listeners: listeners}
+}
+
+// audit records the change with the context's transaction, so a change that cannot be audited is not made either.
+func (s *Service) audit(
+	ctx context.Context, action string, resourceType string, resourceID string,
+	before interface{}, after interface{}, profileIDs ...uuid.UUID,
+) error {
+	if err := s.auditor.Record(ctx, action, resourceType, resourceID, before, after, profileIDs...); err != nil {
+		return errors.Wrapf(err, "unable to audit %s on %s %s", action, resourceType, resourceID)
+	}
+	return nil
 }
 
-func NewService(repository Repository) Service {
-	return Service{repository}
+// publish is best effort: a failed notification never fails the write that caused it.
+func (s *Service) publish(ctx context.Context, eventType models.StreamEventType, id uuid.UUID, payload interface{}, audience ...uuid.UUID) {
+	event, err := models.NewStreamEvent(eventType, id, payload, audience...)
+	if err != nil {
+		log.Printf("unable to build %s stream event: %v", eventType, err)
+		return
+	}
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("unable to publish %s stream event: %v", eventType, err)
+	}
+}
+
+func (s *Service) applicationAudience(ctx context.Context, application models.Application) []uuid.UUID {
+	audience := []uuid.UUID{application.PerformerID}
+	if event, err := s.repo.GetEvent(ctx, application.EventRef); err == nil {
+		audience = append(audience, event.ProducerID)
+	}
+	return audience
+}
+
+func (s *Service) eventAudience(ctx context.Context, event models.Event) []uuid.UUID {
+	audience := []uuid.UUID{event.ProducerID}
+	if applications, err := s.repo.GetApplicationsByEvent(ctx, event.ID); err == nil {
+		for _, application := range applications {
+			audience = append(audience, application.PerformerID)
+		}
+	}
+	return audience
 }
 
 func (s *Service) CreateEvent(ctx context.Context, event models.Event) (uuid.UUID, error) {
-	id, err := s.repo.CreateEvent(ctx, event)
+	event.Pay.Normalize()
+	if err := ValidateEvent(event); err != nil {
+		return uuid.Nil, err
+	}
+	var id uuid.UUID
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.repo.CreateEvent(ctx, event); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		event.ID = id
+		return s.audit(ctx, "event.create", "event", id.String(), nil, event, event.ProducerID)
+	})
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "db error")
+		return uuid.Nil, err
 	}
 	return id, nil
 }
@@ -33,24 +101,91 @@ func (s *Service) GetEvent(ctx context.Context, id uuid.UUID) (models.Event, err
 	return event, nil
 }
 func (s *Service) UpdateEvent(ctx context.Context, event models.Event) (models.Event, error) {
-	if out, err := s.repo.UpdateEvent(ctx, event); err != nil {
-		return event, errors.Wrap(err, "db error")
-	} else {
-		return out, nil
+	event.Pay.Normalize()
+	if err := ValidateEvent(event); err != nil {
+		return event, err
 	}
-
+	previous, prevErr := s.repo.GetEvent(ctx, event.ID)
+	var out models.Event
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpdateEvent(ctx, event); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		var before interface{}
+		if prevErr == nil {
+			before = previous
+		}
+		return s.audit(ctx, "event.update", "event", out.ID.String(), before, out, previous.ProducerID, out.ProducerID)
+	})
+	if err != nil {
+		return event, err
+	}
+	if prevErr == nil && previous.Status != out.Status {
+		s.publish(ctx, models.StreamEventStatusChanged, out.ID,
+			map[string]interface{}{"id": out.ID, "previous_status": previous.Status, "status": out.Status},
+			s.eventAudience(ctx, out)...,
+		)
+	}
+	return out, nil
 }
-func (s *Service) DeleteEvent(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteEvent(ctx, id); err != nil {
-		return errors.Wrap(err, "db error")
+
+// PatchEvent applies an RFC 7396 merge patch to the event with the given ID. A non-zero version must match the
+// stored one.
+func (s *Service) PatchEvent(ctx context.Context, id uuid.UUID, patch []byte, version int64) (models.Event, error) {
+	event, err := s.repo.GetEvent(ctx, id)
+	if err != nil {
+		return event, errors.Wrap(err, "db error")
 	}
-	return nil
+	if version != 0 && version != event.Version {
+		return event, models.ErrVersionConflict
+	}
+	if err := models.ApplyMergePatch(&event, patch, eventImmutable...); err != nil {
+		return event, err
+	}
+	return s.UpdateEvent(ctx, event)
 }
+
+// DeleteEvent soft-deletes the event; a non-zero version must match the stored one.
+func (s *Service) DeleteEvent(ctx context.Context, id uuid.UUID, version int64) error {
+	previous, prevErr := s.repo.GetEvent(ctx, id)
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteEvent(ctx, id, version); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		if prevErr != nil {
+			return s.audit(ctx, "event.delete", "event", id.String(), nil, nil)
+		}
+		return s.audit(ctx, "event.delete", "event", id.String(), previous, nil, previous.ProducerID)
+	})
+}
+
+// CreateApplication checks the answers against the event's current application form and records which version of it
+// they answer. Applications to an event without a form carry no answers.
 func (s *Service) CreateApplication(ctx context.Context, application models.Application) (uuid.UUID, error) {
-	id, err := s.repo.CreateApplication(ctx, application)
+	if err := validateApplication(application); err != nil {
+		return uuid.Nil, err
+	}
+	form, err := s.repo.GetForm(ctx, application.EventRef, 0)
 	if err != nil {
 		return uuid.Nil, errors.Wrap(err, "db error")
 	}
+	if err := validateAnswers(form, application.Answers); err != nil {
+		return uuid.Nil, err
+	}
+	application.FormVersion = form.Version
+	var id uuid.UUID
+	audience := s.applicationAudience(ctx, application)
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.repo.CreateApplication(ctx, application); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		application.ID = id
+		return s.audit(ctx, "application.create", "application", id.String(), nil, application, audience...)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	s.publish(ctx, models.StreamApplicationCreated, id, application, audience...)
 	return id, nil
 }
 func (s *Service) GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error) {
@@ -60,18 +195,94 @@ func (s *Service) GetApplication(ctx context.Context, id uuid.UUID) (models.Appl
 	}
 	return application, nil
 }
-func (s *Service) UpdateApplication(ctx context.Context, application models.Application) (models.Application, error) {
-	if out, err := s.repo.UpdateApplication(ctx, application); err != nil {
+
+// UpdateApplication writes the application on behalf of the given party, which may only make the status changes
+// statusMoves allows it.
+func (s *Service) UpdateApplication(
+	ctx context.Context, party Party, application models.Application,
+) (models.Application, error) {
+	if err := validateApplication(application); err != nil {
+		return application, err
+	}
+	previous, err := s.repo.GetApplication(ctx, application.ID)
+	if err != nil {
 		return application, errors.Wrap(err, "db error")
-	} else {
-		return out, nil
 	}
+	if err := checkStatusMove(party, previous.Status, application.Status); err != nil {
+		return application, err
+	}
+	var event models.Event
+	if previous.Status != application.Status {
+		if event, err = s.repo.GetEvent(ctx, application.EventRef); err != nil {
+			return application, errors.Wrap(err, "db error")
+		}
+	}
+	if err := s.settleStatus(ctx, event, previous, &application); err != nil {
+		return application, err
+	}
+	var out models.Application
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpdateApplication(ctx, application); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "application.update", "application", out.ID.String(), previous, out, s.applicationAudience(ctx, out)...)
+	})
+	if err != nil {
+		return application, err
+	}
+	if previous.Status != out.Status {
+		s.statusChanged(ctx, previous, out)
+	}
+	return out, nil
 }
-func (s *Service) DeleteApplication(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteApplication(ctx, id); err != nil {
-		return errors.Wrap(err, "db error")
+
+// statusChanged tells the stream and the listeners about a status change that has been persisted, and hands a declined
+// or expired offer on to the waitlist.
+func (s *Service) statusChanged(ctx context.Context, previous models.Application, current models.Application) {
+	s.publish(ctx, models.StreamApplicationStatusChanged, current.ID,
+		map[string]interface{}{"id": current.ID, "event_ref": current.EventRef, "previous_status": previous.Status, "status": current.Status},
+		s.applicationAudience(ctx, current)...,
+	)
+	for _, listener := range s.listeners {
+		if err := listener.ApplicationStatusChanged(ctx, previous, current); err != nil {
+			log.Printf("application %s status listener: %v", current.ID, err)
+		}
+	}
+	if releasesOffer(previous, current) {
+		s.promoteNext(ctx, current.EventRef)
 	}
-	return nil
+}
+
+// PatchApplication applies an RFC 7396 merge patch to the application with the given ID on behalf of the party, like
+// UpdateApplication. A non-zero version must match the stored one.
+func (s *Service) PatchApplication(
+	ctx context.Context, party Party, id uuid.UUID, patch []byte, version int64,
+) (models.Application, error) {
+	application, err := s.repo.GetApplication(ctx, id)
+	if err != nil {
+		return application, errors.Wrap(err, "db error")
+	}
+	if version != 0 && version != application.Version {
+		return application, models.ErrVersionConflict
+	}
+	if err := models.ApplyMergePatch(&application, patch, applicationImmutable...); err != nil {
+		return application, err
+	}
+	return s.UpdateApplication(ctx, party, application)
+}
+
+// DeleteApplication soft-deletes the application; a non-zero version must match the stored one.
+func (s *Service) DeleteApplication(ctx context.Context, id uuid.UUID, version int64) error {
+	previous, prevErr := s.repo.GetApplication(ctx, id)
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteApplication(ctx, id, version); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		if prevErr != nil {
+			return s.audit(ctx, "application.delete", "application", id.String(), nil, nil)
+		}
+		return s.audit(ctx, "application.delete", "application", id.String(), previous, nil, s.applicationAudience(ctx, previous)...)
+	})
 }
 func (s *Service) GetEventsByProducer(ctx context.Context, producerID uuid.UUID) ([]models.Event, error) {
 	events, err := s.repo.GetEventsByProducer(ctx, producerID)
@@ -89,6 +300,46 @@ func (s *Service) GetApplicationsByEvent(ctx context.Context, eventID uuid.UUID)
 	return applications, nil
 }
 
+// RankApplicationsByEvent returns the event's applications with their review scores, best average first and
+// unscored ones last in submission order. Scores are for the producer only; nothing a performer reads carries them.
+func (s *Service) RankApplicationsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Application, error) {
+	applications, err := s.repo.GetApplicationsByEvent(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	summaries, err := s.repo.GetScoreSummaries(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	for i := range applications {
+		if summary, ok := summaries[applications[i].ID]; ok {
+			applications[i].Score = &summary
+		}
+	}
+	sort.SliceStable(applications, func(i, j int) bool {
+		a, b := applications[i], applications[j]
+		switch {
+		case a.Score == nil || b.Score == nil:
+			if a.Score != nil || b.Score != nil {
+				return a.Score != nil
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		case a.Score.Average != b.Score.Average:
+			return a.Score.Average > b.Score.Average
+		default:
+			return a.Score.Reviews > b.Score.Reviews
+		}
+	})
+	return applications, nil
+}
+
+// EachApplicationByEvent hands the event's applications with the given status, or all of them if status is empty, to
+// fn one at a time. An error from fn stops the walk and is returned as is.
+func (s *Service) EachApplicationByEvent(
+	ctx context.Context, eventID uuid.UUID, status models.ApplicationStatus, fn func(models.Application) error,
+) error {
+	return s.repo.EachApplicationByEvent(ctx, eventID, status, fn)
+}
 func (s *Service) GetApplicationsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Application, error) {
 	applications, err := s.repo.GetApplicationsByPerformer(ctx, performerID)
 	if err != nil {
@@ -98,9 +349,9 @@ func (s *Service) GetApplicationsByPerformer(ctx context.Context, performerID uu
 }
 func (s *Service) GetAllEvents(
 	ctx context.Context, startTime time.Time, endTime time.Time,
-	centerPoint gormGIS.GeoPoint, distanceKM float64,
+	centerPoint gormGIS.GeoPoint, distanceKM float64, minPay *models.MinimumPay,
 ) ([]models.Event, error) {
-	events, err := s.repo.GetAllEvents(ctx, startTime, endTime, centerPoint, distanceKM)
+	events, err := s.repo.GetAllEvents(ctx, startTime, endTime, centerPoint, distanceKM, minPay)
 	if err != nil {
 		return nil, errors.Wrap(err, "db error")
 	}
@@ -108,15 +359,58 @@ func (s *Service) GetAllEvents(
 }
 
 func (s *Service) CreateTag(ctx context.Context, tag models.Tag) (uint, error) {
-	id, err := s.repo.CreateTag(ctx, tag)
+	var id uint
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.repo.CreateTag(ctx, tag); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		tag.ID = id
+		return s.audit(ctx, "tag.create", "tag", tag.Name, nil, tag)
+	})
 	if err != nil {
-		return 0, errors.Wrap(err, "db error")
+		return 0, err
 	}
 	return id, nil
 }
 func (s *Service) DeleteTag(ctx context.Context, tag models.Tag) error {
-	if err := s.repo.DeleteTag(ctx, tag); err != nil {
-		return errors.Wrap(err, "db error")
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteTag(ctx, tag); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "tag.delete", "tag", tag.Name, tag, nil)
+	})
+}
+func (s *Service) GetTag(ctx context.Context, id uint) (models.Tag, error) {
+	tag, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		return tag, errors.Wrap(err, "db error")
 	}
-	return nil
+	return tag, nil
+}
+func (s *Service) ListTags(ctx context.Context) ([]models.Tag, error) {
+	tags, err := s.repo.ListTags(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return tags, nil
+}
+
+// ListEvents returns every event that is not deleted, oldest first.
+func (s *Service) ListEvents(ctx context.Context) ([]models.Event, error) {
+	events, err := s.repo.ListEvents(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return events, nil
+}
+
+// ForceEventStatus moves the event to status from whatever status it is in. It goes through UpdateEvent, so the
+// change is audited and published like any other.
+func (s *Service) ForceEventStatus(ctx context.Context, id uuid.UUID, status models.EventApplicationStatus) (models.Event, error) {
+	event, err := s.repo.GetEvent(ctx, id)
+	if err != nil {
+		return event, errors.Wrap(err, "db error")
+	}
+	event.Status = status
+	return s.UpdateEvent(ctx, event)
 }
@@ -0,0 +1,49 @@
+package agenda
+
+import (
+	"backend/models"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+)
+
+func validateLocation(v *models.Validator, field string, location gormGIS.GeoPoint) {
+	v.Check(location.Lat >= -90 && location.Lat <= 90, field+".lat", "must be between -90 and 90")
+	v.Check(location.Lng >= -180 && location.Lng <= 180, field+".lng", "must be between -180 and 180")
+}
+
+// ValidateEvent checks an event after its pay structure has been normalized. Imports run it too.
+func ValidateEvent(event models.Event) error {
+	var v models.Validator
+	v.Required("Name", event.Name)
+	v.Check(!event.Time.IsZero(), "Time", "is required")
+	if event.ApplyByTime != nil && !event.Time.IsZero() {
+		v.Check(event.ApplyByTime.Before(event.Time), "apply_by_time", "must be before Time")
+	}
+	switch event.Status {
+	case models.EventDraft, models.EventOpen, models.EventClosed, models.EventCancelled, models.EventUnknown, "":
+	default:
+		v.Add("application_status", "invalid value %q. Allowed: draft, open, closed, cancelled, unknown", event.Status)
+	}
+	v.Check(event.OfferResponseHours >= 0 && event.OfferResponseHours <= MaxOfferResponseHours, "offer_response_hours",
+		"must be between 0 and %d", MaxOfferResponseHours)
+	validateLocation(&v, "location", event.Location)
+	if err := event.Pay.Validate(); err != nil {
+		v.Add("pay", err.Error())
+	}
+	return v.Err()
+}
+
+func validateApplication(application models.Application) error {
+	var v models.Validator
+	v.Check(application.EventRef != uuid.Nil, "EventRef", "is required")
+	switch application.Status {
+	case models.StatusAccepted, models.StatusRejected, models.StatusPending, models.StatusOffered, models.StatusUnknown,
+		models.StatusWaitlisted, models.StatusDeclined, models.StatusExpired, "":
+	default:
+		v.Add("application_status",
+			"invalid value %q. Allowed: accepted, rejected, pending, offered, unknown, waitlisted, declined, expired",
+			application.Status,
+		)
+	}
+	return v.Err()
+}
@@ -0,0 +1,76 @@
+package agenda
+
+import (
+	"backend/models"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateEvent(t *testing.T) {
+	starts := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	before, after := starts.Add(-time.Hour), starts.Add(time.Hour)
+	valid := models.Event{Name: "Open mic", Time: starts, Status: models.EventOpen}
+	tests := []struct {
+		name   string
+		change func(event *models.Event)
+		want   []string
+	}{
+		{name: "valid", change: func(*models.Event) {}},
+		{name: "apply by before the event", change: func(e *models.Event) { e.ApplyByTime = &before }},
+		{name: "missing name and time", change: func(e *models.Event) { e.Name, e.Time = " ", time.Time{} }, want: []string{"Name", "Time"}},
+		{name: "apply by after the event", change: func(e *models.Event) { e.ApplyByTime = &after }, want: []string{"apply_by_time"}},
+		{name: "unknown status", change: func(e *models.Event) { e.Status = "maybe" }, want: []string{"application_status"}},
+		{name: "negative response window", change: func(e *models.Event) { e.OfferResponseHours = -1 }, want: []string{"offer_response_hours"}},
+		{
+			name:   "response window too long",
+			change: func(e *models.Event) { e.OfferResponseHours = MaxOfferResponseHours + 1 },
+			want:   []string{"offer_response_hours"},
+		},
+		{
+			name:   "location off the map",
+			change: func(e *models.Event) { e.Location = gormGIS.GeoPoint{Lat: 91, Lng: -181} },
+			want:   []string{"location.lat", "location.lng"},
+		},
+		{
+			name:   "flat fee without an amount",
+			change: func(e *models.Event) { e.Pay = models.PayStructure{Type: models.PayFlatFee} },
+			want:   []string{"pay"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := valid
+			test.change(&event)
+			if got := invalidFields(t, ValidateEvent(event)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ValidateEvent() fields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateApplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		application models.Application
+		want        []string
+	}{
+		{name: "valid", application: models.Application{EventRef: uuid.New(), Status: models.StatusPending}},
+		{name: "no status yet", application: models.Application{EventRef: uuid.New()}},
+		{name: "no event", application: models.Application{Status: models.StatusPending}, want: []string{"EventRef"}},
+		{
+			name:        "unknown status",
+			application: models.Application{EventRef: uuid.New(), Status: "shortlisted"},
+			want:        []string{"application_status"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := invalidFields(t, validateApplication(test.application)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("validateApplication() fields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
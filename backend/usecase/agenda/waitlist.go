@@ -0,0 +1,292 @@
+package agenda
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+)
+
+// MaxOfferResponseHours bounds an event's offer response window to thirty days.
+const MaxOfferResponseHours = 720
+
+var (
+	ErrNotOffered      = domain.Conflict("application_not_offered", "only an offered application can be declined")
+	ErrProducerOnly    = domain.Forbidden("status_producer_only", "only the event's producer may make this status change")
+	ErrBookingConflict = domain.Conflict(
+		"booking_conflict", "the performer, one of its members or one of its ensembles is already booked around this time",
+	)
+)
+
+// Party is the side of an application that changes it.
+type Party string
+
+const (
+	PartyPerformer Party = "performer"
+	PartyProducer  Party = "producer"
+)
+
+// statusMoves lists, for each party, the statuses it may move an application to from the status it is in. A party
+// with no entry may make every change settleStatus allows: performers only answer offers, the rest is the producer's.
+var statusMoves = map[Party]map[models.ApplicationStatus][]models.ApplicationStatus{
+	PartyPerformer: {
+		models.StatusOffered: {models.StatusAccepted, models.StatusDeclined},
+	},
+}
+
+func checkStatusMove(party Party, from models.ApplicationStatus, to models.ApplicationStatus) error {
+	moves, restricted := statusMoves[party]
+	if !restricted || from == to {
+		return nil
+	}
+	for _, allowed := range moves[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return ErrProducerOnly
+}
+
+func isBooked(status models.ApplicationStatus) bool {
+	return status == models.StatusOffered || status == models.StatusAccepted
+}
+
+// checkConflicts refuses to book the application if anyone in the act is already offered or accepted for an event
+// starting within models.ConflictWindow of this one. Each conflict is named by the event it is for.
+func (s *Service) checkConflicts(ctx context.Context, event models.Event, application models.Application) error {
+	if event.Time.IsZero() {
+		return nil
+	}
+	commitments, err := s.repo.GetCommitments(ctx, application.PerformerID, application.ID,
+		event.Time.Add(-models.ConflictWindow), event.Time.Add(models.ConflictWindow))
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	if len(commitments) == 0 {
+		return nil
+	}
+	fields := make([]domain.FieldError, len(commitments))
+	for i, commitment := range commitments {
+		fields[i] = domain.FieldError{
+			Field:   fmt.Sprintf("conflicts[%d]", i),
+			Message: fmt.Sprintf("%s for event %s", commitment.Status, commitment.EventRef),
+		}
+	}
+	return ErrBookingConflict.WithFields(fields...)
+}
+
+// settleStatus fills in what follows from the application's status before it is written: its place at the end of the
+// waitlist unless one is given, and the deadline of a new offer under the event's response window unless one is given.
+// It refuses the changes no one may make: leaving expired, declining what was not offered and expiring early, and
+// booking an act that is already booked elsewhere at the time.
+func (s *Service) settleStatus(
+	ctx context.Context, event models.Event, previous models.Application, application *models.Application,
+) error {
+	now := time.Now().UTC()
+	switch {
+	case previous.Status == models.StatusExpired && application.Status != models.StatusExpired:
+		return ErrOfferExpired
+	case application.Status == models.StatusDeclined && previous.Status != models.StatusOffered &&
+		previous.Status != models.StatusDeclined:
+		return ErrNotOffered
+	case application.Status == models.StatusExpired && previous.Status != models.StatusExpired &&
+		(previous.Status != models.StatusOffered || previous.OfferExpiresAt == nil || now.Before(*previous.OfferExpiresAt)):
+		return ErrNotExpired
+	}
+	if isBooked(application.Status) && !isBooked(previous.Status) {
+		if err := s.checkConflicts(ctx, event, *application); err != nil {
+			return err
+		}
+	}
+	switch {
+	case application.Status != models.StatusWaitlisted:
+		application.WaitlistPosition = nil
+	case application.WaitlistPosition == nil:
+		position, err := s.repo.NextWaitlistPosition(ctx, application.EventRef)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		application.WaitlistPosition = &position
+	}
+	switch {
+	case application.Status == models.StatusExpired:
+	case application.Status != models.StatusOffered:
+		application.OfferExpiresAt = nil
+	case previous.Status != models.StatusOffered && application.OfferExpiresAt == nil:
+		application.OfferExpiresAt = event.OfferDeadline(now)
+	}
+	if !sameTime(previous.OfferExpiresAt, application.OfferExpiresAt) {
+		application.OfferRemindedAt = nil
+	}
+	return nil
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// releasesOffer tells whether the change gives an offer back, so the next waitlisted application can have it.
+func releasesOffer(previous models.Application, current models.Application) bool {
+	return previous.Status == models.StatusOffered &&
+		(current.Status == models.StatusDeclined || current.Status == models.StatusExpired)
+}
+
+// promoteNext offers the place a declined or expired offer freed to the first application on the event's waitlist, with the
+// event's response window, and tells its performer. The system makes the promotion, whoever declined; a failure is
+// logged, as the decline itself has already been saved.
+func (s *Service) promoteNext(ctx context.Context, eventID uuid.UUID) {
+	ctx = models.ContextWithActor(ctx, models.Actor{Type: models.ActorSystem})
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		log.Printf("unable to promote from the waitlist of event %s: %v", eventID, err)
+		return
+	}
+	if event.Status == models.EventCancelled {
+		return
+	}
+	var promoted statusChange
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		// Applications whose act is booked elsewhere at the time are passed over and stay on the waitlist.
+		var skip []uuid.UUID
+		var next, application models.Application
+		for {
+			var err error
+			if next, err = s.repo.NextWaitlisted(ctx, eventID, skip...); err != nil || next.ID == uuid.Nil {
+				return errors.Wrap(err, "db error")
+			}
+			application = next
+			application.Status = models.StatusOffered
+			err = s.settleStatus(ctx, event, next, &application)
+			if errors.Is(err, ErrBookingConflict) {
+				skip = append(skip, next.ID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			break
+		}
+		out, err := s.repo.UpdateApplication(ctx, application)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		promoted = statusChange{previous: next, current: out}
+		return s.audit(ctx, "application.promote", "application", out.ID.String(), next, out,
+			out.PerformerID, event.ProducerID)
+	})
+	if err != nil {
+		log.Printf("unable to promote from the waitlist of event %s: %v", eventID, err)
+		return
+	}
+	if promoted.current.ID == uuid.Nil {
+		return
+	}
+	current := promoted.current
+	s.statusChanged(ctx, promoted.previous, current)
+	s.publish(ctx, models.StreamApplicationPromoted, current.ID,
+		map[string]interface{}{"id": current.ID, "event_ref": eventID, "offer_expires_at": current.OfferExpiresAt},
+		current.PerformerID, event.ProducerID,
+	)
+}
+
+// GetWaitlist returns the event's waitlisted applications, first in line first.
+func (s *Service) GetWaitlist(ctx context.Context, eventID uuid.UUID) ([]models.Application, error) {
+	applications, err := s.repo.GetWaitlist(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return applications, nil
+}
+
+// SetWaitlist makes the listed applications the event's waitlist, in that order, in one transaction. Pending,
+// unknown, rejected and already waitlisted applications can be listed; waitlisted ones left out go back to pending.
+func (s *Service) SetWaitlist(ctx context.Context, eventID uuid.UUID, order models.WaitlistOrder) ([]models.Application, error) {
+	var v models.Validator
+	v.Check(len(order.ApplicationIDs) <= MaxBulkStatusItems, "application_ids", "must have at most %d items", MaxBulkStatusItems)
+	listed := make(map[uuid.UUID]int, len(order.ApplicationIDs))
+	for i, id := range order.ApplicationIDs {
+		_, seen := listed[id]
+		v.Check(!seen, fmt.Sprintf("application_ids[%d]", i), "appears more than once")
+		listed[id] = i + 1
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+
+	var changes []statusChange
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		changes = nil
+		applications, err := s.repo.GetApplicationsByEvent(ctx, eventID)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		byID := make(map[uuid.UUID]models.Application, len(applications))
+		for _, application := range applications {
+			byID[application.ID] = application
+		}
+		var v models.Validator
+		for i, id := range order.ApplicationIDs {
+			field := fmt.Sprintf("application_ids[%d]", i)
+			switch application, ok := byID[id]; {
+			case !ok:
+				v.Add(field, "is not an application to this event")
+			case application.Status != models.StatusPending && application.Status != models.StatusUnknown &&
+				application.Status != models.StatusRejected && application.Status != models.StatusWaitlisted:
+				v.Add(field, "is %s and cannot be waitlisted", application.Status)
+			}
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+		for _, previous := range applications {
+			application := previous
+			if position, ok := listed[previous.ID]; ok {
+				application.Status, application.WaitlistPosition = models.StatusWaitlisted, &position
+			} else if previous.Status == models.StatusWaitlisted {
+				application.Status, application.WaitlistPosition = models.StatusPending, nil
+			} else {
+				continue
+			}
+			if previous.Status == application.Status && previous.WaitlistPosition != nil &&
+				*previous.WaitlistPosition == *application.WaitlistPosition {
+				continue
+			}
+			// The performer was only loaded with the event's applications; it is left out of the write.
+			application.Performer = models.Profile{}
+			if err := s.settleStatus(ctx, event, previous, &application); err != nil {
+				return err
+			}
+			out, err := s.repo.UpdateApplication(ctx, application)
+			if err != nil {
+				return errors.Wrap(err, "db error")
+			}
+			out.Performer = previous.Performer
+			if err := s.audit(ctx, "application.update", "application", out.ID.String(), previous, out,
+				out.PerformerID, event.ProducerID); err != nil {
+				return err
+			}
+			changes = append(changes, statusChange{previous: previous, current: out})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if change.previous.Status != change.current.Status {
+			s.statusChanged(ctx, change.previous, change.current)
+		}
+	}
+	return s.GetWaitlist(ctx, eventID)
+}
@@ -0,0 +1,211 @@
+package agenda
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCheckStatusMove(t *testing.T) {
+	tests := []struct {
+		party   Party
+		from    models.ApplicationStatus
+		to      models.ApplicationStatus
+		wantErr error
+	}{
+		{party: PartyPerformer, from: models.StatusOffered, to: models.StatusAccepted},
+		{party: PartyPerformer, from: models.StatusOffered, to: models.StatusDeclined},
+		{party: PartyPerformer, from: models.StatusPending, to: models.StatusPending},
+		{party: PartyPerformer, from: models.StatusPending, to: models.StatusAccepted, wantErr: ErrProducerOnly},
+		{party: PartyPerformer, from: models.StatusWaitlisted, to: models.StatusOffered, wantErr: ErrProducerOnly},
+		{party: PartyPerformer, from: models.StatusOffered, to: models.StatusExpired, wantErr: ErrProducerOnly},
+		{party: PartyPerformer, from: models.StatusRejected, to: models.StatusPending, wantErr: ErrProducerOnly},
+		{party: PartyProducer, from: models.StatusPending, to: models.StatusAccepted},
+		{party: PartyProducer, from: models.StatusWaitlisted, to: models.StatusOffered},
+		{party: PartyProducer, from: models.StatusOffered, to: models.StatusDeclined},
+	}
+	for _, test := range tests {
+		if err := checkStatusMove(test.party, test.from, test.to); !errors.Is(err, test.wantErr) {
+			t.Errorf("checkStatusMove(%s, %s, %s) = %v, want %v", test.party, test.from, test.to, err, test.wantErr)
+		}
+	}
+}
+
+// fakeRepo keeps one event and its applications in memory. booked performers are already committed elsewhere at the
+// time of the event. Methods the tests do not reach are left to the embedded nil Repository.
+type fakeRepo struct {
+	Repository
+	event        models.Event
+	applications map[uuid.UUID]models.Application
+	booked       map[uuid.UUID]bool
+}
+
+func (r *fakeRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *fakeRepo) GetEvent(context.Context, uuid.UUID) (models.Event, error) {
+	return r.event, nil
+}
+
+func (r *fakeRepo) GetApplication(_ context.Context, id uuid.UUID) (models.Application, error) {
+	return r.applications[id], nil
+}
+
+func (r *fakeRepo) UpdateApplication(_ context.Context, application models.Application) (models.Application, error) {
+	if r.applications[application.ID].Version != application.Version {
+		return application, models.ErrVersionConflict
+	}
+	application.Version++
+	r.applications[application.ID] = application
+	return application, nil
+}
+
+func (r *fakeRepo) NextWaitlisted(_ context.Context, _ uuid.UUID, skip ...uuid.UUID) (models.Application, error) {
+	skipped := map[uuid.UUID]bool{}
+	for _, id := range skip {
+		skipped[id] = true
+	}
+	var waitlist []models.Application
+	for _, application := range r.applications {
+		if application.Status == models.StatusWaitlisted && !skipped[application.ID] {
+			waitlist = append(waitlist, application)
+		}
+	}
+	if len(waitlist) == 0 {
+		return models.Application{}, nil
+	}
+	sort.Slice(waitlist, func(i, j int) bool { return *waitlist[i].WaitlistPosition < *waitlist[j].WaitlistPosition })
+	return waitlist[0], nil
+}
+
+func (r *fakeRepo) GetCommitments(_ context.Context, performerID uuid.UUID, _ uuid.UUID, _ time.Time, _ time.Time) ([]models.Application, error) {
+	if r.booked[performerID] {
+		return []models.Application{{EventRef: uuid.New(), Status: models.StatusAccepted}}, nil
+	}
+	return nil, nil
+}
+
+type fakePublisher struct {
+	events []models.StreamEvent
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event models.StreamEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+type fakeAuditor struct{}
+
+func (fakeAuditor) Record(context.Context, string, string, string, interface{}, interface{}, ...uuid.UUID) error {
+	return nil
+}
+
+// newWaitlist sets up an event with one offered application and the given number of waitlisted ones, in order.
+func newWaitlist(status models.EventApplicationStatus, waitlisted int) (*fakeRepo, models.Application, []models.Application) {
+	repo := &fakeRepo{
+		event: models.Event{
+			Model: models.Model{ID: uuid.New()}, ProducerID: uuid.New(), Status: status,
+			Time: time.Now().Add(48 * time.Hour), OfferResponseHours: 24,
+		},
+		applications: map[uuid.UUID]models.Application{},
+		booked:       map[uuid.UUID]bool{},
+	}
+	add := func(status models.ApplicationStatus, position *int) models.Application {
+		application := models.Application{
+			Model: models.Model{ID: uuid.New()}, EventRef: repo.event.ID, PerformerID: uuid.New(),
+			Status: status, WaitlistPosition: position, Version: 1,
+		}
+		repo.applications[application.ID] = application
+		return application
+	}
+	offered := add(models.StatusOffered, nil)
+	var waitlist []models.Application
+	for i := 1; i <= waitlisted; i++ {
+		position := i
+		waitlist = append(waitlist, add(models.StatusWaitlisted, &position))
+	}
+	return repo, offered, waitlist
+}
+
+func TestDeclinePromotesFromTheWaitlist(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventStatus models.EventApplicationStatus
+		waitlisted  int
+		booked      []int
+		// promoted is the index in the waitlist of the application that gets the offer, -1 for none.
+		promoted int
+	}{
+		{name: "first in line", eventStatus: models.EventOpen, waitlisted: 3, promoted: 0},
+		{name: "booked elsewhere are passed over", eventStatus: models.EventOpen, waitlisted: 3, booked: []int{0, 1}, promoted: 2},
+		{name: "everyone booked elsewhere", eventStatus: models.EventOpen, waitlisted: 2, booked: []int{0, 1}, promoted: -1},
+		{name: "empty waitlist", eventStatus: models.EventOpen, waitlisted: 0, promoted: -1},
+		{name: "cancelled event", eventStatus: models.EventCancelled, waitlisted: 2, promoted: -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo, offered, waitlist := newWaitlist(test.eventStatus, test.waitlisted)
+			for _, i := range test.booked {
+				repo.booked[waitlist[i].PerformerID] = true
+			}
+			publisher := &fakePublisher{}
+			service := NewService(repo, publisher, fakeAuditor{}, nil)
+
+			declined := offered
+			declined.Status = models.StatusDeclined
+			if _, err := service.UpdateApplication(context.Background(), PartyPerformer, declined); err != nil {
+				t.Fatalf("UpdateApplication() error = %v", err)
+			}
+
+			for i, application := range waitlist {
+				stored := repo.applications[application.ID]
+				if i != test.promoted {
+					if stored.Status != models.StatusWaitlisted || stored.WaitlistPosition == nil {
+						t.Errorf("waitlisted application %d is now %s at %v", i, stored.Status, stored.WaitlistPosition)
+					}
+					continue
+				}
+				if stored.Status != models.StatusOffered || stored.WaitlistPosition != nil {
+					t.Errorf("promoted application is %s at %v, want offered off the waitlist", stored.Status, stored.WaitlistPosition)
+				}
+				if stored.OfferExpiresAt == nil {
+					t.Errorf("promoted application has no deadline under the event's response window")
+				}
+			}
+			var promotions int
+			for _, event := range publisher.events {
+				if event.Type == models.StreamApplicationPromoted {
+					promotions++
+					if event.ResourceID != waitlist[test.promoted].ID {
+						t.Errorf("promotion published for %s, want %s", event.ResourceID, waitlist[test.promoted].ID)
+					}
+				}
+			}
+			want := 1
+			if test.promoted < 0 {
+				want = 0
+			}
+			if promotions != want {
+				t.Errorf("published %d promotions, want %d", promotions, want)
+			}
+		})
+	}
+}
+
+func TestPerformerCannotTakeWaitlistedPlace(t *testing.T) {
+	repo, _, waitlist := newWaitlist(models.EventOpen, 1)
+	service := NewService(repo, &fakePublisher{}, fakeAuditor{}, nil)
+	application := waitlist[0]
+	application.Status = models.StatusOffered
+	if _, err := service.UpdateApplication(context.Background(), PartyPerformer, application); !errors.Is(err, ErrProducerOnly) {
+		t.Fatalf("UpdateApplication() error = %v, want %v", err, ErrProducerOnly)
+	}
+	if stored := repo.applications[application.ID]; stored.Status != models.StatusWaitlisted {
+		t.Errorf("application is now %s, want waitlisted", stored.Status)
+	}
+}
@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"backend/models"
+	"context"
+)
+
+type Repository interface {
+	CreateEntry(ctx context.Context, entry models.AuditEntry) error
+	GetEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
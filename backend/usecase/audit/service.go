@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repository Repository) Service {
+	return Service{repo: repository}
+}
+
+// Record appends an entry for the actor found on ctx. before and after are snapshotted as JSON.
+func (s *Service) Record(
+	ctx context.Context, action string, resourceType string, resourceID string,
+	before interface{}, after interface{}, profileIDs ...uuid.UUID,
+) error {
+	entry, err := models.NewAuditEntry(models.ActorFromContext(ctx), action, resourceType, resourceID, before, after, profileIDs...)
+	if err != nil {
+		return errors.Wrap(err, "unable to snapshot audit entry")
+	}
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	return nil
+}
+
+func (s *Service) GetEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	entries, err := s.repo.GetEntries(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return entries, nil
+}
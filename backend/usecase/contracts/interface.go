@@ -0,0 +1,37 @@
+package contracts
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	CreateTemplate(ctx context.Context, template models.ContractTemplate) (uuid.UUID, error)
+	// GetLatestTemplate and GetContractByApplication return a zero value, not an error, when nothing exists yet.
+	GetLatestTemplate(ctx context.Context, producerID uuid.UUID) (models.ContractTemplate, error)
+	GetTemplatesByProducer(ctx context.Context, producerID uuid.UUID) ([]models.ContractTemplate, error)
+
+	CreateContract(ctx context.Context, contract models.Contract) (uuid.UUID, error)
+	GetContract(ctx context.Context, id uuid.UUID) (models.Contract, error)
+	GetContractByApplication(ctx context.Context, applicationID uuid.UUID) (models.Contract, error)
+	// ReplaceContractBody must refuse to touch a contract that carries any signature.
+	ReplaceContractBody(ctx context.Context, contract models.Contract) (models.Contract, error)
+	SignContract(ctx context.Context, id uuid.UUID, party models.ContractParty, signerUID string, at time.Time) (models.Contract, error)
+	// VoidContract marks a contract void unless both parties signed it; its body and any signature stay as they were.
+	VoidContract(ctx context.Context, id uuid.UUID) error
+	// TerminateContract records when a signed contract stopped applying, once; its status stays signed.
+	TerminateContract(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type AgendaRepository interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
+}
+
+type ProfileRepository interface {
+	GetProfileByID(ctx context.Context, id uuid.UUID) (models.Profile, error)
+	// GetAuthorizedUsers counts the admins of the profile's organization among its members.
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+}
@@ -0,0 +1,292 @@
+package contracts
+
+import (
+	"backend/domain"
+	"backend/models"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"text/template"
+	"time"
+)
+
+var (
+	ErrNotAccepted   = domain.Conflict("application_not_accepted", "contracts can only be generated for accepted applications")
+	ErrAlreadySigned = domain.Conflict("contract_signed", "contract has been signed and can no longer change")
+	ErrVoid          = domain.Conflict("contract_void", "contract was voided when its application stopped being accepted")
+	ErrNotParty      = domain.Forbidden("not_contract_party", "caller is not a member of that party to this contract")
+)
+
+const DefaultTemplate = `PERFORMANCE AGREEMENT
+
+This agreement is made on {{.Date.Format "January 2, 2006"}} between {{.Producer.Name}} ("Producer") and {{.Performer.Name}} ("Performer").
+
+Event: {{.Event.Name}}
+Performance time: {{.Slot.Format "Monday, January 2, 2006 at 3:04 PM MST"}}
+{{- with .Event.Venue}}
+Venue: {{.Name}}
+{{- end}}
+
+Compensation: {{.Pay}}
+
+The Performer agrees to perform at the event above, and the Producer agrees to provide the compensation described.
+Either party may cancel with reasonable written notice through ocall.
+`
+
+type Service struct {
+	repo     Repository
+	agenda   AgendaRepository
+	profiles ProfileRepository
+}
+
+func NewService(repository Repository, agenda AgendaRepository, profiles ProfileRepository) Service {
+	return Service{repo: repository, agenda: agenda, profiles: profiles}
+}
+
+func parseTemplate(body string) (*template.Template, error) {
+	return template.New("contract").Option("missingkey=error").Parse(body)
+}
+
+// validateTemplate parses the body and renders it once with empty data, which catches references to fields
+// ContractData does not have before an acceptance would trip over them.
+func validateTemplate(contractTemplate models.ContractTemplate) error {
+	var v models.Validator
+	v.Required("body", contractTemplate.Body)
+	parsed, err := parseTemplate(contractTemplate.Body)
+	if err == nil {
+		err = parsed.Execute(io.Discard, models.ContractData{})
+	}
+	if err != nil {
+		v.Add("body", "invalid template: %v", err)
+	}
+	return v.Err()
+}
+
+func (s *Service) CreateTemplate(ctx context.Context, contractTemplate models.ContractTemplate) (uuid.UUID, error) {
+	if err := validateTemplate(contractTemplate); err != nil {
+		return uuid.Nil, err
+	}
+	id, err := s.repo.CreateTemplate(ctx, contractTemplate)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "db error")
+	}
+	return id, nil
+}
+
+func (s *Service) GetTemplatesByProducer(ctx context.Context, producerID uuid.UUID) ([]models.ContractTemplate, error) {
+	templates, err := s.repo.GetTemplatesByProducer(ctx, producerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return templates, nil
+}
+
+// ApplicationStatusChanged drafts the agreement as soon as an application is accepted. When the application stops
+// being accepted, a contract still waiting on a signature is voided, and a signed one is marked terminated but
+// otherwise left as both parties signed it.
+func (s *Service) ApplicationStatusChanged(ctx context.Context, previous models.Application, current models.Application) error {
+	switch {
+	case current.Status == models.StatusAccepted && previous.Status != models.StatusAccepted:
+		_, err := s.GenerateForApplication(ctx, current.ID)
+		return err
+	case previous.Status == models.StatusAccepted && current.Status != models.StatusAccepted:
+		existing, err := s.repo.GetContractByApplication(ctx, current.ID)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		switch {
+		case existing.ID == uuid.Nil:
+		case existing.Status == models.ContractPending:
+			err = s.repo.VoidContract(ctx, existing.ID)
+		case existing.Status == models.ContractSigned && existing.TerminatedAt == nil:
+			err = s.repo.TerminateContract(ctx, existing.ID, time.Now().UTC())
+		}
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+	}
+	return nil
+}
+
+// GenerateForApplication renders the producer's current template. An unsigned contract is re-rendered in place;
+// a contract with any signature is immutable. A void or terminated contract is left as it was and a new one drafted.
+func (s *Service) GenerateForApplication(ctx context.Context, applicationID uuid.UUID) (models.Contract, error) {
+	application, err := s.agenda.GetApplication(ctx, applicationID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	if application.Status != models.StatusAccepted {
+		return models.Contract{}, ErrNotAccepted
+	}
+	existing, err := s.repo.GetContractByApplication(ctx, applicationID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	if existing.Status == models.ContractVoid || existing.TerminatedAt != nil {
+		existing = models.Contract{}
+	}
+	if existing.Signed() || existing.Status == models.ContractSigned {
+		return existing, ErrAlreadySigned
+	}
+
+	contract, err := s.render(ctx, application)
+	if err != nil {
+		return models.Contract{}, err
+	}
+	if existing.ID != uuid.Nil {
+		contract.Model = existing.Model
+		if contract, err = s.repo.ReplaceContractBody(ctx, contract); err != nil {
+			return contract, errors.Wrap(err, "db error")
+		}
+		return contract, nil
+	}
+	if contract.ID, err = s.repo.CreateContract(ctx, contract); err != nil {
+		return contract, errors.Wrap(err, "db error")
+	}
+	return contract, nil
+}
+
+func (s *Service) render(ctx context.Context, application models.Application) (models.Contract, error) {
+	event, err := s.agenda.GetEvent(ctx, application.EventRef)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	producer, err := s.profiles.GetProfileByID(ctx, event.ProducerID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	performer, err := s.profiles.GetProfileByID(ctx, application.PerformerID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	if event.VenueID != nil {
+		if venue, err := s.profiles.GetProfileByID(ctx, *event.VenueID); err == nil {
+			event.Venue = &venue
+		}
+	}
+	contractTemplate, err := s.repo.GetLatestTemplate(ctx, event.ProducerID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	body := DefaultTemplate
+	var templateID *uuid.UUID
+	if contractTemplate.ID != uuid.Nil {
+		body = contractTemplate.Body
+		templateID = &contractTemplate.ID
+	}
+	parsed, err := parseTemplate(body)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "invalid contract template")
+	}
+
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, models.ContractData{
+		Event:       models.NewTemplateEvent(event),
+		Producer:    models.NewTemplateProfile(producer),
+		Performer:   models.NewTemplateProfile(performer),
+		Application: models.NewTemplateApplication(application),
+		Pay:         event.Pay.Describe(),
+		Slot:        event.Time,
+		Date:        time.Now().UTC(),
+	}); err != nil {
+		return models.Contract{}, errors.Wrap(err, "unable to render contract")
+	}
+	hash := sha256.Sum256(out.Bytes())
+	return models.Contract{
+		ApplicationID: application.ID,
+		EventRef:      event.ID,
+		ProducerID:    event.ProducerID,
+		PerformerID:   application.PerformerID,
+		TemplateID:    templateID,
+		Status:        models.ContractPending,
+		Body:          out.String(),
+		BodyHash:      hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+func (s *Service) GetContract(ctx context.Context, id uuid.UUID) (models.Contract, error) {
+	contract, err := s.repo.GetContract(ctx, id)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	return contract, nil
+}
+
+func (s *Service) GetContractByApplication(ctx context.Context, applicationID uuid.UUID) (models.Contract, error) {
+	contract, err := s.repo.GetContractByApplication(ctx, applicationID)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	return contract, nil
+}
+
+func (s *Service) isMember(ctx context.Context, profileID uuid.UUID, firebaseID string) (bool, error) {
+	members, err := s.profiles.GetAuthorizedUsers(ctx, profileID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	for _, member := range members {
+		if member.FirebaseId == firebaseID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsParty reports whether the firebase user belongs to either side of the contract.
+func (s *Service) IsParty(ctx context.Context, contract models.Contract, firebaseID string) (bool, error) {
+	if ok, err := s.isMember(ctx, contract.ProducerID, firebaseID); err != nil || ok {
+		return ok, err
+	}
+	return s.isMember(ctx, contract.PerformerID, firebaseID)
+}
+
+// IsProducerMember reports whether the firebase user administers the producer of the application's event.
+func (s *Service) IsProducerMember(ctx context.Context, applicationID uuid.UUID, firebaseID string) (bool, error) {
+	application, err := s.agenda.GetApplication(ctx, applicationID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	event, err := s.agenda.GetEvent(ctx, application.EventRef)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	return s.isMember(ctx, event.ProducerID, firebaseID)
+}
+
+// Sign records the firebase user's signature for the party they name. A user who belongs to both profiles signs for
+// each side separately.
+func (s *Service) Sign(
+	ctx context.Context, id uuid.UUID, party models.ContractParty, firebaseID string,
+) (models.Contract, error) {
+	contract, err := s.repo.GetContract(ctx, id)
+	if err != nil {
+		return models.Contract{}, errors.Wrap(err, "db error")
+	}
+	profileID := contract.ProducerID
+	if party == models.PerformerParty {
+		profileID = contract.PerformerID
+	}
+	if ok, err := s.isMember(ctx, profileID, firebaseID); err != nil {
+		return contract, err
+	} else if !ok {
+		return contract, ErrNotParty
+	}
+	if contract.Status == models.ContractVoid {
+		return contract, ErrVoid
+	}
+	if contract.Status != models.ContractPending ||
+		(party == models.ProducerParty && contract.ProducerSignedAt != nil) ||
+		(party == models.PerformerParty && contract.PerformerSignedAt != nil) {
+		return contract, ErrAlreadySigned
+	}
+	out, err := s.repo.SignContract(ctx, id, party, firebaseID, time.Now().UTC())
+	if err != nil {
+		return contract, errors.Wrap(err, "db error")
+	}
+	return out, nil
+}
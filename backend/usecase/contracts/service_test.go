@@ -0,0 +1,134 @@
+package contracts
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeRepo keeps templates and contracts in memory. Methods the tests do not reach are left to the embedded nil
+// interfaces.
+type fakeRepo struct {
+	Repository
+	templates []models.ContractTemplate
+	contract  models.Contract
+}
+
+func (r *fakeRepo) GetContractByApplication(_ context.Context, applicationID uuid.UUID) (models.Contract, error) {
+	if r.contract.ApplicationID != applicationID {
+		return models.Contract{}, nil
+	}
+	return r.contract, nil
+}
+
+func (r *fakeRepo) VoidContract(_ context.Context, id uuid.UUID) error {
+	if r.contract.ID == id && r.contract.Status == models.ContractPending {
+		r.contract.Status = models.ContractVoid
+	}
+	return nil
+}
+
+func (r *fakeRepo) TerminateContract(_ context.Context, id uuid.UUID, at time.Time) error {
+	if r.contract.ID == id && r.contract.Status == models.ContractSigned && r.contract.TerminatedAt == nil {
+		r.contract.TerminatedAt = &at
+	}
+	return nil
+}
+
+func (r *fakeRepo) CreateTemplate(_ context.Context, template models.ContractTemplate) (uuid.UUID, error) {
+	template.ID = uuid.New()
+	r.templates = append(r.templates, template)
+	return template.ID, nil
+}
+
+func TestCreateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "default template", body: DefaultTemplate},
+		{name: "optional venue", body: `{{with .Event.Venue}}At {{.Name}}{{end}} for {{.Performer.Name}}`},
+		{name: "empty", body: "  ", wantErr: true},
+		{name: "does not parse", body: "{{.Event.Name", wantErr: true},
+		{name: "unknown field", body: "Fee: {{.Fee}}", wantErr: true},
+		{name: "unknown nested field", body: "{{.Performer.Email}}", wantErr: true},
+		{name: "unknown function", body: "{{upper .Event.Name}}", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &fakeRepo{}
+			service := NewService(repo, nil, nil)
+			_, err := service.CreateTemplate(context.Background(), models.ContractTemplate{Body: test.body})
+			if !test.wantErr {
+				if err != nil {
+					t.Fatalf("CreateTemplate() error = %v", err)
+				}
+				if len(repo.templates) != 1 {
+					t.Errorf("CreateTemplate() stored %d templates, want 1", len(repo.templates))
+				}
+				return
+			}
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || !errors.Is(err, domain.ErrInvalid) {
+				t.Fatalf("CreateTemplate() error = %v, want %v", err, domain.ErrInvalid)
+			}
+			if len(domainErr.Fields) == 0 || domainErr.Fields[0].Field != "body" {
+				t.Errorf("CreateTemplate() fields = %+v, want body", domainErr.Fields)
+			}
+			if len(repo.templates) != 0 {
+				t.Errorf("an invalid template was stored")
+			}
+		})
+	}
+}
+
+func TestApplicationNoLongerAccepted(t *testing.T) {
+	signedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		contract       models.Contract
+		wantStatus     models.ContractStatus
+		wantTerminated bool
+	}{
+		{name: "unsigned", contract: models.Contract{Status: models.ContractPending}, wantStatus: models.ContractVoid},
+		{
+			name:       "signed by one party",
+			contract:   models.Contract{Status: models.ContractPending, ProducerSignedAt: &signedAt},
+			wantStatus: models.ContractVoid,
+		},
+		{
+			name: "signed by both parties",
+			contract: models.Contract{
+				Status: models.ContractSigned, ProducerSignedAt: &signedAt, PerformerSignedAt: &signedAt,
+			},
+			wantStatus: models.ContractSigned, wantTerminated: true,
+		},
+		{name: "already void", contract: models.Contract{Status: models.ContractVoid}, wantStatus: models.ContractVoid},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			application := models.Application{Model: models.Model{ID: uuid.New()}, Status: models.StatusRejected}
+			contract := test.contract
+			contract.ID, contract.ApplicationID, contract.Body = uuid.New(), application.ID, "agreed"
+			repo := &fakeRepo{contract: contract}
+			service := NewService(repo, nil, nil)
+			previous := application
+			previous.Status = models.StatusAccepted
+			if err := service.ApplicationStatusChanged(context.Background(), previous, application); err != nil {
+				t.Fatalf("ApplicationStatusChanged() error = %v", err)
+			}
+			if repo.contract.Status != test.wantStatus || (repo.contract.TerminatedAt != nil) != test.wantTerminated {
+				t.Errorf("contract is %s, terminated at %v; want %s, terminated: %v",
+					repo.contract.Status, repo.contract.TerminatedAt, test.wantStatus, test.wantTerminated)
+			}
+			if repo.contract.Body != "agreed" || repo.contract.ProducerSignedAt != contract.ProducerSignedAt {
+				t.Errorf("the contract's body or signatures changed")
+			}
+		})
+	}
+}
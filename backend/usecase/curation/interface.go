@@ -0,0 +1,38 @@
+package curation
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// GetRubric returns a zero value when the event has no rubric yet.
+	GetRubric(ctx context.Context, eventID uuid.UUID) (models.Rubric, error)
+	UpsertRubric(ctx context.Context, rubric models.Rubric) (models.Rubric, error)
+	CountScores(ctx context.Context, eventID uuid.UUID) (int64, error)
+	// UpsertScore replaces the reviewer's earlier score of the application, if any.
+	UpsertScore(ctx context.Context, score models.ApplicationScore) (models.ApplicationScore, error)
+	GetScoresByReviewer(ctx context.Context, eventID uuid.UUID, reviewerID uuid.UUID) ([]models.ApplicationScore, error)
+}
+
+type AgendaRepository interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
+	GetApplicationsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Application, error)
+}
+
+type ProfileRepository interface {
+	// GetAuthorizedUsers counts the admins of the profile's organization among its members.
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
+}
@@ -0,0 +1,243 @@
+package curation
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	maxCriteria      = 20
+	maxCommentLength = 4000
+)
+
+var (
+	ErrNoRubric      = domain.NotFound("no_rubric", "the event has no rubric")
+	ErrRubricInUse   = domain.Conflict("rubric_in_use", "criteria cannot change once applications have been scored")
+	ErrNotReviewer   = domain.Forbidden("not_reviewer", "caller is not assigned to review this event")
+	ErrBlindReviewer = domain.Forbidden(
+		"blind_reviewer", "reviewers of a blind rubric only see the event's applications through their review queue",
+	)
+)
+
+type Service struct {
+	repo     Repository
+	agenda   AgendaRepository
+	profiles ProfileRepository
+	auditor  Auditor
+}
+
+func NewService(repository Repository, agenda AgendaRepository, profiles ProfileRepository, auditor Auditor) Service {
+	return Service{repo: repository, agenda: agenda, profiles: profiles, auditor: auditor}
+}
+
+func validateRubric(rubric models.Rubric, members map[uuid.UUID]bool) error {
+	var v models.Validator
+	v.Check(len(rubric.Criteria) > 0, "criteria", "must not be empty")
+	v.Check(len(rubric.Criteria) <= maxCriteria, "criteria", "must have at most %d criteria", maxCriteria)
+	names := map[string]bool{}
+	for i, criterion := range rubric.Criteria {
+		field := fmt.Sprintf("criteria[%d]", i)
+		v.Required(field+".name", criterion.Name)
+		v.Check(!names[strings.ToLower(criterion.Name)], field+".name", "appears more than once")
+		v.Check(criterion.Weight > 0, field+".weight", "must be positive")
+		names[strings.ToLower(criterion.Name)] = true
+	}
+	for i, id := range rubric.ReviewerIDs {
+		v.Check(members[id], fmt.Sprintf("reviewer_ids[%d]", i), "is not a member of the producer")
+	}
+	return v.Err()
+}
+
+// SetRubric defines or replaces the event's rubric. Reviewers are user IDs of the producer's members, or the IDs of the
+// admins of its organization. Once anything has been scored only the reviewers and blind mode may change, so totals
+// stay comparable.
+func (s *Service) SetRubric(ctx context.Context, eventID uuid.UUID, rubric models.Rubric) (models.Rubric, error) {
+	event, err := s.agenda.GetEvent(ctx, eventID)
+	if err != nil {
+		return rubric, errors.Wrap(err, "db error")
+	}
+	members, err := s.profiles.GetAuthorizedUsers(ctx, event.ProducerID)
+	if err != nil {
+		return rubric, errors.Wrap(err, "db error")
+	}
+	memberIDs := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		memberIDs[member.ID] = true
+	}
+	if err := validateRubric(rubric, memberIDs); err != nil {
+		return rubric, err
+	}
+	previous, err := s.repo.GetRubric(ctx, eventID)
+	if err != nil {
+		return rubric, errors.Wrap(err, "db error")
+	}
+	if previous.ID != uuid.Nil && !reflect.DeepEqual(previous.Criteria, rubric.Criteria) {
+		scored, err := s.repo.CountScores(ctx, eventID)
+		if err != nil {
+			return rubric, errors.Wrap(err, "db error")
+		}
+		if scored > 0 {
+			return rubric, ErrRubricInUse
+		}
+	}
+
+	rubric.Model, rubric.EventRef = models.Model{}, eventID
+	if rubric.ReviewerIDs == nil {
+		rubric.ReviewerIDs = models.UUIDs{}
+	}
+	var out models.Rubric
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpsertRubric(ctx, rubric); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		var before interface{}
+		if previous.ID != uuid.Nil {
+			before = previous
+		}
+		if err := s.auditor.Record(ctx, "rubric.set", "event", eventID.String(), before, out, event.ProducerID); err != nil {
+			return errors.Wrapf(err, "unable to audit rubric.set on event %s", eventID)
+		}
+		return nil
+	})
+	if err != nil {
+		return rubric, err
+	}
+	return out, nil
+}
+
+func (s *Service) GetRubric(ctx context.Context, eventID uuid.UUID) (models.Rubric, error) {
+	rubric, err := s.repo.GetRubric(ctx, eventID)
+	if err != nil {
+		return rubric, errors.Wrap(err, "db error")
+	}
+	if rubric.ID == uuid.Nil {
+		return rubric, ErrNoRubric
+	}
+	return rubric, nil
+}
+
+// reviewer finds the caller among the event's assigned reviewers.
+func (s *Service) reviewer(ctx context.Context, event models.Event, firebaseID string) (models.UserID, models.Rubric, error) {
+	rubric, err := s.GetRubric(ctx, event.ID)
+	if err != nil {
+		return models.UserID{}, rubric, err
+	}
+	assigned := make(map[uuid.UUID]bool, len(rubric.ReviewerIDs))
+	for _, id := range rubric.ReviewerIDs {
+		assigned[id] = true
+	}
+	members, err := s.profiles.GetAuthorizedUsers(ctx, event.ProducerID)
+	if err != nil {
+		return models.UserID{}, rubric, errors.Wrap(err, "db error")
+	}
+	for _, member := range members {
+		if member.FirebaseId == firebaseID && assigned[member.ID] {
+			return member, rubric, nil
+		}
+	}
+	return models.UserID{}, rubric, ErrNotReviewer
+}
+
+// IsBlindReviewer reports whether the caller is assigned to review the event under a blind rubric, and so must not see
+// who applied anywhere but in the review queue.
+func (s *Service) IsBlindReviewer(ctx context.Context, event models.Event, firebaseID string) (bool, error) {
+	_, rubric, err := s.reviewer(ctx, event, firebaseID)
+	switch {
+	case err == ErrNoRubric || err == ErrNotReviewer:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return rubric.Blind, nil
+}
+
+// ReviewQueue lists the event's applications for one of its reviewers, with the reviewer's own scores. Under a blind
+// rubric everything that could tell who the performer is, from the application name to the answers to the event's form,
+// is left out.
+func (s *Service) ReviewQueue(ctx context.Context, eventID uuid.UUID, firebaseID string) ([]models.ReviewItem, error) {
+	event, err := s.agenda.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	reviewer, rubric, err := s.reviewer(ctx, event, firebaseID)
+	if err != nil {
+		return nil, err
+	}
+	applications, err := s.agenda.GetApplicationsByEvent(ctx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	scores, err := s.repo.GetScoresByReviewer(ctx, eventID, reviewer.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	byApplication := make(map[uuid.UUID]models.ApplicationScore, len(scores))
+	for _, score := range scores {
+		byApplication[score.ApplicationID] = score
+	}
+	items := make([]models.ReviewItem, len(applications))
+	for i, application := range applications {
+		if rubric.Blind {
+			application.Name, application.Performer, application.GoogleResponseID = "", models.Profile{}, ""
+			application.PerformerID, application.Members, application.Answers = uuid.Nil, nil, nil
+		}
+		items[i] = models.ReviewItem{Application: application}
+		if score, ok := byApplication[application.ID]; ok {
+			items[i].Score = &score
+		}
+	}
+	return items, nil
+}
+
+func validateScore(rubric models.Rubric, score models.ApplicationScore) error {
+	var v models.Validator
+	known := make(map[string]bool, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		known[criterion.Name] = true
+		value, ok := score.Scores[criterion.Name]
+		field := fmt.Sprintf("scores[%s]", criterion.Name)
+		v.Check(ok, field, "is required")
+		v.Check(!ok || (value >= 0 && value <= models.ScoreScale), field, "must be between 0 and %d", models.ScoreScale)
+	}
+	for name := range score.Scores {
+		v.Check(known[name], fmt.Sprintf("scores[%s]", name), "is not a criterion of the rubric")
+	}
+	v.Check(len(score.Comment) <= maxCommentLength, "comment", "must be at most %d characters", maxCommentLength)
+	return v.Err()
+}
+
+// SubmitScore records or replaces the caller's score of an application. Scores are not audited: audit entries are
+// visible to every profile they mention, and the performer must never see them.
+func (s *Service) SubmitScore(
+	ctx context.Context, applicationID uuid.UUID, firebaseID string, score models.ApplicationScore,
+) (models.ApplicationScore, error) {
+	application, err := s.agenda.GetApplication(ctx, applicationID)
+	if err != nil {
+		return score, errors.Wrap(err, "db error")
+	}
+	event, err := s.agenda.GetEvent(ctx, application.EventRef)
+	if err != nil {
+		return score, errors.Wrap(err, "db error")
+	}
+	reviewer, rubric, err := s.reviewer(ctx, event, firebaseID)
+	if err != nil {
+		return score, err
+	}
+	if err := validateScore(rubric, score); err != nil {
+		return score, err
+	}
+	score.Model = models.Model{}
+	score.EventRef, score.ApplicationID, score.ReviewerID = event.ID, application.ID, reviewer.ID
+	score.Total = rubric.Weighted(score.Scores)
+	out, err := s.repo.UpsertScore(ctx, score)
+	if err != nil {
+		return score, errors.Wrap(err, "db error")
+	}
+	return out, nil
+}
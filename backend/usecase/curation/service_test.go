@@ -0,0 +1,121 @@
+package curation
+
+import (
+	"backend/models"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"testing"
+)
+
+// fakeRepo serves one event, its applications, the producer's members and, if set, its rubric. Methods the tests do
+// not reach are left to the embedded nil interfaces.
+type fakeRepo struct {
+	Repository
+	AgendaRepository
+	event        models.Event
+	applications []models.Application
+	members      []models.UserID
+	rubric       models.Rubric
+}
+
+func (r *fakeRepo) GetRubric(context.Context, uuid.UUID) (models.Rubric, error) {
+	return r.rubric, nil
+}
+
+func (r *fakeRepo) GetScoresByReviewer(context.Context, uuid.UUID, uuid.UUID) ([]models.ApplicationScore, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetEvent(context.Context, uuid.UUID) (models.Event, error) {
+	return r.event, nil
+}
+
+func (r *fakeRepo) GetApplicationsByEvent(context.Context, uuid.UUID) ([]models.Application, error) {
+	return r.applications, nil
+}
+
+func (r *fakeRepo) GetAuthorizedUsers(context.Context, uuid.UUID) ([]models.UserID, error) {
+	return r.members, nil
+}
+
+func newFakeRepo() *fakeRepo {
+	reviewer := models.UserID{Model: models.Model{ID: uuid.New()}, FirebaseId: "reviewer"}
+	other := models.UserID{Model: models.Model{ID: uuid.New()}, FirebaseId: "other"}
+	return &fakeRepo{
+		event: models.Event{Model: models.Model{ID: uuid.New()}, ProducerID: uuid.New()},
+		applications: []models.Application{{
+			Model:            models.Model{ID: uuid.New()},
+			Name:             "The Band",
+			Status:           models.StatusPending,
+			Performer:        models.Profile{Name: "The Band"},
+			PerformerID:      uuid.New(),
+			GoogleResponseID: "response",
+			Members:          []models.Profile{{Name: "Drummer"}},
+			Answers:          models.Answers{"bio": json.RawMessage(`"We are The Band"`)},
+		}},
+		members: []models.UserID{reviewer, other},
+		rubric: models.Rubric{
+			Model:       models.Model{ID: uuid.New()},
+			Criteria:    models.Criteria{{Name: "craft", Weight: 1}},
+			ReviewerIDs: models.UUIDs{reviewer.ID},
+		},
+	}
+}
+
+func TestIsBlindReviewer(t *testing.T) {
+	tests := []struct {
+		name       string
+		firebaseID string
+		blind      bool
+		noRubric   bool
+		want       bool
+	}{
+		{name: "reviewer of a blind rubric", firebaseID: "reviewer", blind: true, want: true},
+		{name: "reviewer of an open rubric", firebaseID: "reviewer", blind: false, want: false},
+		{name: "member not assigned", firebaseID: "other", blind: true, want: false},
+		{name: "stranger", firebaseID: "stranger", blind: true, want: false},
+		{name: "no rubric", firebaseID: "reviewer", noRubric: true, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.rubric.Blind = test.blind
+			if test.noRubric {
+				repo.rubric = models.Rubric{}
+			}
+			service := NewService(repo, repo, repo, nil)
+			got, err := service.IsBlindReviewer(context.Background(), repo.event, test.firebaseID)
+			if err != nil {
+				t.Fatalf("IsBlindReviewer() error = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("IsBlindReviewer() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReviewQueueRedaction(t *testing.T) {
+	for _, blind := range []bool{false, true} {
+		repo := newFakeRepo()
+		repo.rubric.Blind = blind
+		service := NewService(repo, repo, repo, nil)
+		items, err := service.ReviewQueue(context.Background(), repo.event.ID, "reviewer")
+		if err != nil {
+			t.Fatalf("ReviewQueue() error = %v", err)
+		}
+		if len(items) != 1 {
+			t.Fatalf("ReviewQueue() returned %d items, want 1", len(items))
+		}
+		got, stored := items[0].Application, repo.applications[0]
+		if got.ID != stored.ID || got.Status != stored.Status {
+			t.Errorf("blind %v: the application lost its ID or status: %+v", blind, got)
+		}
+		revealed := got.Name != "" || got.Performer.Name != "" || got.PerformerID != uuid.Nil ||
+			got.GoogleResponseID != "" || len(got.Members) > 0 || len(got.Answers) > 0
+		if revealed == blind {
+			t.Errorf("blind %v: ReviewQueue() revealed the performer: %v (%+v)", blind, revealed, got)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package idempotency
+
+import (
+	"backend/models"
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Reserve stores record unless the caller already holds the key, in which case it returns the stored record and
+	// false. Expired records, and records not completed that were reserved before staleBefore, are replaced.
+	Reserve(ctx context.Context, record models.IdempotencyRecord, staleBefore time.Time) (models.IdempotencyRecord, bool, error)
+	// Complete and Release only touch the reservation record was made with.
+	Complete(ctx context.Context, record models.IdempotencyRecord) error
+	Release(ctx context.Context, record models.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+)
+
+const MaxKeyLength = 255
+
+var (
+	ErrKeyReused    = domain.Validation("idempotency_key_reused", "Idempotency-Key was already used with a different request")
+	ErrInProgress   = domain.Conflict("idempotency_key_in_progress", "a request with this Idempotency-Key is still being processed")
+	ErrKeyMalformed = domain.BadRequest("idempotency_key_malformed", "Idempotency-Key must be between 1 and 255 characters")
+)
+
+type Service struct {
+	repo  Repository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewService(repository Repository, ttl time.Duration, lease time.Duration) Service {
+	if ttl <= 0 {
+		ttl = models.IdempotencyTTL
+	}
+	if lease <= 0 {
+		lease = models.IdempotencyLease
+	}
+	return Service{repo: repository, ttl: ttl, lease: lease}
+}
+
+// Begin claims key for the caller. It returns the stored response and true when the same request was already
+// handled, ErrKeyReused when the key came with a different request, and ErrInProgress while the first one runs. A
+// request that has held the key for longer than the lease without finishing is taken to have died, and the key
+// passes to this one.
+func (s *Service) Begin(ctx context.Context, caller string, key string, requestHash string) (models.IdempotencyRecord, bool, error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return models.IdempotencyRecord{}, false, ErrKeyMalformed
+	}
+	// postgres keeps microseconds, and CreatedAt has to match the stored reservation exactly
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	record := models.IdempotencyRecord{
+		Caller: caller, Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: now.Add(s.ttl),
+	}
+	stored, reserved, err := s.repo.Reserve(ctx, record, now.Add(-s.lease))
+	if err != nil {
+		return record, false, errors.Wrap(err, "db error")
+	}
+	switch {
+	case reserved:
+		return record, false, nil
+	case stored.RequestHash != requestHash:
+		return stored, false, ErrKeyReused
+	case !stored.Completed:
+		return stored, false, ErrInProgress
+	default:
+		return stored, true, nil
+	}
+}
+
+func (s *Service) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	record.Completed = true
+	if err := s.repo.Complete(ctx, record); err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	return nil
+}
+
+// Release forgets a key whose request failed, so a retry is handled afresh.
+func (s *Service) Release(ctx context.Context, record models.IdempotencyRecord) error {
+	if err := s.repo.Release(ctx, record); err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	return nil
+}
+
+// RunCleanup deletes expired keys every interval until ctx is done.
+func (s *Service) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.repo.DeleteExpired(ctx, time.Now()); err != nil {
+			log.Printf("idempotency cleanup failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
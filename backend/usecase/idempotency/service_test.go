@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordKey struct{ caller, key string }
+
+// fakeRepo reserves keys in memory the way the database does: Complete and Release only touch the reservation they
+// were given, told apart by CreatedAt.
+type fakeRepo struct {
+	records map[recordKey]models.IdempotencyRecord
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{records: map[recordKey]models.IdempotencyRecord{}}
+}
+
+func (r *fakeRepo) Reserve(_ context.Context, record models.IdempotencyRecord, staleBefore time.Time) (models.IdempotencyRecord, bool, error) {
+	k := recordKey{record.Caller, record.Key}
+	stored, ok := r.records[k]
+	if ok && stored.ExpiresAt.After(record.CreatedAt) && (stored.Completed || !stored.CreatedAt.Before(staleBefore)) {
+		return stored, false, nil
+	}
+	r.records[k] = record
+	return record, true, nil
+}
+
+func (r *fakeRepo) Complete(_ context.Context, record models.IdempotencyRecord) error {
+	k := recordKey{record.Caller, record.Key}
+	if stored, ok := r.records[k]; ok && stored.CreatedAt.Equal(record.CreatedAt) {
+		r.records[k] = record
+	}
+	return nil
+}
+
+func (r *fakeRepo) Release(_ context.Context, record models.IdempotencyRecord) error {
+	k := recordKey{record.Caller, record.Key}
+	if stored, ok := r.records[k]; ok && stored.CreatedAt.Equal(record.CreatedAt) {
+		delete(r.records, k)
+	}
+	return nil
+}
+
+func (r *fakeRepo) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestBeginMalformedKey(t *testing.T) {
+	service := NewService(newFakeRepo(), 0, 0)
+	for _, key := range []string{"", strings.Repeat("k", MaxKeyLength+1)} {
+		if _, _, err := service.Begin(context.Background(), "caller", key, "hash"); !errors.Is(err, ErrKeyMalformed) {
+			t.Errorf("Begin() with a %d character key error = %v, want %v", len(key), err, ErrKeyMalformed)
+		}
+	}
+}
+
+func TestBeginReplay(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newFakeRepo(), 0, 0)
+	first, replay, err := service.Begin(ctx, "caller", "key", "hash")
+	if err != nil || replay {
+		t.Fatalf("first Begin() = replay %v, error %v", replay, err)
+	}
+	if _, _, err := service.Begin(ctx, "caller", "key", "hash"); !errors.Is(err, ErrInProgress) {
+		t.Errorf("Begin() while the first request runs error = %v, want %v", err, ErrInProgress)
+	}
+	if _, _, err := service.Begin(ctx, "caller", "key", "other"); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("Begin() with a different request error = %v, want %v", err, ErrKeyReused)
+	}
+	if _, replay, err := service.Begin(ctx, "other caller", "key", "hash"); err != nil || replay {
+		t.Errorf("Begin() by another caller = replay %v, error %v, want a fresh reservation", replay, err)
+	}
+	first.StatusCode, first.Body = 201, []byte("created")
+	if err := service.Complete(ctx, first); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	stored, replay, err := service.Begin(ctx, "caller", "key", "hash")
+	if err != nil || !replay {
+		t.Fatalf("Begin() after Complete() = replay %v, error %v, want a replay", replay, err)
+	}
+	if stored.StatusCode != 201 || string(stored.Body) != "created" {
+		t.Errorf("replayed %d %q, want 201 %q", stored.StatusCode, stored.Body, "created")
+	}
+}
+
+func TestBeginAfterRelease(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newFakeRepo(), 0, 0)
+	first, _, err := service.Begin(ctx, "caller", "key", "hash")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := service.Release(ctx, first); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, replay, err := service.Begin(ctx, "caller", "key", "other"); err != nil || replay {
+		t.Errorf("Begin() after Release() = replay %v, error %v, want a fresh reservation", replay, err)
+	}
+}
+
+func TestBeginTakesOverStaleKey(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	service := NewService(repo, 0, time.Nanosecond)
+	first, _, err := service.Begin(ctx, "caller", "key", "hash")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	second, replay, err := service.Begin(ctx, "caller", "key", "hash")
+	if err != nil || replay {
+		t.Fatalf("Begin() past the lease = replay %v, error %v, want a fresh reservation", replay, err)
+	}
+	// the request that lost the key can no longer finish it
+	first.StatusCode = 500
+	if err := service.Complete(ctx, first); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := service.Release(ctx, first); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	stored := repo.records[recordKey{"caller", "key"}]
+	if !stored.CreatedAt.Equal(second.CreatedAt) || stored.Completed {
+		t.Errorf("stale request changed the key to %+v", stored)
+	}
+}
@@ -0,0 +1,117 @@
+package imports
+
+import (
+	"backend/domain"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MaxRows bounds a single import; larger seasons have to be split.
+const MaxRows = 5000
+
+var (
+	ErrEmpty       = domain.Validation("empty_import", "the file has no header row")
+	ErrTooManyRows = domain.Validation("too_many_rows", fmt.Sprintf("an import is limited to %d rows", MaxRows))
+	ErrColumns     = domain.Validation("invalid_columns", "the header row does not match the expected columns")
+)
+
+// row is one record of the file, keyed by lower-cased column name. line is where the record starts, counting the
+// header as line 1, so it matches what a spreadsheet shows.
+type row struct {
+	line   int
+	values map[string]string
+}
+
+func (r row) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// readCSV reads a header row naming a subset of columns, in any order and any case, and the records under it.
+// Blank records are skipped.
+func readCSV(r io.Reader, columns []string, required ...string) ([]row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, domain.ErrBadRequest.WithMessage(err.Error()).Wrap(err)
+	}
+
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[column] = true
+	}
+	var problems []domain.FieldError
+	seen := map[string]bool{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		header[i] = name
+		switch {
+		case !known[name]:
+			problems = append(problems, domain.FieldError{
+				Field: name, Message: "unknown column. Allowed: " + strings.Join(columns, ", "),
+			})
+		case seen[name]:
+			problems = append(problems, domain.FieldError{Field: name, Message: "appears more than once"})
+		}
+		seen[name] = true
+	}
+	for _, name := range required {
+		if !seen[name] {
+			problems = append(problems, domain.FieldError{Field: name, Message: "column is required"})
+		}
+	}
+	if len(problems) > 0 {
+		return nil, ErrColumns.WithFields(problems...)
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, domain.ErrBadRequest.WithMessage(err.Error()).Wrap(err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		line, _ := reader.FieldPos(0)
+		values := make(map[string]string, len(header))
+		for i, name := range header {
+			values[name] = record[i]
+		}
+		rows = append(rows, row{line: line, values: values})
+	}
+}
+
+// timeLayouts are tried in order. Times without an offset, as spreadsheets usually write them, are taken as UTC.
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02 15:04:05"}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitList splits a cell holding several values separated by semicolons or, for URLs, whitespace.
+func splitList(s string, whitespace bool) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || (whitespace && (r == ' ' || r == '\t' || r == '\n' || r == '\r'))
+	})
+}
@@ -0,0 +1,63 @@
+package imports
+
+import (
+	"backend/domain"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	columns := []string{"name", "time", "location"}
+	tests := []struct {
+		name    string
+		in      string
+		want    []row
+		wantErr error
+	}{
+		{name: "empty", in: "", wantErr: ErrEmpty},
+		{name: "header only", in: "name,time\n", want: nil},
+		{
+			name: "columns in any order and case",
+			in:   "Time, NAME\n2024-01-01T20:00,Open mic\n",
+			want: []row{{line: 2, values: map[string]string{"time": "2024-01-01T20:00", "name": "Open mic"}}},
+		},
+		{
+			name: "byte order mark",
+			in:   "\ufeffname,time\nOpen mic,2024-01-01T20:00\n",
+			want: []row{{line: 2, values: map[string]string{"name": "Open mic", "time": "2024-01-01T20:00"}}},
+		},
+		{
+			name: "blank records are skipped and lines kept",
+			in:   "name,time\n,\nOpen mic,2024-01-01T20:00\n\"Late\nshow\",2024-01-02T22:00\n",
+			want: []row{
+				{line: 3, values: map[string]string{"name": "Open mic", "time": "2024-01-01T20:00"}},
+				{line: 4, values: map[string]string{"name": "Late\nshow", "time": "2024-01-02T22:00"}},
+			},
+		},
+		{name: "unknown column", in: "name,time,color\n", wantErr: ErrColumns},
+		{name: "repeated column", in: "name,time,name\n", wantErr: ErrColumns},
+		{name: "missing required column", in: "name,location\n", wantErr: ErrColumns},
+		{name: "ragged record", in: "name,time\nOpen mic\n", wantErr: domain.ErrBadRequest},
+		{name: "broken quoting", in: "name,time\n\"Open mic,2024\n", wantErr: domain.ErrBadRequest},
+		{name: "too many rows", in: "name,time\n" + strings.Repeat("a,b\n", MaxRows+1), wantErr: ErrTooManyRows},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := readCSV(strings.NewReader(test.in), columns, "name", "time")
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("readCSV() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, test.want) {
+				t.Errorf("readCSV() = %+v, want %+v", rows, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package imports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+)
+
+// Transactor runs fn in a transaction that repository calls made with the context it is given take part in. Nested
+// calls must be able to fail on their own without aborting the outer transaction.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (gormGIS.GeoPoint, error)
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
+}
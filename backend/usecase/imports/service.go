@@ -0,0 +1,338 @@
+package imports
+
+import (
+	"backend/domain"
+	"backend/models"
+	"backend/usecase/agenda"
+	"backend/usecase/users"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"github.com/pkg/errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	EventColumns   = []string{"name", "description", "time", "apply_by_time", "location", "venue", "tags", "status", "pay"}
+	ProfileColumns = []string{"name", "type", "location", "portfolio_links"}
+
+	ErrNotProducer = domain.Validation("not_a_producer", "imports belong to a producer profile")
+	ErrNoOwner     = domain.Conflict("no_owner", "the producer has no admin to own the imported profiles")
+	// ErrRejected carries every row error of an import that was not committed, named rows[<line>].<field>.
+	ErrRejected = domain.Validation("import_rejected", "nothing was imported because some rows are invalid")
+
+	errRolledBack = errors.New("import rolled back")
+)
+
+// MaxGeocodedAddresses bounds how many distinct addresses one import geocodes, as geocoding is throttled to about one a
+// second; past it, locations must be given as "lat,lng".
+const MaxGeocodedAddresses = 20
+
+var coordinatesPattern = regexp.MustCompile(`^(-?[0-9]+(?:\.[0-9]+)?)\s*,\s*(-?[0-9]+(?:\.[0-9]+)?)$`)
+
+// RowResult is what happened to one row. ID is only set once the import has been committed.
+type RowResult struct {
+	Line   int                 `json:"line"`
+	ID     *uuid.UUID          `json:"id,omitempty"`
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+type Report struct {
+	DryRun    bool        `json:"dry_run"`
+	Committed bool        `json:"committed"`
+	Failed    int         `json:"failed"`
+	Rows      []RowResult `json:"rows"`
+}
+
+type Service struct {
+	events     agenda.Repository
+	profiles   users.Repository
+	transactor Transactor
+	geocoder   Geocoder
+	auditor    Auditor
+}
+
+// NewService takes a nil geocoder when none is configured; locations must then be given as "lat,lng".
+func NewService(
+	events agenda.Repository, profiles users.Repository, transactor Transactor, geocoder Geocoder, auditor Auditor,
+) Service {
+	return Service{events: events, profiles: profiles, transactor: transactor, geocoder: geocoder, auditor: auditor}
+}
+
+// pending is a parsed row: either the errors that keep it from being created, or how to create it.
+type pending struct {
+	line   int
+	errors []domain.FieldError
+	create func(ctx context.Context) (uuid.UUID, error)
+}
+
+func fieldsOf(err error) []domain.FieldError {
+	if err == nil {
+		return nil
+	}
+	if e, ok := domain.As(err); ok && len(e.Fields) > 0 {
+		return e.Fields
+	}
+	return []domain.FieldError{{Field: "row", Message: err.Error()}}
+}
+
+func (s *Service) producer(ctx context.Context, producerID uuid.UUID) error {
+	producer, err := s.profiles.GetProfileByID(ctx, producerID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	if producer.ProfileType != models.ProducerType {
+		return ErrNotProducer
+	}
+	return nil
+}
+
+// locator turns a location cell into a point, geocoding each address at most once per import and at most
+// MaxGeocodedAddresses addresses in all.
+type locator struct {
+	ctx      context.Context
+	geocoder Geocoder
+	cache    map[string]gormGIS.GeoPoint
+	geocoded int
+}
+
+func (l *locator) locate(v *models.Validator, s string) *gormGIS.GeoPoint {
+	if m := coordinatesPattern.FindStringSubmatch(s); m != nil {
+		lat, _ := strconv.ParseFloat(m[1], 64)
+		lng, _ := strconv.ParseFloat(m[2], 64)
+		return &gormGIS.GeoPoint{Lat: lat, Lng: lng}
+	}
+	if l.geocoder == nil {
+		v.Add("location", `must be "lat,lng", addresses cannot be geocoded here`)
+		return nil
+	}
+	key := strings.ToLower(s)
+	if point, ok := l.cache[key]; ok {
+		return &point
+	}
+	if l.geocoded >= MaxGeocodedAddresses {
+		v.Add("location", `must be "lat,lng", an import geocodes at most %d addresses`, MaxGeocodedAddresses)
+		return nil
+	}
+	l.geocoded++
+	point, err := l.geocoder.Geocode(l.ctx, s)
+	if err != nil {
+		v.Add("location", "unable to geocode %q: %v", s, err)
+		return nil
+	}
+	l.cache[key] = point
+	return &point
+}
+
+// ImportEvents creates the events of a CSV file for the producer, with the columns in EventColumns. Location is
+// "lat,lng" or an address and defaults to the venue's; tags are separated by semicolons and, like venues, matched by
+// name. Either every row is created or none is: a dry run, or any invalid row, rolls the whole import back, and the
+// report says row by row what failed.
+func (s *Service) ImportEvents(ctx context.Context, producerID uuid.UUID, r io.Reader, dryRun bool) (Report, error) {
+	if err := s.producer(ctx, producerID); err != nil {
+		return Report{}, err
+	}
+	rows, err := readCSV(r, EventColumns, "name", "time")
+	if err != nil {
+		return Report{}, err
+	}
+	tags, err := s.events.ListTags(ctx)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "db error")
+	}
+	tagsByName := map[string]models.Tag{}
+	for _, tag := range tags {
+		tagsByName[strings.ToLower(tag.Name)] = tag
+	}
+	profiles, err := s.profiles.ListProfiles(ctx)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "db error")
+	}
+	venuesByName := map[string][]models.Profile{}
+	for _, profile := range profiles {
+		if profile.ProfileType == models.VenueType {
+			name := strings.ToLower(profile.Name)
+			venuesByName[name] = append(venuesByName[name], profile)
+		}
+	}
+
+	l := &locator{ctx: ctx, geocoder: s.geocoder, cache: map[string]gormGIS.GeoPoint{}}
+	parsed := make([]pending, 0, len(rows))
+	for _, row := range rows {
+		var v models.Validator
+		event := models.Event{
+			Name:        row.get("name"),
+			Description: row.get("description"),
+			ProducerID:  producerID,
+			Status:      models.EventApplicationStatus(strings.ToLower(row.get("status"))),
+			Pay:         models.ParseLegacyPayStructure(row.get("pay")),
+		}
+		if t, ok := parseTime(row.get("time")); ok {
+			event.Time = t
+		} else if row.get("time") != "" {
+			v.Add("time", "invalid time %q, expected RFC 3339 or YYYY-MM-DD HH:MM", row.get("time"))
+		}
+		if raw := row.get("apply_by_time"); raw != "" {
+			if t, ok := parseTime(raw); ok {
+				event.ApplyByTime = &t
+			} else {
+				v.Add("apply_by_time", "invalid time %q, expected RFC 3339 or YYYY-MM-DD HH:MM", raw)
+			}
+		}
+		if name := row.get("venue"); name != "" {
+			switch venues := venuesByName[strings.ToLower(name)]; len(venues) {
+			case 0:
+				v.Add("venue", "no venue is named %q", name)
+			case 1:
+				event.VenueID = &venues[0].ID
+				if venues[0].Location != nil {
+					event.Location = *venues[0].Location
+				}
+			default:
+				v.Add("venue", "%d venues are named %q", len(venues), name)
+			}
+		}
+		if raw := row.get("location"); raw != "" {
+			if point := l.locate(&v, raw); point != nil {
+				event.Location = *point
+			}
+		}
+		for _, name := range splitList(row.get("tags"), false) {
+			name = strings.TrimSpace(name)
+			if tag, ok := tagsByName[strings.ToLower(name)]; ok {
+				event.Tags = append(event.Tags, tag)
+			} else if name != "" {
+				v.Add("tags", "no tag is named %q", name)
+			}
+		}
+		errs := fieldsOf(v.Err())
+		if len(errs) == 0 {
+			errs = fieldsOf(agenda.ValidateEvent(event))
+		}
+		parsed = append(parsed, pending{line: row.line, errors: errs, create: func(ctx context.Context) (uuid.UUID, error) {
+			id, err := s.events.CreateEvent(ctx, event)
+			if err != nil {
+				return uuid.Nil, errors.Wrap(err, "db error")
+			}
+			event.ID = id
+			return id, s.auditor.Record(ctx, "event.import", "event", id.String(), nil, event, producerID)
+		}})
+	}
+	return s.commit(ctx, dryRun, parsed)
+}
+
+// ImportProfiles creates a producer's roster of past performers from a CSV file with the columns in ProfileColumns.
+// Type defaults to performer and portfolio links are separated by whitespace or semicolons. The producer's admins,
+// including those of its organization, become the admins of every imported profile, so someone owns it until an
+// operator adds the performer as a member and revokes them; a producer without admins cannot import. It commits all or
+// nothing like ImportEvents.
+func (s *Service) ImportProfiles(ctx context.Context, producerID uuid.UUID, r io.Reader, dryRun bool) (Report, error) {
+	if err := s.producer(ctx, producerID); err != nil {
+		return Report{}, err
+	}
+	members, err := s.profiles.GetAuthorizedUsers(ctx, producerID)
+	if err != nil {
+		return Report{}, errors.Wrap(err, "db error")
+	}
+	var owners []string
+	seen := map[string]bool{}
+	for _, member := range members {
+		if member.Permissions == models.Admin && !seen[member.FirebaseId] {
+			seen[member.FirebaseId] = true
+			owners = append(owners, member.FirebaseId)
+		}
+	}
+	if len(owners) == 0 {
+		return Report{}, ErrNoOwner
+	}
+	rows, err := readCSV(r, ProfileColumns, "name")
+	if err != nil {
+		return Report{}, err
+	}
+
+	l := &locator{ctx: ctx, geocoder: s.geocoder, cache: map[string]gormGIS.GeoPoint{}}
+	parsed := make([]pending, 0, len(rows))
+	for _, row := range rows {
+		var v models.Validator
+		profile := models.Profile{
+			Name:           row.get("name"),
+			ProfileType:    models.PerformerType,
+			PortfolioLinks: splitList(row.get("portfolio_links"), true),
+		}
+		if raw := row.get("type"); raw != "" {
+			profile.ProfileType = models.ProfileType(strings.ToLower(raw))
+		}
+		if raw := row.get("location"); raw != "" {
+			profile.Location = l.locate(&v, raw)
+		}
+		errs := fieldsOf(v.Err())
+		if len(errs) == 0 {
+			errs = fieldsOf(users.ValidateProfile(profile))
+		}
+		parsed = append(parsed, pending{line: row.line, errors: errs, create: func(ctx context.Context) (uuid.UUID, error) {
+			id, err := s.profiles.CreateProfile(ctx, profile)
+			if err != nil {
+				return uuid.Nil, errors.Wrap(err, "db error")
+			}
+			profile.ID = id
+			for _, owner := range owners {
+				if _, err := s.profiles.CreateUserID(ctx, models.UserID{FirebaseId: owner, Permissions: models.Admin, ProfileId: id}); err != nil {
+					return uuid.Nil, errors.Wrap(err, "db error")
+				}
+			}
+			return id, s.auditor.Record(ctx, "profile.import", "profile", id.String(), nil, profile, id, producerID)
+		}})
+	}
+	return s.commit(ctx, dryRun, parsed)
+}
+
+// commit creates the valid rows in one transaction, each under its own savepoint so a row the database refuses is
+// reported rather than aborting the rest. It rolls everything back on a dry run or if any row failed.
+func (s *Service) commit(ctx context.Context, dryRun bool, rows []pending) (Report, error) {
+	report := Report{DryRun: dryRun, Rows: make([]RowResult, 0, len(rows))}
+	err := s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		for _, row := range rows {
+			result := RowResult{Line: row.line, Errors: row.errors}
+			if len(result.Errors) == 0 {
+				var id uuid.UUID
+				err := s.transactor.InTransaction(ctx, func(ctx context.Context) (err error) {
+					id, err = row.create(ctx)
+					return err
+				})
+				if e, ok := domain.As(err); ok && e.Kind != domain.KindInternal {
+					result.Errors = fieldsOf(e)
+				} else if err != nil {
+					return err
+				} else if !dryRun {
+					result.ID = &id
+				}
+			}
+			if len(result.Errors) > 0 {
+				report.Failed++
+			}
+			report.Rows = append(report.Rows, result)
+		}
+		if dryRun || report.Failed > 0 {
+			return errRolledBack
+		}
+		return nil
+	})
+	if err != nil && err != errRolledBack {
+		return Report{}, err
+	}
+	report.Committed = err == nil
+	if report.Failed > 0 && !dryRun {
+		var fields []domain.FieldError
+		for _, row := range report.Rows {
+			for _, e := range row.Errors {
+				fields = append(fields, domain.FieldError{Field: fmt.Sprintf("rows[%d].%s", row.Line, e.Field), Message: e.Message})
+			}
+		}
+		return report, ErrRejected.WithFields(fields...)
+	}
+	return report, nil
+}
@@ -0,0 +1,184 @@
+package imports
+
+import (
+	"backend/models"
+	"backend/usecase/users"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"strings"
+	"testing"
+)
+
+// fakeGeocoder places every address at a latitude of its call count and fails on "nowhere".
+type fakeGeocoder struct {
+	calls int
+}
+
+func (g *fakeGeocoder) Geocode(_ context.Context, address string) (gormGIS.GeoPoint, error) {
+	g.calls++
+	if address == "nowhere" {
+		return gormGIS.GeoPoint{}, errors.New("no match")
+	}
+	return gormGIS.GeoPoint{Lat: float64(g.calls)}, nil
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *gormGIS.GeoPoint
+		wantErr bool
+	}{
+		{name: "coordinates", in: "52.5, -13.4", want: &gormGIS.GeoPoint{Lat: 52.5, Lng: -13.4}},
+		{name: "address", in: "1 Main St", want: &gormGIS.GeoPoint{Lat: 1}},
+		{name: "unknown address", in: "nowhere", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := &locator{ctx: context.Background(), geocoder: &fakeGeocoder{}, cache: map[string]gormGIS.GeoPoint{}}
+			var v models.Validator
+			got := l.locate(&v, test.in)
+			if (v.Err() != nil) != test.wantErr {
+				t.Fatalf("locate(%q) error = %v, want an error: %v", test.in, v.Err(), test.wantErr)
+			}
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("locate(%q) = %v, want %v", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLocateWithoutGeocoder(t *testing.T) {
+	l := &locator{ctx: context.Background(), cache: map[string]gormGIS.GeoPoint{}}
+	var v models.Validator
+	if got := l.locate(&v, "1 Main St"); got != nil || v.Err() == nil {
+		t.Errorf("locate() without a geocoder = %v, %v, want an error", got, v.Err())
+	}
+}
+
+func TestLocateGeocodesEachAddressOnceUpToTheCap(t *testing.T) {
+	geocoder := &fakeGeocoder{}
+	l := &locator{ctx: context.Background(), geocoder: geocoder, cache: map[string]gormGIS.GeoPoint{}}
+	for i := 0; i < MaxGeocodedAddresses; i++ {
+		var v models.Validator
+		address := fmt.Sprintf("%d Main St", i)
+		first := l.locate(&v, address)
+		again := l.locate(&v, fmt.Sprintf("%d MAIN ST", i))
+		if err := v.Err(); err != nil {
+			t.Fatalf("locate(%q) error = %v", address, err)
+		}
+		if first == nil || again == nil || *first != *again {
+			t.Fatalf("locate(%q) = %v, then %v, want the cached point", address, first, again)
+		}
+	}
+	if geocoder.calls != MaxGeocodedAddresses {
+		t.Errorf("geocoded %d times, want %d", geocoder.calls, MaxGeocodedAddresses)
+	}
+
+	var v models.Validator
+	if got := l.locate(&v, "one address too many"); got != nil || v.Err() == nil {
+		t.Errorf("locate() past the cap = %v, %v, want an error", got, v.Err())
+	}
+	if got := l.locate(&v, "0 Main St"); got == nil {
+		t.Errorf("locate() of a cached address past the cap = nil, want the cached point")
+	}
+	if geocoder.calls != MaxGeocodedAddresses {
+		t.Errorf("geocoded %d times past the cap, want none", geocoder.calls-MaxGeocodedAddresses)
+	}
+}
+
+// fakeProfiles serves one producer with the given authorized users and records the profiles and members created.
+type fakeProfiles struct {
+	users.Repository
+	producer   models.Profile
+	authorized []models.UserID
+	created    []models.Profile
+	owners     []models.UserID
+}
+
+func (p *fakeProfiles) GetProfileByID(context.Context, uuid.UUID) (models.Profile, error) {
+	return p.producer, nil
+}
+
+func (p *fakeProfiles) GetAuthorizedUsers(context.Context, uuid.UUID) ([]models.UserID, error) {
+	return p.authorized, nil
+}
+
+func (p *fakeProfiles) CreateProfile(_ context.Context, profile models.Profile) (uuid.UUID, error) {
+	profile.ID = uuid.New()
+	p.created = append(p.created, profile)
+	return profile.ID, nil
+}
+
+func (p *fakeProfiles) CreateUserID(_ context.Context, user models.UserID) (uuid.UUID, error) {
+	p.owners = append(p.owners, user)
+	return uuid.New(), nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditor struct{}
+
+func (fakeAuditor) Record(context.Context, string, string, string, interface{}, interface{}, ...uuid.UUID) error {
+	return nil
+}
+
+func TestImportProfilesOwners(t *testing.T) {
+	producer := models.Profile{Model: models.Model{ID: uuid.New()}, ProfileType: models.ProducerType}
+	tests := []struct {
+		name       string
+		authorized []models.UserID
+		want       []string
+		wantErr    error
+	}{
+		{
+			name: "member and organization admins",
+			authorized: []models.UserID{
+				{FirebaseId: "member-admin", Permissions: models.Admin},
+				{FirebaseId: "member", Permissions: models.Restricted},
+				{FirebaseId: "org-admin", Permissions: models.Admin},
+				{FirebaseId: "member-admin", Permissions: models.Admin},
+			},
+			want: []string{"member-admin", "org-admin"},
+		},
+		{
+			name:       "no admins",
+			authorized: []models.UserID{{FirebaseId: "member", Permissions: models.Restricted}},
+			wantErr:    ErrNoOwner,
+		},
+		{name: "no members", wantErr: ErrNoOwner},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profiles := &fakeProfiles{producer: producer, authorized: test.authorized}
+			service := NewService(nil, profiles, fakeTransactor{}, nil, fakeAuditor{})
+			_, err := service.ImportProfiles(context.Background(), producer.ID, strings.NewReader("name\nThe Band\n"), false)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("ImportProfiles() error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				if len(profiles.created) != 0 {
+					t.Errorf("ImportProfiles() created %d profiles without an owner", len(profiles.created))
+				}
+				return
+			}
+			var got []string
+			for _, owner := range profiles.owners {
+				if owner.Permissions != models.Admin || owner.ProfileId != profiles.created[0].ID {
+					t.Errorf("owner %+v is not an admin of the imported profile", owner)
+				}
+				got = append(got, owner.FirebaseId)
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("owners = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
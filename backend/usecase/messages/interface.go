@@ -0,0 +1,36 @@
+package messages
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	CreateMessage(ctx context.Context, message models.Message) (models.Message, error)
+	GetMessagesByApplication(ctx context.Context, applicationID uuid.UUID) ([]models.Message, error)
+}
+
+type AgendaRepository interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
+}
+
+type ProfileRepository interface {
+	// GetAuthorizedUsers counts the admins of the profile's organization among its members.
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event models.StreamEvent) error
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
+}
@@ -0,0 +1,104 @@
+package messages
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"log"
+)
+
+var (
+	ErrNotParty  = domain.Validation("not_application_party", "messages are sent for the performer or the producer of the event")
+	ErrNotMember = domain.Forbidden("not_profile_member", "caller is not a member of the profile the message is sent for")
+)
+
+type Service struct {
+	repo      Repository
+	agenda    AgendaRepository
+	profiles  ProfileRepository
+	publisher Publisher
+	auditor   Auditor
+}
+
+func NewService(
+	repository Repository, agenda AgendaRepository, profiles ProfileRepository, publisher Publisher, auditor Auditor,
+) Service {
+	return Service{repo: repository, agenda: agenda, profiles: profiles, publisher: publisher, auditor: auditor}
+}
+
+func (s *Service) isMember(ctx context.Context, profileID uuid.UUID, firebaseID string) (bool, error) {
+	members, err := s.profiles.GetAuthorizedUsers(ctx, profileID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	for _, member := range members {
+		if member.FirebaseId == firebaseID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Send posts a message on the application for request.ProfileID, which has to be the performer or the event's
+// producer. The super user, who has no firebase UID, may write for either.
+func (s *Service) Send(
+	ctx context.Context, applicationID uuid.UUID, request models.MessageRequest, firebaseID string,
+) (models.Message, error) {
+	application, err := s.agenda.GetApplication(ctx, applicationID)
+	if err != nil {
+		return models.Message{}, errors.Wrap(err, "db error")
+	}
+	event, err := s.agenda.GetEvent(ctx, application.EventRef)
+	if err != nil {
+		return models.Message{}, errors.Wrap(err, "db error")
+	}
+	if request.ProfileID != application.PerformerID && request.ProfileID != event.ProducerID {
+		return models.Message{}, ErrNotParty
+	}
+	if firebaseID != "" {
+		if ok, err := s.isMember(ctx, request.ProfileID, firebaseID); err != nil {
+			return models.Message{}, err
+		} else if !ok {
+			return models.Message{}, ErrNotMember
+		}
+	}
+	return s.Post(ctx, event, application, models.Message{
+		ApplicationID: application.ID, AuthorID: request.ProfileID, SenderUID: firebaseID, Body: request.Body,
+	})
+}
+
+// Post stores a message that has already been authorized and tells both sides of the application about it.
+func (s *Service) Post(
+	ctx context.Context, event models.Event, application models.Application, message models.Message,
+) (models.Message, error) {
+	audience := []uuid.UUID{application.PerformerID, event.ProducerID}
+	var out models.Message
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.CreateMessage(ctx, message); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		if err := s.auditor.Record(ctx, "message.create", "message", out.ID.String(), nil, out, audience...); err != nil {
+			return errors.Wrapf(err, "unable to audit message.create on message %s", out.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return message, err
+	}
+	if streamEvent, err := models.NewStreamEvent(models.StreamMessageCreated, out.ID, out, audience...); err != nil {
+		log.Printf("unable to build %s stream event: %v", models.StreamMessageCreated, err)
+	} else if err := s.publisher.Publish(ctx, streamEvent); err != nil {
+		log.Printf("unable to publish %s stream event: %v", models.StreamMessageCreated, err)
+	}
+	return out, nil
+}
+
+func (s *Service) GetMessages(ctx context.Context, applicationID uuid.UUID) ([]models.Message, error) {
+	messages, err := s.repo.GetMessagesByApplication(ctx, applicationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return messages, nil
+}
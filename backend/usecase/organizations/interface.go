@@ -0,0 +1,46 @@
+package organizations
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// CreateOrganization creates the organization and its admins in one transaction.
+	CreateOrganization(ctx context.Context, organization models.Organization, admins ...string) (models.Organization, error)
+	// GetOrganization loads the organization with the profiles it owns.
+	GetOrganization(ctx context.Context, id uuid.UUID) (models.Organization, error)
+	GetOrganizationsByFirebaseId(ctx context.Context, firebaseID string) ([]models.Organization, error)
+
+	// AddAdmin restores the admin if they were removed before.
+	AddAdmin(ctx context.Context, admin models.OrganizationAdmin) (models.OrganizationAdmin, error)
+	// GetAdmin returns the zero admin if the user does not administer the organization.
+	GetAdmin(ctx context.Context, organizationID uuid.UUID, firebaseID string) (models.OrganizationAdmin, error)
+	GetAdmins(ctx context.Context, organizationID uuid.UUID) ([]models.OrganizationAdmin, error)
+	RemoveAdmin(ctx context.Context, organizationID uuid.UUID, firebaseID string) error
+
+	// SetProfileOrganization moves the profile into the organization, or out of any for nil, and bumps its version.
+	SetProfileOrganization(ctx context.Context, profileID uuid.UUID, organizationID *uuid.UUID) (models.Profile, error)
+	// GetEvents lists the events produced by or held at the organization's profiles, soonest first.
+	GetEvents(ctx context.Context, organizationID uuid.UUID) ([]models.Event, error)
+	// GetApplications lists the applications to the events GetEvents lists, with their performers.
+	GetApplications(ctx context.Context, organizationID uuid.UUID) ([]models.Application, error)
+}
+
+type ProfileRepository interface {
+	GetProfileByID(ctx context.Context, id uuid.UUID) (models.Profile, error)
+	// GetUsersByProfileId returns the profile's own members; GetAuthorizedUsers adds the admins of its organization.
+	GetUsersByProfileId(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
+}
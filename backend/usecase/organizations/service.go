@@ -0,0 +1,275 @@
+package organizations
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+var (
+	ErrNotOwnable  = domain.Validation("profile_not_ownable", "only producer and venue profiles can belong to an organization")
+	ErrNotAdmin    = domain.NotFound("organization_admin_not_found", "the user is not an admin of this organization")
+	ErrLastAdmin   = domain.Conflict("last_organization_admin", "an organization must keep at least one admin")
+	ErrNotOwned    = domain.NotFound("profile_not_in_organization", "the profile does not belong to this organization")
+	ErrNoOwnAdmin  = domain.Conflict("profile_without_admin", "the profile has no admin of its own to hand it back to")
+	ErrProfileAuth = domain.Forbidden("not_profile_admin", "only an admin of the profile can bring it into an organization")
+)
+
+type Service struct {
+	repo     Repository
+	profiles ProfileRepository
+	auditor  Auditor
+}
+
+func NewService(repository Repository, profiles ProfileRepository, auditor Auditor) Service {
+	return Service{repo: repository, profiles: profiles, auditor: auditor}
+}
+
+// audit records the change with the context's transaction, so a change that cannot be audited is not made either.
+// Changes to an organization are also recorded against the profiles it owns, whose admins they affect.
+func (s *Service) audit(
+	ctx context.Context, action string, resourceType string, resourceID string, before interface{}, after interface{},
+	profileIDs ...uuid.UUID,
+) error {
+	if err := s.auditor.Record(ctx, action, resourceType, resourceID, before, after, profileIDs...); err != nil {
+		return errors.Wrapf(err, "unable to audit %s on %s %s", action, resourceType, resourceID)
+	}
+	return nil
+}
+
+func ownedProfiles(organization models.Organization) []uuid.UUID {
+	ids := make([]uuid.UUID, len(organization.Profiles))
+	for i, profile := range organization.Profiles {
+		ids[i] = profile.ID
+	}
+	return ids
+}
+
+// CreateOrganization creates an organization with the firebase user as its first admin. The super user, who has no
+// firebase ID, creates it without admins and adds them afterwards.
+func (s *Service) CreateOrganization(
+	ctx context.Context, organization models.Organization, firebaseID string,
+) (models.Organization, error) {
+	organization.Name = strings.TrimSpace(organization.Name)
+	var v models.Validator
+	v.Required("name", organization.Name)
+	if err := v.Err(); err != nil {
+		return organization, err
+	}
+	organization.Model, organization.Profiles = models.Model{}, nil
+	var admins []string
+	if firebaseID != "" {
+		admins = append(admins, firebaseID)
+	}
+	var out models.Organization
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.CreateOrganization(ctx, organization, admins...); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "organization.create", "organization", out.ID.String(), nil, out)
+	})
+	if err != nil {
+		return organization, err
+	}
+	return out, nil
+}
+
+func (s *Service) GetOrganization(ctx context.Context, id uuid.UUID) (models.Organization, error) {
+	organization, err := s.repo.GetOrganization(ctx, id)
+	if err != nil {
+		return organization, errors.Wrap(err, "db error")
+	}
+	return organization, nil
+}
+
+// GetOrganizations returns the organizations the firebase user administers.
+func (s *Service) GetOrganizations(ctx context.Context, firebaseID string) ([]models.Organization, error) {
+	organizations, err := s.repo.GetOrganizationsByFirebaseId(ctx, firebaseID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return organizations, nil
+}
+
+func (s *Service) IsAdmin(ctx context.Context, organizationID uuid.UUID, firebaseID string) (bool, error) {
+	admin, err := s.repo.GetAdmin(ctx, organizationID, firebaseID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	return admin.ID != uuid.Nil, nil
+}
+
+func (s *Service) GetAdmins(ctx context.Context, organizationID uuid.UUID) ([]models.OrganizationAdmin, error) {
+	admins, err := s.repo.GetAdmins(ctx, organizationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return admins, nil
+}
+
+// AddAdmin makes the firebase user an admin of the organization and so of every profile it owns.
+func (s *Service) AddAdmin(
+	ctx context.Context, organizationID uuid.UUID, firebaseID string,
+) (models.OrganizationAdmin, error) {
+	var v models.Validator
+	v.Required("firebase_id", firebaseID)
+	if err := v.Err(); err != nil {
+		return models.OrganizationAdmin{}, err
+	}
+	organization, err := s.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return models.OrganizationAdmin{}, errors.Wrap(err, "db error")
+	}
+	var out models.OrganizationAdmin
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		out, err = s.repo.AddAdmin(ctx, models.OrganizationAdmin{OrganizationID: organizationID, FirebaseId: firebaseID})
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "organization.admin.add", "organization", organizationID.String(), nil, out,
+			ownedProfiles(organization)...)
+	})
+	if err != nil {
+		return models.OrganizationAdmin{}, err
+	}
+	return out, nil
+}
+
+// RemoveAdmin takes the organization away from one of its admins, who keeps whatever membership they hold on its
+// profiles directly. The last admin cannot be removed.
+func (s *Service) RemoveAdmin(ctx context.Context, organizationID uuid.UUID, firebaseID string) error {
+	organization, err := s.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	admins, err := s.repo.GetAdmins(ctx, organizationID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	var previous models.OrganizationAdmin
+	for _, admin := range admins {
+		if admin.FirebaseId == firebaseID {
+			previous = admin
+		}
+	}
+	if previous.ID == uuid.Nil {
+		return ErrNotAdmin
+	}
+	if len(admins) == 1 {
+		return ErrLastAdmin
+	}
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.RemoveAdmin(ctx, organizationID, firebaseID); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "organization.admin.remove", "organization", organizationID.String(), previous, nil,
+			ownedProfiles(organization)...)
+	})
+}
+
+// isProfileAdmin tells whether the firebase user holds the admin permission on the profile, directly or through the
+// organization that owns it now.
+func (s *Service) isProfileAdmin(ctx context.Context, profileID uuid.UUID, firebaseID string) (bool, error) {
+	users, err := s.profiles.GetAuthorizedUsers(ctx, profileID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	for _, user := range users {
+		if user.FirebaseId == firebaseID && user.Permissions == models.Admin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddProfile brings a producer or venue profile into the organization, moving it out of any other. Both sides have
+// to agree: the caller administers the organization and, unless they are the super user with no firebase ID, the
+// profile too.
+func (s *Service) AddProfile(
+	ctx context.Context, organizationID uuid.UUID, profileID uuid.UUID, firebaseID string,
+) (models.Profile, error) {
+	if _, err := s.repo.GetOrganization(ctx, organizationID); err != nil {
+		return models.Profile{}, errors.Wrap(err, "db error")
+	}
+	previous, err := s.profiles.GetProfileByID(ctx, profileID)
+	if err != nil {
+		return previous, errors.Wrap(err, "db error")
+	}
+	if previous.ProfileType != models.ProducerType && previous.ProfileType != models.VenueType {
+		return previous, ErrNotOwnable
+	}
+	if firebaseID != "" {
+		if ok, err := s.isProfileAdmin(ctx, profileID, firebaseID); err != nil {
+			return previous, err
+		} else if !ok {
+			return previous, ErrProfileAuth
+		}
+	}
+	if previous.OrganizationID != nil && *previous.OrganizationID == organizationID {
+		return previous, nil
+	}
+	var out models.Profile
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.SetProfileOrganization(ctx, profileID, &organizationID); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		previous.Reputation = nil
+		return s.audit(ctx, "organization.profile.add", "profile", profileID.String(), previous, out, profileID)
+	})
+	if err != nil {
+		return previous, err
+	}
+	return out, nil
+}
+
+// RemoveProfile hands the profile back to its own members. A profile that only the organization's admins could
+// manage stays, so it is not left without anyone to run it.
+func (s *Service) RemoveProfile(ctx context.Context, organizationID uuid.UUID, profileID uuid.UUID) error {
+	previous, err := s.profiles.GetProfileByID(ctx, profileID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	if previous.OrganizationID == nil || *previous.OrganizationID != organizationID {
+		return ErrNotOwned
+	}
+	members, err := s.profiles.GetUsersByProfileId(ctx, profileID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	ownAdmin := false
+	for _, member := range members {
+		ownAdmin = ownAdmin || member.Permissions == models.Admin
+	}
+	if !ownAdmin {
+		return ErrNoOwnAdmin
+	}
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		out, err := s.repo.SetProfileOrganization(ctx, profileID, nil)
+		if err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		previous.Reputation = nil
+		return s.audit(ctx, "organization.profile.remove", "profile", profileID.String(), previous, out, profileID)
+	})
+}
+
+// GetEvents is the roll-up of the events of every brand and venue of the organization.
+func (s *Service) GetEvents(ctx context.Context, organizationID uuid.UUID) ([]models.Event, error) {
+	events, err := s.repo.GetEvents(ctx, organizationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return events, nil
+}
+
+// GetApplications is the roll-up of the applications to the organization's events.
+func (s *Service) GetApplications(ctx context.Context, organizationID uuid.UUID) ([]models.Application, error) {
+	applications, err := s.repo.GetApplications(ctx, organizationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return applications, nil
+}
@@ -0,0 +1,178 @@
+package organizations
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+// fakeRepo keeps one organization and its admins in memory and moves profiles in and out of it in fakeProfiles.
+type fakeRepo struct {
+	Repository
+	organization models.Organization
+	admins       []models.OrganizationAdmin
+	profiles     *fakeProfiles
+}
+
+func (r *fakeRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *fakeRepo) GetOrganization(context.Context, uuid.UUID) (models.Organization, error) {
+	return r.organization, nil
+}
+
+func (r *fakeRepo) GetAdmins(context.Context, uuid.UUID) ([]models.OrganizationAdmin, error) {
+	return r.admins, nil
+}
+
+func (r *fakeRepo) RemoveAdmin(_ context.Context, _ uuid.UUID, firebaseID string) error {
+	for i, admin := range r.admins {
+		if admin.FirebaseId == firebaseID {
+			r.admins = append(r.admins[:i], r.admins[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (r *fakeRepo) SetProfileOrganization(
+	_ context.Context, profileID uuid.UUID, organizationID *uuid.UUID,
+) (models.Profile, error) {
+	profile := r.profiles.profiles[profileID]
+	profile.OrganizationID = organizationID
+	profile.Version++
+	r.profiles.profiles[profileID] = profile
+	return profile, nil
+}
+
+// fakeProfiles resolves authorized users like the database does: the profile's own members, then the admins of the
+// organization that owns it as admins of the profile.
+type fakeProfiles struct {
+	ProfileRepository
+	profiles map[uuid.UUID]models.Profile
+	members  map[uuid.UUID][]models.UserID
+	repo     *fakeRepo
+}
+
+func (p *fakeProfiles) GetProfileByID(_ context.Context, id uuid.UUID) (models.Profile, error) {
+	return p.profiles[id], nil
+}
+
+func (p *fakeProfiles) GetUsersByProfileId(_ context.Context, id uuid.UUID) ([]models.UserID, error) {
+	return p.members[id], nil
+}
+
+func (p *fakeProfiles) GetAuthorizedUsers(_ context.Context, id uuid.UUID) ([]models.UserID, error) {
+	users := append([]models.UserID(nil), p.members[id]...)
+	if organizationID := p.profiles[id].OrganizationID; organizationID != nil && *organizationID == p.repo.organization.ID {
+		for _, admin := range p.repo.admins {
+			users = append(users, models.UserID{FirebaseId: admin.FirebaseId, Permissions: models.Admin, ProfileId: id})
+		}
+	}
+	return users, nil
+}
+
+type fakeAuditor struct{}
+
+func (fakeAuditor) Record(context.Context, string, string, string, interface{}, interface{}, ...uuid.UUID) error {
+	return nil
+}
+
+// organization sets up a service around an organization administered by "org-admin" and a producer, a venue and a
+// performer outside it. "owner" is an admin and "booker" a restricted member of each of the three profiles.
+func organization() (Service, *fakeRepo, map[string]uuid.UUID) {
+	ids := map[string]uuid.UUID{}
+	profiles := &fakeProfiles{profiles: map[uuid.UUID]models.Profile{}, members: map[uuid.UUID][]models.UserID{}}
+	repo := &fakeRepo{
+		organization: models.Organization{Model: models.Model{ID: uuid.New()}, Name: "company"},
+		admins:       []models.OrganizationAdmin{{Model: models.Model{ID: uuid.New()}, FirebaseId: "org-admin"}},
+		profiles:     profiles,
+	}
+	profiles.repo = repo
+	for name, profileType := range map[string]models.ProfileType{
+		"producer": models.ProducerType, "venue": models.VenueType, "performer": models.PerformerType,
+	} {
+		id := uuid.New()
+		profiles.profiles[id] = models.Profile{Model: models.Model{ID: id}, Name: name, ProfileType: profileType}
+		profiles.members[id] = []models.UserID{
+			{FirebaseId: "owner", Permissions: models.Admin, ProfileId: id},
+			{FirebaseId: "booker", Permissions: models.Restricted, ProfileId: id},
+		}
+		ids[name] = id
+	}
+	return NewService(repo, profiles, fakeAuditor{}), repo, ids
+}
+
+func TestAddProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    string
+		firebaseID string
+		wantErr    error
+	}{
+		{name: "producer by its admin", profile: "producer", firebaseID: "owner"},
+		{name: "venue by its admin", profile: "venue", firebaseID: "owner"},
+		{name: "super user", profile: "producer"},
+		{name: "restricted member", profile: "producer", firebaseID: "booker", wantErr: ErrProfileAuth},
+		{name: "organization admin alone", profile: "producer", firebaseID: "org-admin", wantErr: ErrProfileAuth},
+		{name: "performer", profile: "performer", firebaseID: "owner", wantErr: ErrNotOwnable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, repo, ids := organization()
+			out, err := service.AddProfile(context.Background(), repo.organization.ID, ids[test.profile], test.firebaseID)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("AddProfile() error = %v, want %v", err, test.wantErr)
+			}
+			stored := repo.profiles.profiles[ids[test.profile]]
+			if test.wantErr != nil {
+				if stored.OrganizationID != nil {
+					t.Errorf("a refused profile joined the organization")
+				}
+				return
+			}
+			if out.OrganizationID == nil || *out.OrganizationID != repo.organization.ID || stored.OrganizationID == nil {
+				t.Errorf("AddProfile() = organization %v, want %s", out.OrganizationID, repo.organization.ID)
+			}
+		})
+	}
+}
+
+func TestRemoveProfile(t *testing.T) {
+	service, repo, ids := organization()
+	ctx := context.Background()
+	if err := service.RemoveProfile(ctx, repo.organization.ID, ids["producer"]); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("RemoveProfile() of a profile outside the organization error = %v, want %v", err, ErrNotOwned)
+	}
+	if _, err := service.AddProfile(ctx, repo.organization.ID, ids["producer"], ""); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+	repo.profiles.members[ids["producer"]] = repo.profiles.members[ids["producer"]][1:]
+	if err := service.RemoveProfile(ctx, repo.organization.ID, ids["producer"]); !errors.Is(err, ErrNoOwnAdmin) {
+		t.Errorf("RemoveProfile() without an admin of its own error = %v, want %v", err, ErrNoOwnAdmin)
+	}
+	if repo.profiles.profiles[ids["producer"]].OrganizationID == nil {
+		t.Errorf("a profile nobody else could run left the organization")
+	}
+}
+
+func TestRemoveAdmin(t *testing.T) {
+	service, repo, _ := organization()
+	ctx := context.Background()
+	if err := service.RemoveAdmin(ctx, repo.organization.ID, "org-admin"); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("RemoveAdmin() of the last admin error = %v, want %v", err, ErrLastAdmin)
+	}
+	if err := service.RemoveAdmin(ctx, repo.organization.ID, "owner"); !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("RemoveAdmin() of someone who is not an admin error = %v, want %v", err, ErrNotAdmin)
+	}
+	repo.admins = append(repo.admins, models.OrganizationAdmin{Model: models.Model{ID: uuid.New()}, FirebaseId: "second"})
+	if err := service.RemoveAdmin(ctx, repo.organization.ID, "org-admin"); err != nil {
+		t.Fatalf("RemoveAdmin() error = %v", err)
+	}
+	if len(repo.admins) != 1 || repo.admins[0].FirebaseId != "second" {
+		t.Errorf("admins = %+v, want only the second", repo.admins)
+	}
+}
@@ -0,0 +1,32 @@
+package reviews
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	// CreateReview returns uuid.Nil when that side has already reviewed the application.
+	CreateReview(ctx context.Context, review models.Review) (uuid.UUID, error)
+	GetReview(ctx context.Context, id uuid.UUID) (models.Review, error)
+	// GetReviewByApplication returns a zero value when that side has not reviewed yet.
+	GetReviewByApplication(ctx context.Context, applicationID uuid.UUID, direction models.ReviewDirection) (models.Review, error)
+	// PublishReviews pulls the publication time of every review of the application forward to at.
+	PublishReviews(ctx context.Context, applicationID uuid.UUID, at time.Time) error
+	GetPublishedReviewsBySubject(ctx context.Context, subjectID uuid.UUID) ([]models.Review, error)
+	GetFlaggedReviews(ctx context.Context) ([]models.Review, error)
+	UpdateModeration(ctx context.Context, id uuid.UUID, status models.ModerationStatus, note string) (models.Review, error)
+	GetReputation(ctx context.Context, profileID uuid.UUID) (models.Reputation, error)
+}
+
+type AgendaRepository interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
+}
+
+type ProfileRepository interface {
+	// GetAuthorizedUsers counts the admins of the profile's organization among its members.
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+}
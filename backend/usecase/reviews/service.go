@@ -0,0 +1,174 @@
+package reviews
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"time"
+)
+
+var (
+	ErrNotReviewable   = domain.Conflict("application_not_accepted", "only accepted applications can be reviewed")
+	ErrEventNotOver    = domain.Conflict("event_not_over", "reviews open once the event has taken place")
+	ErrWindowClosed    = domain.Conflict("review_window_closed", "the review window for this event has closed")
+	ErrAlreadyReviewed = domain.Conflict("already_reviewed", "this side has already reviewed the application")
+	ErrNotParticipant  = domain.Forbidden("not_participant", "caller did not take part in this booking")
+	ErrInvalidRating   = domain.Validation("invalid_rating", "rating must be between 1 and 5")
+)
+
+type Service struct {
+	repo     Repository
+	agenda   AgendaRepository
+	profiles ProfileRepository
+}
+
+func NewService(repository Repository, agenda AgendaRepository, profiles ProfileRepository) Service {
+	return Service{repo: repository, agenda: agenda, profiles: profiles}
+}
+
+func (s *Service) isMember(ctx context.Context, profileID uuid.UUID, firebaseID string) (bool, error) {
+	members, err := s.profiles.GetAuthorizedUsers(ctx, profileID)
+	if err != nil {
+		return false, errors.Wrap(err, "db error")
+	}
+	for _, member := range members {
+		if member.FirebaseId == firebaseID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Submit records the firebase user's review of the other side of an accepted application. Which side they review
+// from follows from their membership: producer members review the performer and vice versa.
+func (s *Service) Submit(ctx context.Context, applicationID uuid.UUID, firebaseID string, review models.Review) (models.Review, error) {
+	if review.Rating < 1 || review.Rating > 5 {
+		return review, ErrInvalidRating
+	}
+	application, err := s.agenda.GetApplication(ctx, applicationID)
+	if err != nil {
+		return review, errors.Wrap(err, "db error")
+	}
+	if application.Status != models.StatusAccepted {
+		return review, ErrNotReviewable
+	}
+	event, err := s.agenda.GetEvent(ctx, application.EventRef)
+	if err != nil {
+		return review, errors.Wrap(err, "db error")
+	}
+	now := time.Now().UTC()
+	closes := event.Time.Add(models.ReviewWindow)
+	if now.Before(event.Time) {
+		return review, ErrEventNotOver
+	}
+	if now.After(closes) {
+		return review, ErrWindowClosed
+	}
+
+	review.ApplicationID = application.ID
+	review.EventRef = event.ID
+	review.SubmittedBy = firebaseID
+	review.Moderation = models.ModerationVisible
+	review.ModerationNote = ""
+	review.PublishedAt = closes
+	if ok, err := s.isMember(ctx, event.ProducerID, firebaseID); err != nil {
+		return review, err
+	} else if ok {
+		review.Direction = models.ProducerReviewsPerformer
+		review.ReviewerProfileID, review.SubjectProfileID = event.ProducerID, application.PerformerID
+	} else if ok, err := s.isMember(ctx, application.PerformerID, firebaseID); err != nil {
+		return review, err
+	} else if ok {
+		review.Direction = models.PerformerReviewsProducer
+		review.ReviewerProfileID, review.SubjectProfileID = application.PerformerID, event.ProducerID
+	} else {
+		return review, ErrNotParticipant
+	}
+
+	if existing, err := s.repo.GetReviewByApplication(ctx, application.ID, review.Direction); err != nil {
+		return review, errors.Wrap(err, "db error")
+	} else if existing.ID != uuid.Nil {
+		return existing, ErrAlreadyReviewed
+	}
+	if review.ID, err = s.repo.CreateReview(ctx, review); err != nil {
+		return review, errors.Wrap(err, "db error")
+	} else if review.ID == uuid.Nil {
+		// The other member of this side got there between the check above and the insert.
+		existing, err := s.repo.GetReviewByApplication(ctx, application.ID, review.Direction)
+		if err != nil {
+			return review, errors.Wrap(err, "db error")
+		}
+		return existing, ErrAlreadyReviewed
+	}
+
+	counterpart := models.PerformerReviewsProducer
+	if review.Direction == models.PerformerReviewsProducer {
+		counterpart = models.ProducerReviewsPerformer
+	}
+	if other, err := s.repo.GetReviewByApplication(ctx, application.ID, counterpart); err != nil {
+		return review, errors.Wrap(err, "db error")
+	} else if other.ID != uuid.Nil {
+		if err := s.repo.PublishReviews(ctx, application.ID, now); err != nil {
+			return review, errors.Wrap(err, "db error")
+		}
+		review.PublishedAt = now
+	}
+	return review, nil
+}
+
+func (s *Service) GetReviewsOfProfile(ctx context.Context, profileID uuid.UUID) ([]models.Review, error) {
+	reviews, err := s.repo.GetPublishedReviewsBySubject(ctx, profileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return reviews, nil
+}
+
+func (s *Service) GetReputation(ctx context.Context, profileID uuid.UUID) (models.Reputation, error) {
+	reputation, err := s.repo.GetReputation(ctx, profileID)
+	if err != nil {
+		return reputation, errors.Wrap(err, "db error")
+	}
+	return reputation, nil
+}
+
+// Flag queues a published review for moderation. Only the two sides of the booking may flag it.
+func (s *Service) Flag(ctx context.Context, id uuid.UUID, firebaseID string, reason string) (models.Review, error) {
+	review, err := s.repo.GetReview(ctx, id)
+	if err != nil {
+		return review, errors.Wrap(err, "db error")
+	}
+	allowed := false
+	for _, profileID := range []uuid.UUID{review.SubjectProfileID, review.ReviewerProfileID} {
+		if ok, err := s.isMember(ctx, profileID, firebaseID); err != nil {
+			return review, err
+		} else if ok {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return review, ErrNotParticipant
+	}
+	if review.Moderation != models.ModerationVisible {
+		return review, nil
+	}
+	return s.Moderate(ctx, id, models.ModerationFlagged, reason)
+}
+
+func (s *Service) Moderate(ctx context.Context, id uuid.UUID, status models.ModerationStatus, note string) (models.Review, error) {
+	review, err := s.repo.UpdateModeration(ctx, id, status, note)
+	if err != nil {
+		return review, errors.Wrap(err, "db error")
+	}
+	return review, nil
+}
+
+func (s *Service) GetFlaggedReviews(ctx context.Context) ([]models.Review, error) {
+	reviews, err := s.repo.GetFlaggedReviews(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return reviews, nil
+}
@@ -0,0 +1,184 @@
+package reviews
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeRepo keeps reviews in memory and, like the unique index, refuses a second review from the same side. Lookups
+// miss the first `stale` reviews stored, as if another request wrote them after the lookup.
+type fakeRepo struct {
+	Repository
+	reviews []models.Review
+	stale   int
+}
+
+func (r *fakeRepo) CreateReview(_ context.Context, review models.Review) (uuid.UUID, error) {
+	for _, stored := range r.reviews {
+		if stored.ApplicationID == review.ApplicationID && stored.Direction == review.Direction {
+			return uuid.Nil, nil
+		}
+	}
+	review.ID = uuid.New()
+	r.reviews = append(r.reviews, review)
+	return review.ID, nil
+}
+
+func (r *fakeRepo) GetReviewByApplication(
+	_ context.Context, applicationID uuid.UUID, direction models.ReviewDirection,
+) (models.Review, error) {
+	for _, stored := range r.reviews[r.stale:] {
+		if stored.ApplicationID == applicationID && stored.Direction == direction {
+			return stored, nil
+		}
+	}
+	r.stale = 0
+	return models.Review{}, nil
+}
+
+func (r *fakeRepo) PublishReviews(_ context.Context, applicationID uuid.UUID, at time.Time) error {
+	for i := range r.reviews {
+		if r.reviews[i].ApplicationID == applicationID && r.reviews[i].PublishedAt.After(at) {
+			r.reviews[i].PublishedAt = at
+		}
+	}
+	return nil
+}
+
+type fakeAgenda struct {
+	AgendaRepository
+	event       models.Event
+	application models.Application
+}
+
+func (a *fakeAgenda) GetEvent(context.Context, uuid.UUID) (models.Event, error) {
+	return a.event, nil
+}
+
+func (a *fakeAgenda) GetApplication(context.Context, uuid.UUID) (models.Application, error) {
+	return a.application, nil
+}
+
+type fakeProfiles struct {
+	ProfileRepository
+	members map[uuid.UUID][]string
+}
+
+func (p *fakeProfiles) GetAuthorizedUsers(_ context.Context, id uuid.UUID) ([]models.UserID, error) {
+	var users []models.UserID
+	for _, firebaseID := range p.members[id] {
+		users = append(users, models.UserID{FirebaseId: firebaseID, ProfileId: id})
+	}
+	return users, nil
+}
+
+// booking sets up a service around an accepted application to an event that ended a day ago. "producer-a" and
+// "producer-b" are members of the producer, "performer" of the performer.
+func booking() (Service, *fakeRepo, uuid.UUID) {
+	producerID, performerID := uuid.New(), uuid.New()
+	event := models.Event{Model: models.Model{ID: uuid.New()}, ProducerID: producerID, Time: time.Now().Add(-24 * time.Hour)}
+	application := models.Application{
+		Model: models.Model{ID: uuid.New()}, EventRef: event.ID, PerformerID: performerID, Status: models.StatusAccepted,
+	}
+	repo := &fakeRepo{}
+	profiles := &fakeProfiles{members: map[uuid.UUID][]string{
+		producerID:  {"producer-a", "producer-b"},
+		performerID: {"performer"},
+	}}
+	return NewService(repo, &fakeAgenda{event: event, application: application}, profiles), repo, application.ID
+}
+
+func TestSubmitConcurrentReviewsFromOneSide(t *testing.T) {
+	service, repo, applicationID := booking()
+	first, err := service.Submit(context.Background(), applicationID, "producer-a", models.Review{Rating: 4})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	repo.stale = 1
+	second, err := service.Submit(context.Background(), applicationID, "producer-b", models.Review{Rating: 1})
+	if !errors.Is(err, ErrAlreadyReviewed) {
+		t.Fatalf("Submit() of a racing review error = %v, want %v", err, ErrAlreadyReviewed)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Submit() of a racing review returned review %s, want the stored %s", second.ID, first.ID)
+	}
+	if len(repo.reviews) != 1 || repo.reviews[0].Rating != 4 {
+		t.Errorf("stored reviews = %+v, want only the first", repo.reviews)
+	}
+}
+
+func TestSubmitPublishesOnceBothSidesReviewed(t *testing.T) {
+	service, repo, applicationID := booking()
+	agenda := service.agenda.(*fakeAgenda)
+	closes := agenda.event.Time.Add(models.ReviewWindow)
+
+	first, err := service.Submit(context.Background(), applicationID, "producer-a", models.Review{Rating: 5})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if first.Direction != models.ProducerReviewsPerformer || first.SubjectProfileID != agenda.application.PerformerID {
+		t.Errorf("producer review = %s about %s, want a review of the performer", first.Direction, first.SubjectProfileID)
+	}
+	if !first.PublishedAt.Equal(closes) || !repo.reviews[0].PublishedAt.Equal(closes) {
+		t.Errorf("a one-sided review is published at %v, want the end of the window %v", first.PublishedAt, closes)
+	}
+
+	second, err := service.Submit(context.Background(), applicationID, "performer", models.Review{Rating: 3})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if second.Direction != models.PerformerReviewsProducer || second.SubjectProfileID != agenda.event.ProducerID {
+		t.Errorf("performer review = %s about %s, want a review of the producer", second.Direction, second.SubjectProfileID)
+	}
+	for _, review := range repo.reviews {
+		if !review.PublishedAt.Before(closes) || review.PublishedAt.After(time.Now()) {
+			t.Errorf("%s review is published at %v, want now that both sides reviewed", review.Direction, review.PublishedAt)
+		}
+	}
+}
+
+func TestSubmitRefused(t *testing.T) {
+	tests := []struct {
+		name       string
+		firebaseID string
+		rating     int
+		setup      func(agenda *fakeAgenda)
+		wantErr    error
+	}{
+		{name: "rating too low", firebaseID: "performer", rating: 0, wantErr: ErrInvalidRating},
+		{name: "rating too high", firebaseID: "performer", rating: 6, wantErr: ErrInvalidRating},
+		{name: "outsider", firebaseID: "someone", rating: 3, wantErr: ErrNotParticipant},
+		{
+			name: "not accepted", firebaseID: "performer", rating: 3, wantErr: ErrNotReviewable,
+			setup: func(agenda *fakeAgenda) { agenda.application.Status = models.StatusRejected },
+		},
+		{
+			name: "event still ahead", firebaseID: "performer", rating: 3, wantErr: ErrEventNotOver,
+			setup: func(agenda *fakeAgenda) { agenda.event.Time = time.Now().Add(time.Hour) },
+		},
+		{
+			name: "window closed", firebaseID: "performer", rating: 3, wantErr: ErrWindowClosed,
+			setup: func(agenda *fakeAgenda) { agenda.event.Time = time.Now().Add(-models.ReviewWindow - time.Hour) },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, repo, applicationID := booking()
+			if test.setup != nil {
+				test.setup(service.agenda.(*fakeAgenda))
+			}
+			_, err := service.Submit(context.Background(), applicationID, test.firebaseID, models.Review{Rating: test.rating})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Submit() error = %v, want %v", err, test.wantErr)
+			}
+			if len(repo.reviews) != 0 {
+				t.Errorf("a refused review was stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package settlement
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// GetRevenue returns a zero value when no revenue has been recorded for the event.
+	GetRevenue(ctx context.Context, eventID uuid.UUID) (models.EventRevenue, error)
+	UpsertRevenue(ctx context.Context, revenue models.EventRevenue) (models.EventRevenue, error)
+
+	CreatePayment(ctx context.Context, payment models.Payment) (uuid.UUID, error)
+	GetPaymentsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Payment, error)
+	GetPaymentsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Payment, error)
+}
+
+type AgendaRepository interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplication(ctx context.Context, id uuid.UUID) (models.Application, error)
+	GetApplicationsByEvent(ctx context.Context, eventID uuid.UUID) ([]models.Application, error)
+	GetApplicationsByPerformer(ctx context.Context, performerID uuid.UUID) ([]models.Application, error)
+	// GetEventUnscoped and GetApplicationsByEventUnscoped also find an event that is in the trash, so what a performer
+	// was owed or paid for it still shows in their balance.
+	GetEventUnscoped(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetApplicationsByEventUnscoped(ctx context.Context, eventID uuid.UUID) ([]models.Application, error)
+}
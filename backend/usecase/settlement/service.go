@@ -0,0 +1,243 @@
+package settlement
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotAccepted   = domain.Conflict("application_not_accepted", "payments can only be recorded against accepted applications")
+	ErrWrongEvent    = domain.Validation("wrong_event", "application does not belong to this event")
+	ErrInvalidAmount = domain.Validation("invalid_amount", "payment amount must not be zero")
+	ErrCurrency      = domain.Validation("currency_mismatch", "payment currency must match the event's pay currency")
+	ErrInvalidMethod = domain.Validation("invalid_payment_method", "invalid payment method")
+)
+
+type Service struct {
+	repo   Repository
+	agenda AgendaRepository
+}
+
+func NewService(repository Repository, agenda AgendaRepository) Service {
+	return Service{repo: repository, agenda: agenda}
+}
+
+func (s *Service) RecordRevenue(ctx context.Context, revenue models.EventRevenue) (models.EventRevenue, error) {
+	event, err := s.agenda.GetEvent(ctx, revenue.EventRef)
+	if err != nil {
+		return revenue, errors.Wrap(err, "db error")
+	}
+	if revenue.Currency == "" {
+		revenue.Currency = event.Pay.Currency
+	}
+	revenue.Currency = strings.ToUpper(revenue.Currency)
+	if (revenue.DoorRevenue != nil && *revenue.DoorRevenue < 0) || (revenue.TicketsSold != nil && *revenue.TicketsSold < 0) {
+		return revenue, domain.Validation("invalid_revenue", "revenue must not be negative")
+	}
+	out, err := s.repo.UpsertRevenue(ctx, revenue)
+	if err != nil {
+		return revenue, errors.Wrap(err, "db error")
+	}
+	return out, nil
+}
+
+func (s *Service) RecordPayment(ctx context.Context, eventID uuid.UUID, payment models.Payment) (uuid.UUID, error) {
+	if payment.Amount == 0 {
+		return uuid.Nil, ErrInvalidAmount
+	}
+	method, err := models.ParsePaymentMethod(payment.Method.String())
+	if err != nil {
+		return uuid.Nil, errors.WithMessage(ErrInvalidMethod, err.Error())
+	}
+	application, err := s.agenda.GetApplication(ctx, payment.ApplicationID)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "db error")
+	}
+	if application.EventRef != eventID {
+		return uuid.Nil, ErrWrongEvent
+	}
+	if application.Status != models.StatusAccepted {
+		return uuid.Nil, ErrNotAccepted
+	}
+	event, err := s.agenda.GetEvent(ctx, eventID)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "db error")
+	}
+
+	payment.Method = method
+	payment.EventRef = eventID
+	payment.PerformerID = application.PerformerID
+	if payment.Currency == "" {
+		payment.Currency = event.Pay.Currency
+	}
+	payment.Currency = strings.ToUpper(payment.Currency)
+	if event.Pay.Currency != "" && payment.Currency != event.Pay.Currency {
+		return uuid.Nil, ErrCurrency
+	}
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now().UTC()
+	}
+	id, err := s.repo.CreatePayment(ctx, payment)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "db error")
+	}
+	return id, nil
+}
+
+func (s *Service) GetEventSettlement(ctx context.Context, eventID uuid.UUID) (models.EventSettlement, error) {
+	event, err := s.agenda.GetEvent(ctx, eventID)
+	if err != nil {
+		return models.EventSettlement{}, errors.Wrap(err, "db error")
+	}
+	applications, err := s.agenda.GetApplicationsByEvent(ctx, eventID)
+	if err != nil {
+		return models.EventSettlement{}, errors.Wrap(err, "db error")
+	}
+	revenue, err := s.repo.GetRevenue(ctx, eventID)
+	if err != nil {
+		return models.EventSettlement{}, errors.Wrap(err, "db error")
+	}
+	payments, err := s.repo.GetPaymentsByEvent(ctx, eventID)
+	if err != nil {
+		return models.EventSettlement{}, errors.Wrap(err, "db error")
+	}
+
+	settlement := models.EventSettlement{EventRef: eventID, Pay: event.Pay}
+	if revenue.ID != uuid.Nil {
+		settlement.Revenue = &revenue
+	}
+	settlement.Lines = settle(event, applications, revenue, payments)
+	settlement.Totals = models.SumByCurrency(settlement.Lines)
+	return settlement, nil
+}
+
+// GetPerformerBalance reports what the performer is owed across every event they were accepted to, and what they were
+// paid for applications that have since stopped being accepted, including events that are in the trash or purged.
+func (s *Service) GetPerformerBalance(ctx context.Context, performerID uuid.UUID) (models.PerformerBalance, error) {
+	applications, err := s.agenda.GetApplicationsByPerformer(ctx, performerID)
+	if err != nil {
+		return models.PerformerBalance{}, errors.Wrap(err, "db error")
+	}
+	payments, err := s.repo.GetPaymentsByPerformer(ctx, performerID)
+	if err != nil {
+		return models.PerformerBalance{}, errors.Wrap(err, "db error")
+	}
+
+	var eventIDs []uuid.UUID
+	paymentsByEvent := map[uuid.UUID][]models.Payment{}
+	for _, application := range accepted(applications) {
+		if _, ok := paymentsByEvent[application.EventRef]; !ok {
+			eventIDs = append(eventIDs, application.EventRef)
+			paymentsByEvent[application.EventRef] = nil
+		}
+	}
+	for _, payment := range payments {
+		if _, ok := paymentsByEvent[payment.EventRef]; !ok {
+			eventIDs = append(eventIDs, payment.EventRef)
+		}
+		paymentsByEvent[payment.EventRef] = append(paymentsByEvent[payment.EventRef], payment)
+	}
+
+	balance := models.PerformerBalance{PerformerID: performerID, Lines: []models.SettlementLine{}}
+	for _, eventID := range eventIDs {
+		// Events in the trash still count. A purged one is gone with its applications, but its payments are kept,
+		// and they still show.
+		var acts []models.Application
+		event, err := s.agenda.GetEventUnscoped(ctx, eventID)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			event = models.Event{Model: models.Model{ID: eventID}}
+		case err != nil:
+			return balance, errors.Wrap(err, "db error")
+		default:
+			if acts, err = s.agenda.GetApplicationsByEventUnscoped(ctx, eventID); err != nil {
+				return balance, errors.Wrap(err, "db error")
+			}
+		}
+		revenue, err := s.repo.GetRevenue(ctx, eventID)
+		if err != nil {
+			return balance, errors.Wrap(err, "db error")
+		}
+		for _, line := range settle(event, acts, revenue, paymentsByEvent[eventID]) {
+			if line.PerformerID == performerID {
+				balance.Lines = append(balance.Lines, line)
+			}
+		}
+	}
+	balance.Totals = models.SumByCurrency(balance.Lines)
+	return balance, nil
+}
+
+func accepted(applications []models.Application) []models.Application {
+	out := make([]models.Application, 0, len(applications))
+	for _, application := range applications {
+		if application.Status == models.StatusAccepted {
+			out = append(out, application)
+		}
+	}
+	return out
+}
+
+// settle computes a line for every accepted application of the event, and for every other payment in the ledger so
+// money paid against an application that has since stopped being accepted, or was deleted, is never hidden. Those
+// lines expect nothing, so what was paid shows up as a negative outstanding amount.
+func settle(
+	event models.Event, applications []models.Application, revenue models.EventRevenue, payments []models.Payment,
+) []models.SettlementLine {
+	acts := accepted(applications)
+	totalShares := 0
+	for _, application := range acts {
+		totalShares += event.Pay.Shares(len(application.Members))
+	}
+
+	lines := make([]models.SettlementLine, 0, len(acts))
+	index := map[uuid.UUID]int{}
+	for _, application := range acts {
+		expected, known := event.Pay.ExpectedPayout(
+			revenue.DoorRevenue, revenue.TicketsSold, event.Pay.Shares(len(application.Members)), totalShares,
+		)
+		index[application.ID] = len(lines)
+		lines = append(lines, models.SettlementLine{
+			ApplicationID: application.ID,
+			EventRef:      event.ID,
+			PerformerID:   application.PerformerID,
+			Name:          application.Name,
+			Currency:      event.Pay.Currency,
+			Expected:      expected,
+			ExpectedKnown: known,
+			Payments:      []models.Payment{},
+		})
+	}
+
+	names := map[uuid.UUID]string{}
+	for _, application := range applications {
+		names[application.ID] = application.Name
+	}
+	for _, payment := range payments {
+		i, ok := index[payment.ApplicationID]
+		if !ok {
+			i = len(lines)
+			index[payment.ApplicationID] = i
+			lines = append(lines, models.SettlementLine{
+				ApplicationID: payment.ApplicationID,
+				EventRef:      event.ID,
+				PerformerID:   payment.PerformerID,
+				Name:          names[payment.ApplicationID],
+				Currency:      payment.Currency,
+				ExpectedKnown: true,
+				Payments:      []models.Payment{},
+			})
+		}
+		lines[i].Payments = append(lines[i].Payments, payment)
+		lines[i].Paid += payment.Amount
+	}
+	for i := range lines {
+		lines[i].Outstanding = lines[i].Expected - lines[i].Paid
+	}
+	return lines
+}
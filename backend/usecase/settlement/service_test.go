@@ -0,0 +1,111 @@
+package settlement
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"testing"
+)
+
+// fakeRepo holds events, some of them trashed, with their applications and payments. Methods the tests do not reach
+// are left to the embedded nil interfaces.
+type fakeRepo struct {
+	Repository
+	AgendaRepository
+	events       map[uuid.UUID]models.Event
+	trashed      map[uuid.UUID]bool
+	applications []models.Application
+	payments     []models.Payment
+}
+
+func (r *fakeRepo) GetEvent(_ context.Context, id uuid.UUID) (models.Event, error) {
+	event, ok := r.events[id]
+	if !ok || r.trashed[id] {
+		return event, domain.ErrNotFound
+	}
+	return event, nil
+}
+
+func (r *fakeRepo) GetEventUnscoped(_ context.Context, id uuid.UUID) (models.Event, error) {
+	event, ok := r.events[id]
+	if !ok {
+		return event, domain.ErrNotFound
+	}
+	return event, nil
+}
+
+func (r *fakeRepo) GetApplicationsByEventUnscoped(_ context.Context, eventID uuid.UUID) ([]models.Application, error) {
+	var out []models.Application
+	for _, application := range r.applications {
+		if application.EventRef == eventID {
+			out = append(out, application)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetApplicationsByPerformer(_ context.Context, performerID uuid.UUID) ([]models.Application, error) {
+	var out []models.Application
+	for _, application := range r.applications {
+		if application.PerformerID == performerID {
+			out = append(out, application)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetPaymentsByPerformer(_ context.Context, performerID uuid.UUID) ([]models.Payment, error) {
+	var out []models.Payment
+	for _, payment := range r.payments {
+		if payment.PerformerID == performerID {
+			out = append(out, payment)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetRevenue(context.Context, uuid.UUID) (models.EventRevenue, error) {
+	return models.EventRevenue{}, nil
+}
+
+func TestGetPerformerBalanceKeepsTrashedEvents(t *testing.T) {
+	performerID := uuid.New()
+	pay := models.PayStructure{Type: models.PayFlatFee, Basis: models.PerAct, Currency: "USD", FlatFee: 10000}
+	live := models.Event{Model: models.Model{ID: uuid.New()}, Pay: pay}
+	trashed := models.Event{Model: models.Model{ID: uuid.New()}, Pay: pay}
+	purgedID := uuid.New()
+	liveApplication := models.Application{
+		Model: models.Model{ID: uuid.New()}, EventRef: live.ID, PerformerID: performerID, Status: models.StatusAccepted,
+	}
+	trashedApplication := models.Application{
+		Model: models.Model{ID: uuid.New()}, EventRef: trashed.ID, PerformerID: performerID, Status: models.StatusAccepted,
+	}
+	repo := &fakeRepo{
+		events:       map[uuid.UUID]models.Event{live.ID: live, trashed.ID: trashed},
+		trashed:      map[uuid.UUID]bool{trashed.ID: true},
+		applications: []models.Application{liveApplication, trashedApplication},
+		payments: []models.Payment{
+			{ApplicationID: trashedApplication.ID, EventRef: trashed.ID, PerformerID: performerID, Amount: 4000, Currency: "USD"},
+			{ApplicationID: uuid.New(), EventRef: purgedID, PerformerID: performerID, Amount: 2500, Currency: "USD"},
+		},
+	}
+	service := NewService(repo, repo)
+
+	balance, err := service.GetPerformerBalance(context.Background(), performerID)
+	if err != nil {
+		t.Fatalf("GetPerformerBalance() error = %v", err)
+	}
+	want := map[uuid.UUID]int64{live.ID: 10000, trashed.ID: 6000, purgedID: -2500}
+	if len(balance.Lines) != len(want) {
+		t.Fatalf("GetPerformerBalance() has %d lines, want %d: %+v", len(balance.Lines), len(want), balance.Lines)
+	}
+	for _, line := range balance.Lines {
+		if outstanding, ok := want[line.EventRef]; !ok || line.Outstanding != outstanding {
+			t.Errorf("line for event %s is outstanding %d, want %d", line.EventRef, line.Outstanding, outstanding)
+		}
+	}
+	if len(balance.Totals) != 1 || balance.Totals[0].Outstanding != 13500 {
+		t.Errorf("GetPerformerBalance() totals = %+v, want 13500 USD outstanding", balance.Totals)
+	}
+}
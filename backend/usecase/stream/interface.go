@@ -0,0 +1,16 @@
+package stream
+
+import (
+	"backend/models"
+	"context"
+)
+
+// Broker fans stream events out to subscribers. Subscriptions end when ctx is done, after which the channel is closed.
+type Broker interface {
+	Publish(ctx context.Context, event models.StreamEvent) error
+	Subscribe(ctx context.Context) (<-chan models.StreamEvent, error)
+}
+
+type ProfileRepository interface {
+	GetProfilesByFirebaseId(ctx context.Context, firebaseID string) ([]models.Profile, error)
+}
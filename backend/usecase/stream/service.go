@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// MembershipRefresh is how often a subscription reloads the profiles its user is a member of, so a member who is
+// removed stops receiving a profile's events without reconnecting.
+const MembershipRefresh = 30 * time.Second
+
+type Service struct {
+	broker   Broker
+	profiles ProfileRepository
+	refresh  time.Duration
+}
+
+func NewService(broker Broker, profiles ProfileRepository) Service {
+	return Service{broker: broker, profiles: profiles, refresh: MembershipRefresh}
+}
+
+func (s *Service) Publish(ctx context.Context, event models.StreamEvent) error {
+	if err := s.broker.Publish(ctx, event); err != nil {
+		return errors.Wrap(err, "broker publish error")
+	}
+	return nil
+}
+
+// SubscribeAll returns every event regardless of audience. Only super users should reach this.
+func (s *Service) SubscribeAll(ctx context.Context) (<-chan models.StreamEvent, error) {
+	events, err := s.broker.Subscribe(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "broker subscribe error")
+	}
+	return events, nil
+}
+
+// Subscribe returns the events addressed to any profile the firebase user is a member of. Memberships are reloaded
+// every MembershipRefresh; the subscription ends if that fails.
+func (s *Service) Subscribe(ctx context.Context, firebaseID string) (<-chan models.StreamEvent, error) {
+	allowed, err := s.memberOf(ctx, firebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.broker.Subscribe(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "broker subscribe error")
+	}
+	out := make(chan models.StreamEvent)
+	go func() {
+		defer close(out)
+		refresh := time.NewTicker(s.refresh)
+		defer refresh.Stop()
+		for {
+			select {
+			case <-refresh.C:
+				reloaded, err := s.memberOf(ctx, firebaseID)
+				if err != nil {
+					return
+				}
+				allowed = reloaded
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if !visible(event, allowed) {
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (s *Service) memberOf(ctx context.Context, firebaseID string) (map[uuid.UUID]struct{}, error) {
+	profiles, err := s.profiles.GetProfilesByFirebaseId(ctx, firebaseID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	allowed := make(map[uuid.UUID]struct{}, len(profiles))
+	for _, profile := range profiles {
+		allowed[profile.ID] = struct{}{}
+	}
+	return allowed, nil
+}
+
+func visible(event models.StreamEvent, allowed map[uuid.UUID]struct{}) bool {
+	for _, id := range event.Audience {
+		if _, ok := allowed[id]; ok {
+			return true
+		}
+	}
+	return false
+}
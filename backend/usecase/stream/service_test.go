@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBroker struct {
+	Broker
+	events chan models.StreamEvent
+}
+
+func (b *fakeBroker) Subscribe(context.Context) (<-chan models.StreamEvent, error) {
+	return b.events, nil
+}
+
+// fakeProfiles returns the current memberships and counts how often they were loaded.
+type fakeProfiles struct {
+	mu       sync.Mutex
+	profiles []models.Profile
+	loads    int
+}
+
+func (p *fakeProfiles) GetProfilesByFirebaseId(context.Context, string) ([]models.Profile, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loads++
+	return p.profiles, nil
+}
+
+func (p *fakeProfiles) set(profiles ...models.Profile) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profiles = profiles
+	return p.loads
+}
+
+func (p *fakeProfiles) loaded() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loads
+}
+
+func TestSubscribeDropsRevokedMemberships(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kept := models.Profile{Model: models.Model{ID: uuid.New()}}
+	revoked := models.Profile{Model: models.Model{ID: uuid.New()}}
+	broker := &fakeBroker{events: make(chan models.StreamEvent)}
+	profiles := &fakeProfiles{profiles: []models.Profile{kept, revoked}}
+	service := NewService(broker, profiles)
+	service.refresh = time.Millisecond
+
+	out, err := service.Subscribe(ctx, "member")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	receive := func() models.StreamEvent {
+		t.Helper()
+		select {
+		case event := <-out:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no event received")
+			return models.StreamEvent{}
+		}
+	}
+
+	broker.events <- models.StreamEvent{Type: models.StreamMessageCreated, Audience: []uuid.UUID{revoked.ID}}
+	if got := receive(); got.Audience[0] != revoked.ID {
+		t.Fatalf("received %v, want the event for the profile the user is a member of", got.Audience)
+	}
+
+	// Two loads after the change guarantee the subscription filters with the new memberships.
+	before := profiles.set(kept)
+	for deadline := time.Now().Add(time.Second); profiles.loaded() < before+2; {
+		if time.Now().After(deadline) {
+			t.Fatal("memberships were not reloaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	broker.events <- models.StreamEvent{Type: models.StreamMessageCreated, Audience: []uuid.UUID{revoked.ID}}
+	broker.events <- models.StreamEvent{Type: models.StreamMessageCreated, Audience: []uuid.UUID{kept.ID}}
+	if got := receive(); got.Audience[0] != kept.ID {
+		t.Errorf("received an event for %v after the membership was revoked", got.Audience)
+	}
+}
@@ -0,0 +1,30 @@
+package trash
+
+import (
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// GetTrash lists the soft-deleted events, applications and profiles owned by the profile, newest first.
+	GetTrash(ctx context.Context, profileID uuid.UUID) ([]models.TrashItem, error)
+	// GetTrashItem returns a zero value when the item is not deleted or not owned by the profile.
+	GetTrashItem(ctx context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID) (models.TrashItem, error)
+	// GetExpiredTrash lists every item deleted before cutoff.
+	GetExpiredTrash(ctx context.Context, cutoff time.Time) ([]models.TrashItem, error)
+	Restore(ctx context.Context, item models.TrashItem) error
+	// Purge hard-deletes the item together with the rows that cannot exist without it.
+	Purge(ctx context.Context, item models.TrashItem) error
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
+}
@@ -0,0 +1,147 @@
+package trash
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+)
+
+var (
+	ErrNotInTrash    = domain.NotFound("not_in_trash", "item is not in this profile's trash")
+	ErrExpired       = domain.Gone("retention_expired", "item is past its retention window and can no longer be restored")
+	ErrParentDeleted = domain.Conflict("parent_deleted", "restore the event or profile this item belongs to first")
+)
+
+type Service struct {
+	repo      Repository
+	auditor   Auditor
+	retention time.Duration
+}
+
+func NewService(repository Repository, auditor Auditor, retention time.Duration) Service {
+	if retention <= 0 {
+		retention = models.TrashRetention
+	}
+	return Service{repo: repository, auditor: auditor, retention: retention}
+}
+
+func (s *Service) withExpiry(item models.TrashItem) models.TrashItem {
+	item.ExpiresAt = item.DeletedAt.Add(s.retention)
+	return item
+}
+
+// audit records the change with the context's transaction, so a change that cannot be audited is not made either.
+func (s *Service) audit(ctx context.Context, action string, item models.TrashItem) error {
+	if err := s.auditor.Record(
+		ctx, item.Kind.String()+"."+action, item.Kind.String(), item.ID.String(), item, nil, item.ProfileIDs...,
+	); err != nil {
+		return errors.Wrapf(err, "unable to audit %s.%s %s", item.Kind, action, item.ID)
+	}
+	return nil
+}
+
+// purge hard-deletes the item and audits it in one transaction.
+func (s *Service) purge(ctx context.Context, item models.TrashItem) error {
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Purge(ctx, item); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "purge", item)
+	})
+}
+
+func (s *Service) GetTrash(ctx context.Context, profileID uuid.UUID) ([]models.TrashItem, error) {
+	items, err := s.repo.GetTrash(ctx, profileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	for i := range items {
+		items[i] = s.withExpiry(items[i])
+	}
+	return items, nil
+}
+
+func (s *Service) getItem(ctx context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID) (models.TrashItem, error) {
+	item, err := s.repo.GetTrashItem(ctx, profileID, kind, id)
+	if err != nil {
+		return item, errors.Wrap(err, "db error")
+	}
+	if item.ID == uuid.Nil {
+		return item, ErrNotInTrash
+	}
+	return s.withExpiry(item), nil
+}
+
+// Restore undeletes an item of the profile's trash while it is inside the retention window.
+func (s *Service) Restore(ctx context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID) (models.TrashItem, error) {
+	item, err := s.getItem(ctx, profileID, kind, id)
+	if err != nil {
+		return item, err
+	}
+	if time.Now().After(item.ExpiresAt) {
+		return item, ErrExpired
+	}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, item); err != nil {
+			if errors.Is(err, ErrParentDeleted) {
+				return err
+			}
+			return errors.Wrap(err, "db error")
+		}
+		return s.audit(ctx, "restore", item)
+	})
+	if err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// Purge permanently deletes an item of the profile's trash without waiting for the retention job.
+func (s *Service) Purge(ctx context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID) error {
+	item, err := s.getItem(ctx, profileID, kind, id)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, item)
+}
+
+// PurgeExpired hard-deletes everything deleted longer ago than the retention window and returns how many items it
+// purged. Items that fail are logged and retried on the next run.
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	ctx = models.ContextWithActor(ctx, models.Actor{Type: models.ActorSystem})
+	items, err := s.repo.GetExpiredTrash(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "db error")
+	}
+	purged := 0
+	for _, item := range items {
+		if err := s.purge(ctx, s.withExpiry(item)); err != nil {
+			log.Printf("unable to purge %s %s: %v", item.Kind, item.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// RunRetention calls PurgeExpired every interval until ctx is done.
+func (s *Service) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := s.PurgeExpired(ctx); err != nil {
+			log.Printf("trash retention failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("trash retention purged %d items", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
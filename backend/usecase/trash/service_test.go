@@ -0,0 +1,141 @@
+package trash
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeRepo keeps the trash in memory. Purging an ID listed in failing fails.
+type fakeRepo struct {
+	items    []models.TrashItem
+	restored []uuid.UUID
+	purged   []uuid.UUID
+	failing  map[uuid.UUID]bool
+	blocked  bool
+}
+
+func (r *fakeRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *fakeRepo) GetTrash(context.Context, uuid.UUID) ([]models.TrashItem, error) {
+	return r.items, nil
+}
+
+func (r *fakeRepo) GetTrashItem(_ context.Context, profileID uuid.UUID, kind models.TrashKind, id uuid.UUID) (models.TrashItem, error) {
+	for _, item := range r.items {
+		for _, owner := range item.ProfileIDs {
+			if item.Kind == kind && item.ID == id && owner == profileID {
+				return item, nil
+			}
+		}
+	}
+	return models.TrashItem{}, nil
+}
+
+func (r *fakeRepo) GetExpiredTrash(_ context.Context, cutoff time.Time) ([]models.TrashItem, error) {
+	var expired []models.TrashItem
+	for _, item := range r.items {
+		if item.DeletedAt.Before(cutoff) {
+			expired = append(expired, item)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakeRepo) Restore(_ context.Context, item models.TrashItem) error {
+	if r.blocked {
+		return ErrParentDeleted
+	}
+	r.restored = append(r.restored, item.ID)
+	return nil
+}
+
+func (r *fakeRepo) Purge(_ context.Context, item models.TrashItem) error {
+	if r.failing[item.ID] {
+		return errors.New("purge failed")
+	}
+	r.purged = append(r.purged, item.ID)
+	return nil
+}
+
+type fakeAuditor struct {
+	actions []string
+}
+
+func (a *fakeAuditor) Record(_ context.Context, action string, _ string, _ string, _ interface{}, _ interface{}, _ ...uuid.UUID) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
+func item(kind models.TrashKind, owner uuid.UUID, deletedAgo time.Duration) models.TrashItem {
+	return models.TrashItem{Kind: kind, ID: uuid.New(), ProfileIDs: []uuid.UUID{owner}, DeletedAt: time.Now().Add(-deletedAgo)}
+}
+
+func TestRestore(t *testing.T) {
+	owner := uuid.New()
+	recent := item(models.TrashEvent, owner, time.Hour)
+	old := item(models.TrashEvent, owner, models.TrashRetention+time.Hour)
+	tests := []struct {
+		name    string
+		profile uuid.UUID
+		kind    models.TrashKind
+		id      uuid.UUID
+		blocked bool
+		wantErr error
+	}{
+		{name: "within retention", profile: owner, kind: models.TrashEvent, id: recent.ID},
+		{name: "expired", profile: owner, kind: models.TrashEvent, id: old.ID, wantErr: ErrExpired},
+		{name: "someone else's", profile: uuid.New(), kind: models.TrashEvent, id: recent.ID, wantErr: ErrNotInTrash},
+		{name: "wrong kind", profile: owner, kind: models.TrashApplication, id: recent.ID, wantErr: ErrNotInTrash},
+		{name: "parent deleted", profile: owner, kind: models.TrashEvent, id: recent.ID, blocked: true, wantErr: ErrParentDeleted},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &fakeRepo{items: []models.TrashItem{recent, old}, blocked: test.blocked}
+			auditor := &fakeAuditor{}
+			service := NewService(repo, auditor, 0)
+			restored, err := service.Restore(context.Background(), test.profile, test.kind, test.id)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Restore() error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				if len(repo.restored) != 0 || len(auditor.actions) != 0 {
+					t.Errorf("a refused restore restored %v and audited %v", repo.restored, auditor.actions)
+				}
+				return
+			}
+			if !restored.ExpiresAt.Equal(recent.DeletedAt.Add(models.TrashRetention)) {
+				t.Errorf("ExpiresAt = %v, want the end of the retention window", restored.ExpiresAt)
+			}
+			if len(auditor.actions) != 1 || auditor.actions[0] != "event.restore" {
+				t.Errorf("audited %v, want event.restore", auditor.actions)
+			}
+		})
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	owner := uuid.New()
+	fresh := item(models.TrashApplication, owner, time.Hour)
+	expired := item(models.TrashApplication, owner, 2*time.Hour)
+	broken := item(models.TrashProfile, owner, 3*time.Hour)
+	repo := &fakeRepo{items: []models.TrashItem{fresh, expired, broken}, failing: map[uuid.UUID]bool{broken.ID: true}}
+	auditor := &fakeAuditor{}
+	service := NewService(repo, auditor, 90*time.Minute)
+
+	purged, err := service.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != 1 || len(repo.purged) != 1 || repo.purged[0] != expired.ID {
+		t.Errorf("PurgeExpired() purged %d: %v, want only %s", purged, repo.purged, expired.ID)
+	}
+	if len(auditor.actions) != 1 || auditor.actions[0] != "application.purge" {
+		t.Errorf("audited %v, want one application.purge", auditor.actions)
+	}
+}
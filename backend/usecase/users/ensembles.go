@@ -0,0 +1,170 @@
+package users
+
+import (
+	"backend/domain"
+	"backend/models"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// MaxEnsembleMembers bounds the invited and active members of one ensemble.
+const MaxEnsembleMembers = 50
+
+var (
+	ErrNotEnsemble  = domain.Validation("not_an_ensemble", "only an ensemble profile has members")
+	ErrNotSoloist   = domain.Validation("not_a_soloist", "only a performer that is not an ensemble can be a member")
+	ErrNotInvited   = domain.NotFound("invitation_not_found", "the performer has no invitation from this ensemble")
+	ErrEnsembleFull = domain.Conflict("ensemble_full", "the ensemble already has as many members as it can")
+	ErrHasMembers   = domain.Conflict("ensemble_has_members", "an ensemble with members cannot stop being one")
+	ErrIsMember     = domain.Conflict("ensemble_member", "a member of an ensemble cannot become one")
+)
+
+func (s *Service) auditMembership(
+	ctx context.Context, action string, before interface{}, after interface{}, membership models.EnsembleMember,
+) error {
+	if err := s.auditor.Record(
+		ctx, action, "ensemble_member", membership.ID.String(), before, after, membership.EnsembleID, membership.MemberID,
+	); err != nil {
+		return errors.Wrapf(err, "unable to audit %s on ensemble_member %s", action, membership.ID)
+	}
+	return nil
+}
+
+// countsTowardEnsemble tells whether a membership still has a say: declined invitations do not.
+func countsTowardEnsemble(membership models.EnsembleMember) bool {
+	return membership.ID != uuid.Nil && membership.Status != models.MembershipDeclined
+}
+
+// Invite asks a performer to join the ensemble; they are a member once their own profile accepts. Inviting an active
+// member again only changes their role, and inviting one who declined asks again.
+func (s *Service) Invite(
+	ctx context.Context, ensembleID uuid.UUID, invitation models.EnsembleInvitation,
+) (models.EnsembleMember, error) {
+	ensemble, err := s.repo.GetProfileByID(ctx, ensembleID)
+	if err != nil {
+		return models.EnsembleMember{}, errors.Wrap(err, "db error")
+	}
+	if !ensemble.Ensemble {
+		return models.EnsembleMember{}, ErrNotEnsemble
+	}
+	member, err := s.repo.GetProfileByID(ctx, invitation.MemberID)
+	if err != nil {
+		return models.EnsembleMember{}, errors.Wrap(err, "db error")
+	}
+	if member.ProfileType != models.PerformerType || member.Ensemble {
+		return models.EnsembleMember{}, ErrNotSoloist
+	}
+	previous, err := s.repo.GetMembership(ctx, ensembleID, member.ID)
+	if err != nil {
+		return models.EnsembleMember{}, errors.Wrap(err, "db error")
+	}
+	if !countsTowardEnsemble(previous) {
+		memberships, err := s.repo.GetMembers(ctx, ensembleID)
+		if err != nil {
+			return models.EnsembleMember{}, errors.Wrap(err, "db error")
+		}
+		count := 0
+		for _, membership := range memberships {
+			if countsTowardEnsemble(membership) {
+				count++
+			}
+		}
+		if count >= MaxEnsembleMembers {
+			return models.EnsembleMember{}, ErrEnsembleFull
+		}
+	}
+	membership := models.EnsembleMember{
+		EnsembleID: ensembleID, MemberID: member.ID, Role: invitation.Role, Status: models.MembershipInvited,
+	}
+	if previous.Status == models.MembershipActive {
+		membership.Status = models.MembershipActive
+	}
+	var out models.EnsembleMember
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpsertMembership(ctx, membership); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		var before interface{}
+		if previous.ID != uuid.Nil {
+			before = previous
+		}
+		return s.auditMembership(ctx, "ensemble.invite", before, out, out)
+	})
+	if err != nil {
+		return models.EnsembleMember{}, err
+	}
+	return out, nil
+}
+
+// RespondToInvitation is the member's answer to a pending invitation. Accepting makes them part of the ensemble's
+// applications and bookings; declining keeps them out until they are invited again. Accepting again once active
+// changes nothing; an active member leaves through RemoveMember.
+func (s *Service) RespondToInvitation(
+	ctx context.Context, memberID uuid.UUID, ensembleID uuid.UUID, response models.InvitationResponse,
+) (models.EnsembleMember, error) {
+	previous, err := s.repo.GetMembership(ctx, ensembleID, memberID)
+	if err != nil {
+		return previous, errors.Wrap(err, "db error")
+	}
+	if previous.Status == models.MembershipActive && response.Accept {
+		return previous, nil
+	}
+	if previous.ID == uuid.Nil || previous.Status != models.MembershipInvited {
+		return previous, ErrNotInvited
+	}
+	membership := models.EnsembleMember{
+		EnsembleID: ensembleID, MemberID: memberID, Role: previous.Role, Status: models.MembershipDeclined,
+	}
+	action := "ensemble.decline"
+	if response.Accept {
+		membership.Status, action = models.MembershipActive, "ensemble.accept"
+	}
+	var out models.EnsembleMember
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpsertMembership(ctx, membership); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.auditMembership(ctx, action, previous, out, out)
+	})
+	if err != nil {
+		return previous, err
+	}
+	return out, nil
+}
+
+// RemoveMember ends a membership or withdraws an invitation, whether the ensemble removes the member or the member
+// leaves.
+func (s *Service) RemoveMember(ctx context.Context, ensembleID uuid.UUID, memberID uuid.UUID) error {
+	previous, err := s.repo.GetMembership(ctx, ensembleID, memberID)
+	if err != nil {
+		return errors.Wrap(err, "db error")
+	}
+	if previous.ID == uuid.Nil {
+		return ErrNotInvited
+	}
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteMembership(ctx, ensembleID, memberID); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.auditMembership(ctx, "ensemble.remove", previous, nil, previous)
+	})
+}
+
+// GetMembers returns the ensemble's members and pending invitations, with the member profiles.
+func (s *Service) GetMembers(ctx context.Context, ensembleID uuid.UUID) ([]models.EnsembleMember, error) {
+	memberships, err := s.repo.GetMembers(ctx, ensembleID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return memberships, nil
+}
+
+// GetEnsembles returns the ensembles the performer belongs to or is invited to, with the ensemble profiles.
+func (s *Service) GetEnsembles(ctx context.Context, memberID uuid.UUID) ([]models.EnsembleMember, error) {
+	memberships, err := s.repo.GetEnsembles(ctx, memberID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return memberships, nil
+}
@@ -0,0 +1,192 @@
+package users
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func (r *fakeRepo) GetMembership(_ context.Context, ensembleID uuid.UUID, memberID uuid.UUID) (models.EnsembleMember, error) {
+	for _, membership := range r.memberships {
+		if membership.EnsembleID == ensembleID && membership.MemberID == memberID {
+			return membership, nil
+		}
+	}
+	return models.EnsembleMember{}, nil
+}
+
+func (r *fakeRepo) GetMembers(_ context.Context, ensembleID uuid.UUID) ([]models.EnsembleMember, error) {
+	var members []models.EnsembleMember
+	for _, membership := range r.memberships {
+		if membership.EnsembleID == ensembleID {
+			members = append(members, membership)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeRepo) UpsertMembership(_ context.Context, membership models.EnsembleMember) (models.EnsembleMember, error) {
+	for i, stored := range r.memberships {
+		if stored.EnsembleID == membership.EnsembleID && stored.MemberID == membership.MemberID {
+			r.memberships[i].Role, r.memberships[i].Status = membership.Role, membership.Status
+			return r.memberships[i], nil
+		}
+	}
+	membership.ID = uuid.New()
+	r.memberships = append(r.memberships, membership)
+	return membership, nil
+}
+
+func (r *fakeRepo) DeleteMembership(_ context.Context, ensembleID uuid.UUID, memberID uuid.UUID) error {
+	for i, stored := range r.memberships {
+		if stored.EnsembleID == ensembleID && stored.MemberID == memberID {
+			r.memberships = append(r.memberships[:i], r.memberships[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+// ensemble sets up a service around an ensemble, a soloist who could join it, another ensemble and a producer.
+func ensemble() (Service, *fakeRepo, map[string]uuid.UUID) {
+	ids := map[string]uuid.UUID{}
+	repo := &fakeRepo{profiles: map[uuid.UUID]models.Profile{}}
+	for name, profile := range map[string]models.Profile{
+		"band":     {ProfileType: models.PerformerType, Ensemble: true},
+		"other":    {ProfileType: models.PerformerType, Ensemble: true},
+		"soloist":  {ProfileType: models.PerformerType},
+		"producer": {ProfileType: models.ProducerType},
+	} {
+		profile.ID, profile.Name = uuid.New(), name
+		repo.profiles[profile.ID], ids[name] = profile, profile.ID
+	}
+	return NewService(repo, fakeAuditor{}), repo, ids
+}
+
+func TestInvite(t *testing.T) {
+	tests := []struct {
+		name       string
+		ensemble   string
+		member     string
+		previous   models.MembershipStatus
+		others     int
+		wantErr    error
+		wantStatus models.MembershipStatus
+	}{
+		{name: "soloist", ensemble: "band", member: "soloist", wantStatus: models.MembershipInvited},
+		{name: "not an ensemble", ensemble: "soloist", member: "soloist", wantErr: ErrNotEnsemble},
+		{name: "ensemble as member", ensemble: "band", member: "other", wantErr: ErrNotSoloist},
+		{name: "producer as member", ensemble: "band", member: "producer", wantErr: ErrNotSoloist},
+		{
+			name: "active member keeps their place", ensemble: "band", member: "soloist",
+			previous: models.MembershipActive, others: MaxEnsembleMembers, wantStatus: models.MembershipActive,
+		},
+		{
+			name: "declined is asked again", ensemble: "band", member: "soloist",
+			previous: models.MembershipDeclined, wantStatus: models.MembershipInvited,
+		},
+		{name: "full", ensemble: "band", member: "soloist", others: MaxEnsembleMembers, wantErr: ErrEnsembleFull},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, repo, ids := ensemble()
+			for i := 0; i < test.others; i++ {
+				repo.memberships = append(repo.memberships, models.EnsembleMember{
+					Model: models.Model{ID: uuid.New()}, EnsembleID: ids["band"], MemberID: uuid.New(),
+					Status: models.MembershipActive,
+				})
+			}
+			if test.previous != "" {
+				repo.memberships = append(repo.memberships, models.EnsembleMember{
+					Model: models.Model{ID: uuid.New()}, EnsembleID: ids["band"], MemberID: ids["soloist"],
+					Status: test.previous,
+				})
+			}
+			out, err := service.Invite(context.Background(), ids[test.ensemble],
+				models.EnsembleInvitation{MemberID: ids[test.member], Role: "drums"})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Invite() error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				return
+			}
+			if out.Status != test.wantStatus || out.Role != "drums" {
+				t.Errorf("Invite() = %s as %q, want %s as %q", out.Status, out.Role, test.wantStatus, "drums")
+			}
+		})
+	}
+}
+
+func TestInviteDeclinedDoNotCount(t *testing.T) {
+	service, repo, ids := ensemble()
+	for i := 0; i < MaxEnsembleMembers; i++ {
+		repo.memberships = append(repo.memberships, models.EnsembleMember{
+			Model: models.Model{ID: uuid.New()}, EnsembleID: ids["band"], MemberID: uuid.New(),
+			Status: models.MembershipDeclined,
+		})
+	}
+	if _, err := service.Invite(context.Background(), ids["band"], models.EnsembleInvitation{MemberID: ids["soloist"]}); err != nil {
+		t.Errorf("Invite() with only declined invitations error = %v", err)
+	}
+}
+
+func TestRespondToInvitation(t *testing.T) {
+	tests := []struct {
+		name       string
+		previous   models.MembershipStatus
+		accept     bool
+		wantErr    error
+		wantStatus models.MembershipStatus
+	}{
+		{name: "accept", previous: models.MembershipInvited, accept: true, wantStatus: models.MembershipActive},
+		{name: "decline", previous: models.MembershipInvited, wantStatus: models.MembershipDeclined},
+		{name: "accept again", previous: models.MembershipActive, accept: true, wantStatus: models.MembershipActive},
+		{name: "decline once active", previous: models.MembershipActive, wantErr: ErrNotInvited},
+		{name: "accept once declined", previous: models.MembershipDeclined, accept: true, wantErr: ErrNotInvited},
+		{name: "never invited", accept: true, wantErr: ErrNotInvited},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, repo, ids := ensemble()
+			if test.previous != "" {
+				repo.memberships = append(repo.memberships, models.EnsembleMember{
+					Model: models.Model{ID: uuid.New()}, EnsembleID: ids["band"], MemberID: ids["soloist"],
+					Role: "drums", Status: test.previous,
+				})
+			}
+			out, err := service.RespondToInvitation(context.Background(), ids["soloist"], ids["band"],
+				models.InvitationResponse{Accept: test.accept})
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("RespondToInvitation() error = %v, want %v", err, test.wantErr)
+			}
+			if stored, _ := repo.GetMembership(context.Background(), ids["band"], ids["soloist"]); test.wantErr != nil {
+				if stored.Status != test.previous {
+					t.Errorf("a refused answer changed the membership to %s", stored.Status)
+				}
+				return
+			}
+			if out.Status != test.wantStatus || out.Role != "drums" {
+				t.Errorf("RespondToInvitation() = %s as %q, want %s as %q", out.Status, out.Role, test.wantStatus, "drums")
+			}
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	service, repo, ids := ensemble()
+	repo.memberships = append(repo.memberships, models.EnsembleMember{
+		Model: models.Model{ID: uuid.New()}, EnsembleID: ids["band"], MemberID: ids["soloist"],
+		Status: models.MembershipActive,
+	})
+	if err := service.RemoveMember(context.Background(), ids["band"], ids["soloist"]); err != nil {
+		t.Fatalf("RemoveMember() error = %v", err)
+	}
+	if len(repo.memberships) != 0 {
+		t.Errorf("memberships = %+v, want none", repo.memberships)
+	}
+	if err := service.RemoveMember(context.Background(), ids["band"], ids["soloist"]); !errors.Is(err, ErrNotInvited) {
+		t.Errorf("RemoveMember() of a removed member error = %v, want %v", err, ErrNotInvited)
+	}
+}
@@ -7,9 +7,42 @@ import (
 )
 
 type Repository interface {
+	// InTransaction runs fn in a transaction that the calls it makes with the context it is given take part in.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
 	CreateProfile(ctx context.Context, profile models.Profile) (uuid.UUID, error)
 	GetProfileByID(ctx context.Context, id uuid.UUID) (models.Profile, error)
+	// UpdateProfile only writes if the row is still at profile.Version and returns it bumped; otherwise it fails with
+	// models.ErrVersionConflict. DeleteProfile checks version the same way unless it is 0.
 	UpdateProfile(ctx context.Context, profile models.Profile) (models.Profile, error)
-	DeleteProfile(ctx context.Context, id uuid.UUID) error
+	DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error
 	GetUsersByProfileId(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+	// GetAuthorizedUsers adds the admins of the profile's organization to its members, as admins of the profile with
+	// the organization admin's ID.
+	GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+	// GetAuthorizedUsersUnscoped also finds the profile when it is soft-deleted.
+	GetAuthorizedUsersUnscoped(ctx context.Context, id uuid.UUID) ([]models.UserID, error)
+	// GetProfilesByFirebaseId includes the profiles of the organizations the user administers.
+	GetProfilesByFirebaseId(ctx context.Context, firebaseID string) ([]models.Profile, error)
+	ListProfiles(ctx context.Context) ([]models.Profile, error)
+
+	// UpsertMembership creates the membership or replaces the role and status of the existing one, even if removed.
+	UpsertMembership(ctx context.Context, membership models.EnsembleMember) (models.EnsembleMember, error)
+	// GetMembership returns the zero membership if the performer was never invited or has been removed.
+	GetMembership(ctx context.Context, ensembleID uuid.UUID, memberID uuid.UUID) (models.EnsembleMember, error)
+	DeleteMembership(ctx context.Context, ensembleID uuid.UUID, memberID uuid.UUID) error
+	// GetMembers and GetEnsembles attach the profile on the other side of each membership.
+	GetMembers(ctx context.Context, ensembleID uuid.UUID) ([]models.EnsembleMember, error)
+	GetEnsembles(ctx context.Context, memberID uuid.UUID) ([]models.EnsembleMember, error)
+
+	CreateUserID(ctx context.Context, user models.UserID) (uuid.UUID, error)
+	GetUserID(ctx context.Context, id uuid.UUID) (models.UserID, error)
+	UpdatePermission(ctx context.Context, id uuid.UUID, permission models.Permission) error
+}
+
+type Auditor interface {
+	Record(
+		ctx context.Context, action string, resourceType string, resourceID string,
+		before interface{}, after interface{}, profileIDs ...uuid.UUID,
+	) error
 }
@@ -7,18 +7,45 @@ import (
 	"github.com/pkg/errors"
 )
 
+// profileImmutable are the fields a merge patch may not change; reputation is derived from reviews and the owning
+// organization is changed through the organization.
+var profileImmutable = []string{
+	"id", "created_at", "updated_at", "deleted_at", "version", "reputation", "organization_id",
+}
+
 type Service struct {
-	repo Repository
+	repo    Repository
+	auditor Auditor
+}
+
+func NewService(repository Repository, auditor Auditor) Service {
+	return Service{repo: repository, auditor: auditor}
 }
 
-func NewService(repository Repository) Service {
-	return Service{repository}
+// audit records the change with the context's transaction, so a change that cannot be audited is not made either.
+func (s *Service) audit(ctx context.Context, action string, id uuid.UUID, before interface{}, after interface{}) error {
+	if err := s.auditor.Record(ctx, action, "profile", id.String(), before, after, id); err != nil {
+		return errors.Wrapf(err, "unable to audit %s on profile %s", action, id)
+	}
+	return nil
 }
 
 func (s *Service) CreateProfile(ctx context.Context, profile models.Profile) (uuid.UUID, error) {
-	id, err := s.repo.CreateProfile(ctx, profile)
+	if err := ValidateProfile(profile); err != nil {
+		return uuid.Nil, err
+	}
+	// A profile joins an organization through the organization, which checks both sides agree.
+	profile.OrganizationID = nil
+	var id uuid.UUID
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.repo.CreateProfile(ctx, profile); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		profile.ID = id
+		return s.audit(ctx, "profile.create", id, nil, profile)
+	})
 	if err != nil {
-		return uuid.Nil, errors.Wrap(err, "db error")
+		return uuid.Nil, err
 	}
 	return id, nil
 }
@@ -30,18 +57,72 @@ func (s *Service) GetProfileByID(ctx context.Context, id uuid.UUID) (models.Prof
 	return performer, nil
 }
 func (s *Service) UpdateProfile(ctx context.Context, profile models.Profile) (models.Profile, error) {
-	if out, err := s.repo.UpdateProfile(ctx, profile); err != nil {
-		return profile, errors.Wrap(err, "db error")
-	} else {
-		return out, nil
+	if err := ValidateProfile(profile); err != nil {
+		return profile, err
 	}
-
+	previous, prevErr := s.repo.GetProfileByID(ctx, profile.ID)
+	if prevErr == nil && previous.Ensemble != profile.Ensemble {
+		// Ensembles do not nest, and an ensemble keeps being one while it has members.
+		get, conflict := s.repo.GetMembers, ErrHasMembers
+		if profile.Ensemble {
+			get, conflict = s.repo.GetEnsembles, ErrIsMember
+		}
+		memberships, err := get(ctx, profile.ID)
+		if err != nil {
+			return profile, errors.Wrap(err, "db error")
+		}
+		for _, membership := range memberships {
+			if countsTowardEnsemble(membership) {
+				return profile, conflict
+			}
+		}
+	}
+	var out models.Profile
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if out, err = s.repo.UpdateProfile(ctx, profile); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		if prevErr != nil {
+			return s.audit(ctx, "profile.update", out.ID, nil, out)
+		}
+		// reputation is derived from reviews, not part of the profile being edited
+		previous.Reputation = nil
+		return s.audit(ctx, "profile.update", out.ID, previous, out)
+	})
+	if err != nil {
+		return profile, err
+	}
+	return out, nil
 }
-func (s *Service) DeleteProfile(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteProfile(ctx, id); err != nil {
-		return errors.Wrap(err, "db error")
+
+// PatchProfile applies an RFC 7396 merge patch to the profile with the given ID. A non-zero version must match the
+// stored one.
+func (s *Service) PatchProfile(ctx context.Context, id uuid.UUID, patch []byte, version int64) (models.Profile, error) {
+	profile, err := s.repo.GetProfileByID(ctx, id)
+	if err != nil {
+		return profile, errors.Wrap(err, "db error")
 	}
-	return nil
+	if version != 0 && version != profile.Version {
+		return profile, models.ErrVersionConflict
+	}
+	if err := models.ApplyMergePatch(&profile, patch, profileImmutable...); err != nil {
+		return profile, err
+	}
+	return s.UpdateProfile(ctx, profile)
+}
+
+// DeleteProfile soft-deletes the profile; a non-zero version must match the stored one.
+func (s *Service) DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error {
+	previous, prevErr := s.repo.GetProfileByID(ctx, id)
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteProfile(ctx, id, version); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		if prevErr != nil {
+			return s.audit(ctx, "profile.delete", id, nil, nil)
+		}
+		return s.audit(ctx, "profile.delete", id, previous, nil)
+	})
 }
 func (s *Service) GetUsersByProfileId(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
 	if users, err := s.repo.GetUsersByProfileId(ctx, id); err != nil {
@@ -50,3 +131,101 @@ func (s *Service) GetUsersByProfileId(ctx context.Context, id uuid.UUID) ([]mode
 		return users, nil
 	}
 }
+
+// GetAuthorizedUsers returns everyone who may act for the profile: its own members and the admins of the organization
+// that owns it, who count as admins of the profile.
+func (s *Service) GetAuthorizedUsers(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
+	users, err := s.repo.GetAuthorizedUsers(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return users, nil
+}
+
+// GetAuthorizedUsersUnscoped is GetAuthorizedUsers for a profile that may be in the trash.
+func (s *Service) GetAuthorizedUsersUnscoped(ctx context.Context, id uuid.UUID) ([]models.UserID, error) {
+	users, err := s.repo.GetAuthorizedUsersUnscoped(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return users, nil
+}
+func (s *Service) GetProfilesByFirebaseId(ctx context.Context, firebaseID string) ([]models.Profile, error) {
+	if profiles, err := s.repo.GetProfilesByFirebaseId(ctx, firebaseID); err != nil {
+		return nil, errors.Wrap(err, "error getting profiles from repo")
+	} else {
+		return profiles, nil
+	}
+}
+
+// ListProfiles returns every profile that is not deleted, oldest first.
+func (s *Service) ListProfiles(ctx context.Context) ([]models.Profile, error) {
+	profiles, err := s.repo.ListProfiles(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "db error")
+	}
+	return profiles, nil
+}
+
+// AddMember lets the firebase user act on the profile with the given permission.
+func (s *Service) AddMember(
+	ctx context.Context, profileID uuid.UUID, firebaseID string, permission models.Permission,
+) (models.UserID, error) {
+	user := models.UserID{FirebaseId: firebaseID, Permissions: permission, ProfileId: profileID}
+	if err := validateMember(user); err != nil {
+		return user, err
+	}
+	if _, err := s.repo.GetProfileByID(ctx, profileID); err != nil {
+		return user, errors.Wrap(err, "db error")
+	}
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) (err error) {
+		if user.ID, err = s.repo.CreateUserID(ctx, user); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.auditMember(ctx, "member.add", nil, user)
+	})
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func (s *Service) GetMember(ctx context.Context, userID uuid.UUID) (models.UserID, error) {
+	user, err := s.repo.GetUserID(ctx, userID)
+	if err != nil {
+		return user, errors.Wrap(err, "db error")
+	}
+	return user, nil
+}
+
+// SetPermission changes what a member may do on its profile. Revoking a permission sets PermissionUnknown.
+func (s *Service) SetPermission(ctx context.Context, userID uuid.UUID, permission models.Permission) (models.UserID, error) {
+	previous, err := s.repo.GetUserID(ctx, userID)
+	if err != nil {
+		return previous, errors.Wrap(err, "db error")
+	}
+	user := previous
+	user.Permissions = permission
+	if err := validateMember(user); err != nil {
+		return previous, err
+	}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdatePermission(ctx, userID, permission); err != nil {
+			return errors.Wrap(err, "db error")
+		}
+		return s.auditMember(ctx, "member.permission", previous, user)
+	})
+	if err != nil {
+		return previous, err
+	}
+	return user, nil
+}
+
+func (s *Service) auditMember(ctx context.Context, action string, before interface{}, user models.UserID) error {
+	if err := s.auditor.Record(
+		ctx, action, "user_id", user.ID.String(), before, user, user.ProfileId,
+	); err != nil {
+		return errors.Wrapf(err, "unable to audit %s on user_id %s", action, user.ID)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package users
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+// fakeRepo keeps profiles and ensemble memberships in memory and versions profiles like the database does. Methods the
+// tests do not reach are left to the embedded nil Repository.
+type fakeRepo struct {
+	Repository
+	profiles    map[uuid.UUID]models.Profile
+	memberships []models.EnsembleMember
+	updates     int
+}
+
+func (r *fakeRepo) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *fakeRepo) GetProfileByID(_ context.Context, id uuid.UUID) (models.Profile, error) {
+	profile, ok := r.profiles[id]
+	if !ok {
+		return profile, errors.New("not found")
+	}
+	return profile, nil
+}
+
+func (r *fakeRepo) UpdateProfile(_ context.Context, profile models.Profile) (models.Profile, error) {
+	if r.profiles[profile.ID].Version != profile.Version {
+		return profile, models.ErrVersionConflict
+	}
+	profile.Version++
+	r.profiles[profile.ID] = profile
+	r.updates++
+	return profile, nil
+}
+
+type fakeAuditor struct{}
+
+func (fakeAuditor) Record(context.Context, string, string, string, interface{}, interface{}, ...uuid.UUID) error {
+	return nil
+}
+
+func TestPatchProfileVersion(t *testing.T) {
+	id := uuid.New()
+	stored := models.Profile{Model: models.Model{ID: id}, Name: "before", ProfileType: models.PerformerType, Version: 2}
+	tests := []struct {
+		name        string
+		version     int64
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "no precondition", version: 0, wantVersion: 3},
+		{name: "current version", version: 2, wantVersion: 3},
+		{name: "stale version", version: 1, wantErr: models.ErrVersionConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &fakeRepo{profiles: map[uuid.UUID]models.Profile{id: stored}}
+			service := NewService(repo, fakeAuditor{})
+			out, err := service.PatchProfile(context.Background(), id, []byte(`{"name": "after"}`), test.version)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("PatchProfile() error = %v, want %v", err, test.wantErr)
+			}
+			if test.wantErr != nil {
+				if repo.updates != 0 {
+					t.Errorf("a stale patch was written")
+				}
+				return
+			}
+			if out.Version != test.wantVersion || out.Name != "after" {
+				t.Errorf("PatchProfile() = version %d name %q, want version %d name %q", out.Version, out.Name, test.wantVersion, "after")
+			}
+		})
+	}
+}
+
+func TestUpdateProfileLostUpdate(t *testing.T) {
+	id := uuid.New()
+	stored := models.Profile{Model: models.Model{ID: id}, Name: "before", ProfileType: models.PerformerType, Version: 1}
+	repo := &fakeRepo{profiles: map[uuid.UUID]models.Profile{id: stored}}
+	service := NewService(repo, fakeAuditor{})
+	first, second := stored, stored
+	first.Name, second.Name = "first", "second"
+	if _, err := service.UpdateProfile(context.Background(), first); err != nil {
+		t.Fatalf("first UpdateProfile() error = %v", err)
+	}
+	if _, err := service.UpdateProfile(context.Background(), second); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("second UpdateProfile() error = %v, want %v", err, models.ErrVersionConflict)
+	}
+	if got := repo.profiles[id].Name; got != "first" {
+		t.Errorf("stored name = %q, want %q", got, "first")
+	}
+}
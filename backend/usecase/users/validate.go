@@ -0,0 +1,47 @@
+package users
+
+import (
+	"backend/models"
+	"fmt"
+	"net/url"
+)
+
+const maxPortfolioLinks = 20
+
+// ValidateProfile is exported for imports, which create profiles without going through the service.
+func ValidateProfile(profile models.Profile) error {
+	var v models.Validator
+	v.Required("name", profile.Name)
+	switch profile.ProfileType {
+	case models.ProducerType, models.PerformerType, models.VenueType:
+	default:
+		v.Add("type", "invalid value %q. Allowed: producer, performer, venue", profile.ProfileType)
+	}
+	if profile.Location != nil {
+		v.Check(profile.Location.Lat >= -90 && profile.Location.Lat <= 90, "location.lat", "must be between -90 and 90")
+		v.Check(profile.Location.Lng >= -180 && profile.Location.Lng <= 180, "location.lng", "must be between -180 and 180")
+	}
+	v.Check(!profile.Ensemble || profile.ProfileType == models.PerformerType, "ensemble", "only performers can be ensembles")
+	v.Check(profile.OrganizationID == nil || profile.ProfileType != models.PerformerType, "type",
+		"must be producer or venue while the profile belongs to an organization")
+	v.Check(len(profile.PortfolioLinks) <= maxPortfolioLinks, "portfolio_links", "must have at most %d links", maxPortfolioLinks)
+	for i, link := range profile.PortfolioLinks {
+		u, err := url.Parse(link)
+		v.Check(
+			err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			fmt.Sprintf("portfolio_links[%d]", i), "must be an http or https URL",
+		)
+	}
+	return v.Err()
+}
+
+func validateMember(user models.UserID) error {
+	var v models.Validator
+	v.Required("firebase_id", user.FirebaseId)
+	switch user.Permissions {
+	case models.Admin, models.Restricted, models.PermissionUnknown:
+	default:
+		v.Add("permission", "invalid value %q. Allowed: admin, restricted, unknown", user.Permissions)
+	}
+	return v.Err()
+}
@@ -0,0 +1,102 @@
+package users
+
+import (
+	"backend/domain"
+	"backend/models"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nferruzzi/gormGIS"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// invalidFields lists the fields err names, sorted, or nil if err is nil.
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	domainErr, ok := domain.As(err)
+	if !ok || !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrInvalid)
+	}
+	var fields []string
+	for _, field := range domainErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestValidateProfile(t *testing.T) {
+	organization := uuid.New()
+	links := make(models.PortfolioLinks, maxPortfolioLinks+1)
+	for i := range links {
+		links[i] = "https://example.com"
+	}
+	tests := []struct {
+		name    string
+		profile models.Profile
+		want    []string
+	}{
+		{name: "performer", profile: models.Profile{Name: "Act", ProfileType: models.PerformerType}},
+		{
+			name:    "ensemble with links",
+			profile: models.Profile{Name: "Band", ProfileType: models.PerformerType, Ensemble: true, PortfolioLinks: models.PortfolioLinks{"http://band.example"}},
+		},
+		{name: "organization venue", profile: models.Profile{Name: "Hall", ProfileType: models.VenueType, OrganizationID: &organization}},
+		{name: "missing name and type", profile: models.Profile{Name: " "}, want: []string{"name", "type"}},
+		{
+			name:    "location off the map",
+			profile: models.Profile{Name: "Act", ProfileType: models.PerformerType, Location: &gormGIS.GeoPoint{Lat: -91, Lng: 181}},
+			want:    []string{"location.lat", "location.lng"},
+		},
+		{
+			name:    "producer ensemble",
+			profile: models.Profile{Name: "Club", ProfileType: models.ProducerType, Ensemble: true},
+			want:    []string{"ensemble"},
+		},
+		{
+			name:    "organization performer",
+			profile: models.Profile{Name: "Act", ProfileType: models.PerformerType, OrganizationID: &organization},
+			want:    []string{"type"},
+		},
+		{
+			name:    "bad links",
+			profile: models.Profile{Name: "Act", ProfileType: models.PerformerType, PortfolioLinks: models.PortfolioLinks{"https://ok.example", "ftp://x", "nope"}},
+			want:    []string{"portfolio_links[1]", "portfolio_links[2]"},
+		},
+		{
+			name:    "too many links",
+			profile: models.Profile{Name: "Act", ProfileType: models.PerformerType, PortfolioLinks: links},
+			want:    []string{"portfolio_links"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := invalidFields(t, ValidateProfile(test.profile)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ValidateProfile() fields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateMember(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.UserID
+		want []string
+	}{
+		{name: "admin", user: models.UserID{FirebaseId: "uid", Permissions: models.Admin}},
+		{name: "missing firebase id", user: models.UserID{Permissions: models.Restricted}, want: []string{"firebase_id"}},
+		{name: "unknown permission", user: models.UserID{FirebaseId: "uid", Permissions: "owner"}, want: []string{"permission"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := invalidFields(t, validateMember(test.user)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("validateMember() fields = %v, want %v", got, test.want)
+			}
+		})
+	}
+}